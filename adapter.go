@@ -1,7 +1,9 @@
 package rules
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 )
 
 // MapperFunc is a function that transforms an input of type TSource to TTarget.
@@ -9,6 +11,7 @@ type MapperFunc[TSource, TTarget any] func(TSource) TTarget
 
 // Map transforms a Rule[TTarget] into a Rule[TSource] by applying a mapping
 // function to convert TSource to TTarget before evaluation.
+// Automatically inherits domains from the wrapped rule.
 //
 // This allows combining rules that operate on different types by providing
 // appropriate mapping functions.
@@ -33,11 +36,19 @@ func Map[TSource, TTarget any](
 	rule Rule[TTarget],
 	mapper MapperFunc[TSource, TTarget],
 ) Rule[TSource] {
-	return &mappedRule[TSource, TTarget]{
+	mapped := &mappedRule[TSource, TTarget]{
 		name:   name,
 		rule:   rule,
 		mapper: mapper,
 	}
+
+	// Collect domains from the wrapped rule
+	domains := collectDomainsFromRules([]Rule[TTarget]{rule})
+	if len(domains) > 0 {
+		_ = Register(mapped, WithDomains(domains...))
+	}
+
+	return mapped
 }
 
 // mappedRule wraps a rule and applies a mapping function to the input.
@@ -49,6 +60,13 @@ type mappedRule[TSource, TTarget any] struct {
 
 func (r *mappedRule[TSource, TTarget]) Evaluate(
 	input TSource,
+) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *mappedRule[TSource, TTarget]) EvaluateContext(
+	ctx context.Context,
+	input TSource,
 ) (bool, error) {
 	if r.rule == nil {
 		return false, fmt.Errorf(
@@ -62,7 +80,7 @@ func (r *mappedRule[TSource, TTarget]) Evaluate(
 	target := r.mapper(input)
 
 	// Evaluate the underlying rule
-	satisfied, err := r.rule.Evaluate(target)
+	satisfied, err := EvaluateContext(ctx, r.rule, target)
 	if err != nil {
 		return false, fmt.Errorf(
 			"evaluating mapped rule %q: %w",
@@ -78,6 +96,40 @@ func (r *mappedRule[TSource, TTarget]) Name() string {
 	return r.name
 }
 
+// Child returns the wrapped rule (for documentation purposes).
+func (r *mappedRule[TSource, TTarget]) Child() Rule[TTarget] {
+	return r.rule
+}
+
+// SourceType returns the name of the type the rule is evaluated against.
+func (r *mappedRule[TSource, TTarget]) SourceType() string {
+	return typeName[TSource]()
+}
+
+// TargetType returns the name of the type the wrapped rule is evaluated against.
+func (r *mappedRule[TSource, TTarget]) TargetType() string {
+	return typeName[TTarget]()
+}
+
+// Kind reports that the rule maps its input for a wrapped rule.
+func (r *mappedRule[TSource, TTarget]) Kind() RuleType {
+	return RuleTypeMapped
+}
+
+// evaluateNested evaluates the wrapped rule tree on the mapped value.
+func (r *mappedRule[TSource, TTarget]) evaluateNested(
+	ev *evaluation,
+	input TSource,
+) (bool, []Result, error) {
+	if r.rule == nil {
+		return false, nil, ErrNilRule
+	}
+
+	childResult := evaluateRuleDetailed(ev.nested(), r.rule, r.mapper(input))
+
+	return childResult.Satisfied, []Result{childResult}, childResult.Error
+}
+
 func (r *mappedRule[TSource, TTarget]) Description() string {
 	return fmt.Sprintf(
 		"%s: MAPPED(%s)",
@@ -144,3 +196,12 @@ func CombineMany[TCombined any](
 ) Rule[TCombined] {
 	return And(name, mappedRules...)
 }
+
+// typeName returns a short, human-readable name for the type T.
+func typeName[T any]() string {
+	t := reflect.TypeFor[T]()
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -492,3 +493,77 @@ func ExampleCombine() {
 	fmt.Printf("Order request valid: %v\n", satisfied)
 	// Output: Order request valid: true
 }
+
+func TestMapDetailedEvaluation(t *testing.T) {
+	t.Parallel()
+
+	isAdmin := New("user is admin", func(user User) (bool, error) {
+		return user.Role == "admin", nil
+	})
+	isActive := New("user is active", func(user User) (bool, error) {
+		return user.IsActive, nil
+	})
+	userRule := And("valid user", isAdmin, isActive)
+
+	requestRule := Map("request from valid user", userRule, func(req OrderRequest) User {
+		return req.User
+	})
+
+	result := NewEvaluator(requestRule).EvaluateDetailed(OrderRequest{
+		User: User{Role: "admin", IsActive: false},
+	})
+
+	if result.Satisfied {
+		t.Error("Expected rule to not be satisfied")
+	}
+	if len(result.Children) != 1 || result.Children[0].RuleName != "valid user" {
+		t.Fatalf("Expected the wrapped rule as the only child, got %v", result.Children)
+	}
+	if len(result.Children[0].Children) != 2 {
+		t.Fatalf("Expected the wrapped tree to be evaluated, got %v", result.Children[0].Children)
+	}
+
+	unsatisfied := result.UnsatisfiedRules()
+	want := []string{"request from valid user", "valid user", "user is active"}
+	if fmt.Sprint(unsatisfied) != fmt.Sprint(want) {
+		t.Errorf("UnsatisfiedRules() = %v, want %v", unsatisfied, want)
+	}
+}
+
+func TestMapIntrospection(t *testing.T) {
+	t.Parallel()
+
+	userRule := New("user rule", func(user User) (bool, error) {
+		return true, nil
+	})
+	mapped := Map("mapped rule", userRule, func(req OrderRequest) User {
+		return req.User
+	})
+
+	if got := getRuleType(mapped); got != RuleTypeMapped {
+		t.Errorf("getRuleType() = %v, want %v", got, RuleTypeMapped)
+	}
+
+	children := getChildren(mapped)
+	if len(children) != 1 || children[0] != userRule {
+		t.Errorf("getChildren() = %v, want the wrapped rule", children)
+	}
+
+	node := buildRuleTree(mapped, nil, 0, 0)
+	if got := node.typeLabel(); got != "MAPPED(User ← OrderRequest)" {
+		t.Errorf("typeLabel() = %q, want %q", got, "MAPPED(User ← OrderRequest)")
+	}
+}
+
+func TestMapWithNilRuleDetailed(t *testing.T) {
+	t.Parallel()
+
+	mapped := Map[OrderRequest, User]("nil rule", nil, func(req OrderRequest) User {
+		return req.User
+	})
+
+	result := NewEvaluator(mapped).EvaluateDetailed(OrderRequest{})
+	if !errors.Is(result.Error, ErrNilRule) {
+		t.Errorf("Expected ErrNilRule, got %v", result.Error)
+	}
+}
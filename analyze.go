@@ -0,0 +1,430 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FindingKind classifies a problem found by Analyze.
+type FindingKind string
+
+const (
+	// FindingUnsatisfiable marks a rule that can never be satisfied.
+	FindingUnsatisfiable FindingKind = "UNSATISFIABLE"
+	// FindingTautology marks a composite rule that is always satisfied.
+	FindingTautology FindingKind = "TAUTOLOGY"
+	// FindingRedundantChild marks a child that never changes the outcome of
+	// its parent, e.g. Always in an And.
+	FindingRedundantChild FindingKind = "REDUNDANT_CHILD"
+	// FindingDuplicateChild marks a rule that appears more than once among
+	// the children of a composite.
+	FindingDuplicateChild FindingKind = "DUPLICATE_CHILD"
+	// FindingDoubleNegation marks a Not of a Not.
+	FindingDoubleNegation FindingKind = "DOUBLE_NEGATION"
+	// FindingEmptyComposite marks a composite rule without children.
+	FindingEmptyComposite FindingKind = "EMPTY_COMPOSITE"
+	// FindingNilChild marks a nil child rule.
+	FindingNilChild FindingKind = "NIL_CHILD"
+	// FindingOverlappingRows marks decision table rows that match the same
+	// input although the hit policy requires a unique match.
+	FindingOverlappingRows FindingKind = "OVERLAPPING_ROWS"
+	// FindingUnreachableRow marks a decision table row that is never
+	// selected by the hit policy.
+	FindingUnreachableRow FindingKind = "UNREACHABLE_ROW"
+	// FindingMissingRow marks an input that matches no row of a decision
+	// table without a default outcome.
+	FindingMissingRow FindingKind = "MISSING_ROW"
+	// FindingMalformedRow marks a decision table row whose conditions do
+	// not match the inputs of the table.
+	FindingMalformedRow FindingKind = "MALFORMED_ROW"
+)
+
+// Finding is a problem found in a rule tree by Analyze.
+type Finding struct {
+	Kind FindingKind
+	// Severity is SeverityError for problems that make the rule fail or
+	// return an error at runtime, SeverityWarning for likely mistakes and
+	// SeverityInfo for rules that can be simplified.
+	Severity Severity
+	// Path holds the names of the rules from the analyzed rule down to the
+	// rule the finding is about.
+	Path    []string
+	Message string
+}
+
+// String returns a human-readable representation of the finding, e.g.
+// `error [UNSATISFIABLE] order validation > premium: ...`.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Kind, strings.Join(f.Path, " > "), f.Message)
+}
+
+// Analyze inspects a rule tree without evaluating it and reports
+// contradictions, redundancy and structural problems that would otherwise
+// only surface at runtime, such as:
+//
+//   - branches that can never be satisfied, e.g. an And containing Never or
+//     both a rule and its negation
+//   - composites that are always satisfied, e.g. an Or containing Always
+//   - children that have no effect, appear twice, or are nil
+//   - double negations and composites without children
+//
+// Outcomes are derived from Always, Never and the structure of the
+// composites; the predicates of simple rules are never called. Rules with a
+// validity window (see Effective) and references (see Ref) are treated as
+// having an unknown outcome, and references are not followed. Findings of
+// child rules precede those of their parents.
+func Analyze[T any](rule Rule[T]) []Finding {
+	a := &analyzer{}
+	if isNilRule(rule) {
+		a.report(FindingNilChild, SeverityError, nil, "rule is nil")
+		return a.findings
+	}
+
+	a.analyze(rule, nil)
+	return a.findings
+}
+
+// outcome is the outcome of a rule as far as it is known without
+// evaluating it.
+type outcome int
+
+const (
+	outcomeUnknown outcome = iota
+	outcomeAlways
+	outcomeNever
+)
+
+// analyzer collects the findings of a single Analyze call.
+type analyzer struct {
+	findings []Finding
+}
+
+func (a *analyzer) report(kind FindingKind, severity Severity, path []string, format string, args ...any) {
+	a.findings = append(a.findings, Finding{
+		Kind:     kind,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// analyze reports the findings of a rule and its descendants and returns
+// the outcome of the rule.
+func (a *analyzer) analyze(rule any, path []string) outcome {
+	path = append(path[:len(path):len(path)], getRuleName(rule))
+
+	result := a.analyzeStructure(unwrapRule(rule), path)
+
+	// Rules that are not in effect are satisfied, whatever they contain
+	if _, _, ok := effectiveWindow(rule); ok {
+		return outcomeUnknown
+	}
+
+	return result
+}
+
+// analyzeStructure analyzes an unwrapped rule.
+func (a *analyzer) analyzeStructure(rule any, path []string) outcome {
+	if constant, ok := rule.(interface{ Constant() bool }); ok {
+		if constant.Constant() {
+			return outcomeAlways
+		}
+		return outcomeNever
+	}
+
+	kind := getRuleType(rule)
+	children, outcomes := a.analyzeChildren(rule, kind, path)
+
+	switch {
+	case kind == RuleTypeAnd:
+		return a.analyzeAnd(children, outcomes, path)
+	case kind == RuleTypeOr:
+		return a.analyzeOr(children, outcomes, path)
+	case kind == RuleTypeNot:
+		return a.analyzeNot(children, outcomes, path)
+	case kind == RuleTypeMapped && len(outcomes) == 1:
+		// Mapped rules have the outcome of the rule they wrap
+		return outcomes[0]
+	case kind.isQuantifier():
+		return a.analyzeQuantifier(rule, kind, outcomes, path)
+	case kind.isCollection() && len(outcomes) == 1:
+		return a.analyzeCollection(rule, kind, outcomes[0], path)
+	default:
+		return outcomeUnknown
+	}
+}
+
+// analyzeChildren analyzes the children of a rule, reporting nil and
+// duplicate children and composites without children. It returns the
+// non-nil children and their outcomes.
+func (a *analyzer) analyzeChildren(rule any, kind RuleType, path []string) ([]any, []outcome) {
+	all := getChildren(rule)
+	if len(all) == 0 && isCompositeKind(kind) {
+		severity := SeverityWarning
+		if kind == RuleTypeAnd || kind == RuleTypeOr || kind == RuleTypeNoneOf {
+			// These fail with ErrEmptyRules
+			severity = SeverityError
+		}
+		a.report(FindingEmptyComposite, severity, path, "%s rule has no children", kind)
+		return nil, nil
+	}
+
+	var (
+		children []any
+		outcomes []outcome
+		seen     = make(map[ruleIdentity]bool)
+	)
+	for i, child := range all {
+		if isNilRule(child) {
+			a.report(FindingNilChild, SeverityError, path, "child %d is nil", i+1)
+			continue
+		}
+
+		if id, ok := identify(child); ok {
+			if seen[id] {
+				a.report(FindingDuplicateChild, SeverityWarning, path, "child %q appears more than once", getRuleName(child))
+			}
+			seen[id] = true
+		}
+
+		children = append(children, child)
+		outcomes = append(outcomes, a.analyze(child, path))
+	}
+
+	return children, outcomes
+}
+
+// analyzeAnd derives the outcome of an And rule.
+func (a *analyzer) analyzeAnd(children []any, outcomes []outcome, path []string) outcome {
+	if len(children) == 0 {
+		return outcomeUnknown
+	}
+
+	for i, child := range children {
+		if outcomes[i] == outcomeNever && severityOf(child).isBlocking() {
+			a.report(FindingUnsatisfiable, SeverityError, path, "child %q is never satisfied", getRuleName(child))
+			return outcomeNever
+		}
+	}
+
+	if negated, ok := findNegatedPair(children, true); ok {
+		a.report(FindingUnsatisfiable, SeverityError, path, "contains both %q and its negation", negated)
+		return outcomeNever
+	}
+
+	if allOutcomes(outcomes, outcomeAlways) {
+		a.report(FindingTautology, SeverityWarning, path, "all children are always satisfied")
+		return outcomeAlways
+	}
+
+	for i, child := range children {
+		if outcomes[i] != outcomeUnknown {
+			a.report(FindingRedundantChild, SeverityWarning, path, "child %q cannot fail the rule and has no effect", getRuleName(child))
+		}
+	}
+
+	return outcomeUnknown
+}
+
+// analyzeOr derives the outcome of an Or rule.
+func (a *analyzer) analyzeOr(children []any, outcomes []outcome, path []string) outcome {
+	if len(children) == 0 {
+		return outcomeUnknown
+	}
+
+	for i, child := range children {
+		if outcomes[i] == outcomeAlways {
+			a.report(FindingTautology, SeverityWarning, path, "child %q is always satisfied", getRuleName(child))
+			return outcomeAlways
+		}
+	}
+
+	if negated, ok := findNegatedPair(children, false); ok {
+		a.report(FindingTautology, SeverityWarning, path, "contains both %q and its negation", negated)
+		return outcomeAlways
+	}
+
+	if allOutcomes(outcomes, outcomeNever) {
+		a.report(FindingUnsatisfiable, SeverityError, path, "all children are never satisfied")
+		return outcomeNever
+	}
+
+	for i, child := range children {
+		if outcomes[i] == outcomeNever {
+			a.report(FindingRedundantChild, SeverityWarning, path, "child %q is never satisfied and has no effect", getRuleName(child))
+		}
+	}
+
+	return outcomeUnknown
+}
+
+// analyzeNot derives the outcome of a Not rule.
+func (a *analyzer) analyzeNot(children []any, outcomes []outcome, path []string) outcome {
+	if len(children) != 1 {
+		return outcomeUnknown
+	}
+
+	if getRuleType(unwrapRule(children[0])) == RuleTypeNot {
+		inner := getChildren(unwrapRule(children[0]))
+		if len(inner) == 1 && !isNilRule(inner[0]) {
+			a.report(FindingDoubleNegation, SeverityInfo, path, "can be replaced by %q", getRuleName(inner[0]))
+		}
+	}
+
+	switch outcomes[0] {
+	case outcomeAlways:
+		return outcomeNever
+	case outcomeNever:
+		return outcomeAlways
+	default:
+		return outcomeUnknown
+	}
+}
+
+// analyzeQuantifier derives the outcome of a quantifier rule from the
+// range of children that can be satisfied.
+func (a *analyzer) analyzeQuantifier(rule any, kind RuleType, outcomes []outcome, path []string) outcome {
+	threshold := 0
+	if quantifier, ok := rule.(interface{ Threshold() int }); ok {
+		threshold = quantifier.Threshold()
+	}
+
+	// The number of satisfied children lies within [least, most]
+	least, most := 0, 0
+	for _, o := range outcomes {
+		if o == outcomeAlways {
+			least++
+		}
+		if o != outcomeNever {
+			most++
+		}
+	}
+
+	var always, never bool
+	switch kind {
+	case RuleTypeAtLeast:
+		always, never = least >= threshold, most < threshold
+	case RuleTypeExactly:
+		always, never = least == threshold && most == threshold, threshold < least || threshold > most
+	case RuleTypeAtMost:
+		always, never = most <= threshold, least > threshold
+	case RuleTypeNoneOf:
+		if len(outcomes) == 0 {
+			return outcomeUnknown
+		}
+		always, never = most == 0, least > 0
+	}
+
+	phrase := quantifierPhrase(kind, threshold)
+	switch {
+	case never:
+		a.report(FindingUnsatisfiable, SeverityError, path,
+			"requires %s its children to be satisfied, but between %d and %d of %d can be",
+			phrase, least, most, len(outcomes))
+		return outcomeNever
+	case always:
+		a.report(FindingTautology, SeverityWarning, path,
+			"requires %s its children to be satisfied, which holds for any outcome of its %d children",
+			phrase, len(outcomes))
+		return outcomeAlways
+	default:
+		return outcomeUnknown
+	}
+}
+
+// analyzeCollection derives the outcome of a collection rule whose element
+// rule has a known outcome. Outcomes that depend on the number of elements
+// stay unknown.
+func (a *analyzer) analyzeCollection(rule any, kind RuleType, element outcome, path []string) outcome {
+	threshold := 0
+	if collection, ok := rule.(interface{ Threshold() int }); ok {
+		threshold = collection.Threshold()
+	}
+
+	var always, never bool
+	switch kind {
+	case RuleTypeForAll:
+		always = element == outcomeAlways
+	case RuleTypeForAny:
+		never = element == outcomeNever
+	case RuleTypeForNone:
+		always = element == outcomeNever
+	case RuleTypeCountAtLeast:
+		always = threshold <= 0
+		never = !always && element == outcomeNever
+	}
+
+	phrase := collectionPhrase(kind, threshold)
+	switch {
+	case never:
+		a.report(FindingUnsatisfiable, SeverityError, path, "requires the element rule to be satisfied %s, but it never is", phrase)
+		return outcomeNever
+	case always:
+		a.report(FindingTautology, SeverityWarning, path, "is satisfied for any collection")
+		return outcomeAlways
+	default:
+		return outcomeUnknown
+	}
+}
+
+// isCompositeKind reports whether rules of this type combine child rules
+// and need at least one.
+func isCompositeKind(kind RuleType) bool {
+	return kind == RuleTypeAnd || kind == RuleTypeOr || kind == RuleTypeComposite || kind.isQuantifier()
+}
+
+// allOutcomes reports whether every outcome equals want.
+func allOutcomes(outcomes []outcome, want outcome) bool {
+	for _, o := range outcomes {
+		if o != want {
+			return false
+		}
+	}
+	return len(outcomes) > 0
+}
+
+// findNegatedPair looks for a child whose negation is also a child and
+// returns its name. With blockingOnly set, only children whose failure
+// blocks the parent are considered.
+func findNegatedPair(children []any, blockingOnly bool) (string, bool) {
+	ids := make(map[ruleIdentity]bool)
+	for _, child := range children {
+		if blockingOnly && !severityOf(child).isBlocking() {
+			continue
+		}
+		if id, ok := identify(unwrapRule(child)); ok {
+			ids[id] = true
+		}
+	}
+
+	for _, child := range children {
+		if blockingOnly && !severityOf(child).isBlocking() {
+			continue
+		}
+		structure := unwrapRule(child)
+		if getRuleType(structure) != RuleTypeNot {
+			continue
+		}
+		for _, negated := range getChildren(structure) {
+			if isNilRule(negated) {
+				continue
+			}
+			if id, ok := identify(unwrapRule(negated)); ok && ids[id] {
+				return getRuleName(negated), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// isNilRule reports whether a rule is nil, including typed nil pointers.
+func isNilRule(rule any) bool {
+	if rule == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(rule)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
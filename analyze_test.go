@@ -0,0 +1,206 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// hasFinding reports whether findings contain a finding of the given kind
+// about the rule at path.
+func hasFinding(findings []Finding, kind FindingKind, path string) bool {
+	for _, f := range findings {
+		if f.Kind == kind && strings.Join(f.Path, " > ") == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	positive := New("positive", func(input testInput) (bool, error) {
+		return input.value > 0, nil
+	})
+	even := New("even", func(input testInput) (bool, error) {
+		return input.value%2 == 0, nil
+	})
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+		kind FindingKind
+		path string
+	}{
+		{
+			name: "and with never",
+			rule: And("root", positive, Never[testInput]("never")),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+		{
+			name: "and with a rule and its negation",
+			rule: And("root", positive, even, Not("not positive", positive)),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+		{
+			name: "or with always",
+			rule: Or("root", positive, Always[testInput]("always")),
+			kind: FindingTautology,
+			path: "root",
+		},
+		{
+			name: "or with a rule and its negation",
+			rule: Or("root", positive, Not("not positive", positive)),
+			kind: FindingTautology,
+			path: "root",
+		},
+		{
+			name: "or with never",
+			rule: Or("root", positive, Never[testInput]("never")),
+			kind: FindingRedundantChild,
+			path: "root",
+		},
+		{
+			name: "and with always",
+			rule: And("root", positive, Always[testInput]("always")),
+			kind: FindingRedundantChild,
+			path: "root",
+		},
+		{
+			name: "nested contradiction",
+			rule: Or("root", even, And("branch", positive, Not("not always", Always[testInput]("always")))),
+			kind: FindingUnsatisfiable,
+			path: "root > branch",
+		},
+		{
+			name: "duplicate child",
+			rule: And("root", positive, even, positive),
+			kind: FindingDuplicateChild,
+			path: "root",
+		},
+		{
+			name: "double negation",
+			rule: Not("not not positive", Not("not positive", positive)),
+			kind: FindingDoubleNegation,
+			path: "not not positive",
+		},
+		{
+			name: "empty composite",
+			rule: And("root", positive, Or[testInput]("empty")),
+			kind: FindingEmptyComposite,
+			path: "root > empty",
+		},
+		{
+			name: "nil child",
+			rule: And("root", positive, nil),
+			kind: FindingNilChild,
+			path: "root",
+		},
+		{
+			name: "unreachable threshold",
+			rule: AtLeast("root", 3, positive, even),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+		{
+			name: "threshold always met",
+			rule: AtMost("root", 2, positive, even),
+			kind: FindingTautology,
+			path: "root",
+		},
+		{
+			name: "none of with always",
+			rule: NoneOf("root", positive, Always[testInput]("always")),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+		{
+			name: "mapped never",
+			rule: And("root", positive, Map("mapped", Never[int]("never"), func(input testInput) int { return input.value })),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+		{
+			name: "parallel and with never",
+			rule: ParallelAnd("root", 0, positive, Never[testInput]("never")),
+			kind: FindingUnsatisfiable,
+			path: "root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings := Analyze(tt.rule)
+			if !hasFinding(findings, tt.kind, tt.path) {
+				t.Errorf("Expected %s finding at %q, got %v", tt.kind, tt.path, findings)
+			}
+		})
+	}
+}
+
+func TestAnalyze_NoFindings(t *testing.T) {
+	t.Parallel()
+
+	positive := New("positive", func(input testInput) (bool, error) {
+		return input.value > 0, nil
+	})
+	even := New("even", func(input testInput) (bool, error) {
+		return input.value%2 == 0, nil
+	})
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+	}{
+		{name: "simple rule", rule: positive},
+		{name: "composites", rule: Or("root", And("both", positive, even), Not("not even", even))},
+		{name: "quantifier", rule: AtLeast("root", 1, positive, even)},
+		{
+			name: "non-blocking negation",
+			rule: And("root", positive, WithSeverity(Not("not positive", positive), SeverityWarning)),
+		},
+		{
+			name: "effective-dated never",
+			rule: And("root", positive, Effective(Never[testInput]("retired"), time.Time{}, effectiveJan1)),
+		},
+		{name: "reference", rule: And("root", positive, RefIn[testInput](NewRegistry(), "missing"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if findings := Analyze(tt.rule); len(findings) > 0 {
+				t.Errorf("Expected no findings, got %v", findings)
+			}
+		})
+	}
+}
+
+func TestAnalyze_NilRule(t *testing.T) {
+	t.Parallel()
+
+	findings := Analyze[testInput](nil)
+	if len(findings) != 1 || findings[0].Kind != FindingNilChild || findings[0].Severity != SeverityError {
+		t.Errorf("Expected a single nil finding, got %v", findings)
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	t.Parallel()
+
+	findings := Analyze(And("order validation", Never[testInput]("never")))
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %v", findings)
+	}
+
+	want := `error [UNSATISFIABLE] order validation: child "never" is never satisfied`
+	if got := findings[0].String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
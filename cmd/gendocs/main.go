@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"unicode"
 
 	"github.com/tobbstr/rules"
 )
@@ -20,6 +22,8 @@ type config struct {
 	description     string
 	groupByDomain   bool
 	includeMetadata bool
+	truthTables     bool
+	maxTruthRows    int
 	formats         []string
 }
 
@@ -47,6 +51,10 @@ func parseFlags() *config {
 		"Group rules by domain in documentation")
 	flag.BoolVar(&cfg.includeMetadata, "include-metadata", true,
 		"Include metadata (owner, version, etc.) in documentation")
+	flag.BoolVar(&cfg.truthTables, "truth-tables", false,
+		"Include truth tables of composite rules and write them as CSV files")
+	flag.IntVar(&cfg.maxTruthRows, "max-truth-table-rows", rules.DefaultMaxTruthTableRows,
+		"Maximum number of rows of a truth table; larger tables are left out")
 
 	var formatsFlag string
 	flag.StringVar(&formatsFlag, "formats", "markdown,html,json,mermaid",
@@ -95,10 +103,12 @@ func run(cfg *config) error {
 
 	// Document options
 	opts := rules.DocumentOptions{
-		Title:           cfg.title,
-		Description:     cfg.description,
-		GroupByDomain:   cfg.groupByDomain,
-		IncludeMetadata: cfg.includeMetadata,
+		Title:              cfg.title,
+		Description:        cfg.description,
+		GroupByDomain:      cfg.groupByDomain,
+		IncludeMetadata:    cfg.includeMetadata,
+		IncludeTruthTables: cfg.truthTables,
+		MaxTruthTableRows:  cfg.maxTruthRows,
 	}
 
 	// Generate each requested format
@@ -109,6 +119,13 @@ func run(cfg *config) error {
 		fmt.Printf("  ✓ Generated %s\n", format)
 	}
 
+	if cfg.truthTables {
+		if err := generateTruthTables(cfg, opts); err != nil {
+			return fmt.Errorf("generating truth tables: %w", err)
+		}
+		fmt.Println("  ✓ Generated truth tables")
+	}
+
 	return nil
 }
 
@@ -183,6 +200,53 @@ func generateMermaid(cfg *config, opts rules.DocumentOptions) error {
 	return nil
 }
 
+// generateTruthTables writes the truth table of each composite rule as a
+// CSV file named after the rule.
+func generateTruthTables(cfg *config, opts rules.DocumentOptions) error {
+	dir := filepath.Join(cfg.outputDir, "truth-tables")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating truth table directory: %w", err)
+	}
+
+	seen := make(map[string]int)
+	for _, table := range rules.GenerateTruthTables(opts) {
+		name := fileName(table.Rule)
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, seen[name])
+		}
+
+		filename := filepath.Join(dir, name+".csv")
+		if err := writeFile(filename, table.CSV()); err != nil {
+			return fmt.Errorf("writing truth table of %q: %w", table.Rule, err)
+		}
+	}
+
+	return nil
+}
+
+// fileName turns a rule name into a file name, e.g. "Order Total" into
+// "order-total".
+func fileName(ruleName string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(ruleName) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if sb.Len() == 0 {
+		return "rule"
+	}
+	return sb.String()
+}
+
 func writeFile(filename, content string) error {
 	file, err := os.Create(filename)
 	if err != nil {
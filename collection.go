@@ -0,0 +1,220 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+)
+
+// ForAll creates a rule that is satisfied if the rule is satisfied by every
+// element extracted from the input. It is satisfied when there are no
+// elements. Automatically inherits domains from the element rule.
+//
+// Example:
+//
+//	inStock := rules.New("item in stock", func(item LineItem) (bool, error) {
+//	    return item.Stock >= item.Quantity, nil
+//	})
+//
+//	allInStock := rules.ForAll("all items in stock", inStock,
+//	    func(o Order) []LineItem { return o.Items })
+func ForAll[T, E any](name string, rule Rule[E], elements MapperFunc[T, []E]) Rule[T] {
+	return newCollectionRule(name, RuleTypeForAll, 0, rule, elements)
+}
+
+// ForAny creates a rule that is satisfied if the rule is satisfied by at
+// least one element extracted from the input. It is not satisfied when
+// there are no elements. Automatically inherits domains from the element
+// rule.
+func ForAny[T, E any](name string, rule Rule[E], elements MapperFunc[T, []E]) Rule[T] {
+	return newCollectionRule(name, RuleTypeForAny, 0, rule, elements)
+}
+
+// ForNone creates a rule that is satisfied if the rule is satisfied by none
+// of the elements extracted from the input. It is satisfied when there are
+// no elements. Automatically inherits domains from the element rule.
+func ForNone[T, E any](name string, rule Rule[E], elements MapperFunc[T, []E]) Rule[T] {
+	return newCollectionRule(name, RuleTypeForNone, 0, rule, elements)
+}
+
+// CountAtLeast creates a rule that is satisfied if the rule is satisfied by
+// at least n of the elements extracted from the input. Automatically
+// inherits domains from the element rule.
+func CountAtLeast[T, E any](name string, n int, rule Rule[E], elements MapperFunc[T, []E]) Rule[T] {
+	return newCollectionRule(name, RuleTypeCountAtLeast, n, rule, elements)
+}
+
+// collectionRule evaluates a rule against each element of a collection
+// extracted from the input. It backs ForAll, ForAny, ForNone and
+// CountAtLeast. An element rule that is not in effect (see Effective) is
+// ignored, so the rule is decided as if there were no elements.
+type collectionRule[T, E any] struct {
+	name     string
+	kind     RuleType
+	n        int
+	rule     Rule[E]
+	elements MapperFunc[T, []E]
+}
+
+func newCollectionRule[T, E any](
+	name string,
+	kind RuleType,
+	n int,
+	rule Rule[E],
+	elements MapperFunc[T, []E],
+) Rule[T] {
+	collection := &collectionRule[T, E]{
+		name:     name,
+		kind:     kind,
+		n:        n,
+		rule:     rule,
+		elements: elements,
+	}
+
+	// Collect domains from the element rule
+	domains := collectDomainsFromRules([]Rule[E]{rule})
+	if len(domains) > 0 {
+		_ = Register(collection, WithDomains(domains...))
+	}
+
+	return collection
+}
+
+func (r *collectionRule[T, E]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *collectionRule[T, E]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if r.rule == nil {
+		return false, fmt.Errorf(
+			"evaluating %s rule %q: %w",
+			r.kind,
+			r.name,
+			ErrNilRule,
+		)
+	}
+
+	if !inEffect(ctx, r.rule) {
+		return r.outcome(0, 0), nil
+	}
+
+	elements := r.elements(input)
+
+	satisfied := 0
+	for i, element := range elements {
+		result, err := EvaluateContext(ctx, r.rule, element)
+		if err != nil {
+			return false, fmt.Errorf(
+				"evaluating %s rule %q at index %d: %w",
+				r.kind,
+				r.name,
+				i,
+				err,
+			)
+		}
+
+		if result {
+			satisfied++
+		}
+		if r.settled(satisfied, i+1) {
+			break
+		}
+	}
+
+	return r.outcome(satisfied, len(elements)), nil
+}
+
+func (r *collectionRule[T, E]) Name() string {
+	return r.name
+}
+
+// Child returns the element rule (for documentation purposes).
+func (r *collectionRule[T, E]) Child() Rule[E] {
+	return r.rule
+}
+
+// Threshold returns the number of elements that must satisfy the rule
+// (only meaningful for CountAtLeast).
+func (r *collectionRule[T, E]) Threshold() int {
+	return r.n
+}
+
+// SourceType returns the name of the type the elements are extracted from.
+func (r *collectionRule[T, E]) SourceType() string {
+	return typeName[T]()
+}
+
+// TargetType returns the name of the element type.
+func (r *collectionRule[T, E]) TargetType() string {
+	return typeName[E]()
+}
+
+// Kind reports which collection quantifier the rule implements.
+func (r *collectionRule[T, E]) Kind() RuleType {
+	return r.kind
+}
+
+// settled reports whether the outcome can no longer change once count of
+// the first evaluated elements have satisfied the rule.
+func (r *collectionRule[T, E]) settled(count, evaluated int) bool {
+	switch r.kind {
+	case RuleTypeForAll:
+		return count < evaluated
+	case RuleTypeCountAtLeast:
+		return count >= r.n
+	default:
+		// ForAny and ForNone are decided by the first satisfied element
+		return count > 0
+	}
+}
+
+// outcome returns whether the rule is satisfied when count of total
+// elements satisfy the element rule.
+func (r *collectionRule[T, E]) outcome(count, total int) bool {
+	switch r.kind {
+	case RuleTypeForAll:
+		return count == total
+	case RuleTypeForAny:
+		return count > 0
+	case RuleTypeForNone:
+		return count == 0
+	default:
+		return count >= r.n
+	}
+}
+
+// evaluateNested evaluates the element rule tree on every element. Child
+// results are labeled with the index of their element.
+func (r *collectionRule[T, E]) evaluateNested(
+	ev *evaluation,
+	input T,
+) (bool, []Result, error) {
+	if r.rule == nil {
+		return false, nil, ErrNilRule
+	}
+
+	elements := r.elements(input)
+	children := make([]Result, 0, len(elements))
+
+	satisfied, active := 0, 0
+	for i, element := range elements {
+		childResult := evaluateRuleDetailed(ev.nested(), r.rule, element)
+		childResult.RuleName = fmt.Sprintf("%s [%d]", childResult.RuleName, i)
+		children = append(children, childResult)
+		if childResult.Error != nil {
+			return false, children, childResult.Error
+		}
+		if childResult.Inactive {
+			continue
+		}
+
+		active++
+		if childResult.Satisfied {
+			satisfied++
+		}
+		if ev.shortCircuit && r.settled(satisfied, active) {
+			return r.outcome(satisfied, len(elements)), children, nil
+		}
+	}
+
+	return r.outcome(satisfied, active), children, nil
+}
@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type lineItem struct {
+	SKU      string
+	Quantity int
+}
+
+type basket struct {
+	Items []lineItem
+}
+
+func basketItems(b basket) []lineItem {
+	return b.Items
+}
+
+func TestCollectionRules(t *testing.T) {
+	t.Parallel()
+
+	positive := New("positive quantity", func(item lineItem) (bool, error) {
+		return item.Quantity > 0, nil
+	})
+	bulk := New("bulk quantity", func(item lineItem) (bool, error) {
+		return item.Quantity >= 10, nil
+	})
+
+	mixed := basket{Items: []lineItem{{"a", 1}, {"b", 12}, {"c", 0}, {"d", 20}}}
+	empty := basket{}
+
+	tests := []struct {
+		name  string
+		rule  Rule[basket]
+		input basket
+		want  bool
+	}{
+		{"ForAll not satisfied", ForAll("all positive", positive, basketItems), mixed, false},
+		{"ForAll satisfied", ForAll("all positive", positive, basketItems), basket{Items: mixed.Items[:2]}, true},
+		{"ForAll on empty", ForAll("all positive", positive, basketItems), empty, true},
+		{"ForAny satisfied", ForAny("any bulk", bulk, basketItems), mixed, true},
+		{"ForAny on empty", ForAny("any bulk", bulk, basketItems), empty, false},
+		{"ForNone not satisfied", ForNone("no bulk", bulk, basketItems), mixed, false},
+		{"ForNone on empty", ForNone("no bulk", bulk, basketItems), empty, true},
+		{"CountAtLeast satisfied", CountAtLeast("two bulk", 2, bulk, basketItems), mixed, true},
+		{"CountAtLeast not satisfied", CountAtLeast("three bulk", 3, bulk, basketItems), mixed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.rule.Evaluate(tt.input)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+
+			// Detailed evaluation must agree with Evaluate
+			evaluator := NewEvaluator(tt.rule)
+			if result := evaluator.EvaluateDetailed(tt.input); result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailed().Satisfied = %v, want %v", result.Satisfied, tt.want)
+			}
+			if result := evaluator.EvaluateDetailedShortCircuit(tt.input); result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailedShortCircuit().Satisfied = %v, want %v", result.Satisfied, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	failing := New("failing", func(item lineItem) (bool, error) {
+		if item.SKU == "b" {
+			return false, errBoom
+		}
+		return true, nil
+	})
+	input := basket{Items: []lineItem{{"a", 1}, {"b", 2}}}
+
+	_, err := ForAll("all", failing, basketItems).Evaluate(input)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected errBoom, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `evaluating FOR_ALL rule "all" at index 1`) {
+		t.Errorf("Expected error to name the element index, got %v", err)
+	}
+
+	_, err = ForAny[basket, lineItem]("nil", nil, basketItems).Evaluate(input)
+	if !errors.Is(err, ErrNilRule) {
+		t.Errorf("Expected ErrNilRule, got %v", err)
+	}
+
+	result := NewEvaluator(ForAll("all", failing, basketItems)).EvaluateDetailed(input)
+	if !errors.Is(result.Error, errBoom) || len(result.Children) != 2 {
+		t.Errorf("Expected error after 2 children, got %v", result)
+	}
+}
+
+func TestCollectionDetailedEvaluation(t *testing.T) {
+	t.Parallel()
+
+	positive := New("positive quantity", func(item lineItem) (bool, error) {
+		return item.Quantity > 0, nil
+	})
+	rule := ForAll("all positive", positive, basketItems)
+	input := basket{Items: []lineItem{{"a", 1}, {"b", 0}, {"c", 3}}}
+
+	t.Run("full evaluation labels every element", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailed(input)
+		if result.Satisfied {
+			t.Fatal("Expected rule to not be satisfied")
+		}
+
+		var names []string
+		for _, child := range result.Children {
+			names = append(names, child.RuleName)
+		}
+		want := []string{"positive quantity [0]", "positive quantity [1]", "positive quantity [2]"}
+		if fmt.Sprint(names) != fmt.Sprint(want) {
+			t.Errorf("Child names = %v, want %v", names, want)
+		}
+
+		unsatisfied := result.UnsatisfiedRules()
+		if fmt.Sprint(unsatisfied) != fmt.Sprint([]string{"all positive", "positive quantity [1]"}) {
+			t.Errorf("UnsatisfiedRules() = %v", unsatisfied)
+		}
+	})
+
+	t.Run("short-circuit stops at the deciding element", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailedShortCircuit(input)
+		if len(result.Children) != 2 {
+			t.Errorf("Expected 2 children, got %d", len(result.Children))
+		}
+	})
+}
+
+func TestCollectionIntrospection(t *testing.T) {
+	t.Parallel()
+
+	bulk := New("bulk quantity", func(item lineItem) (bool, error) {
+		return item.Quantity >= 10, nil
+	})
+	rule := CountAtLeast("two bulk", 2, bulk, basketItems)
+
+	if got := getRuleType(rule); got != RuleTypeCountAtLeast {
+		t.Errorf("getRuleType() = %v, want %v", got, RuleTypeCountAtLeast)
+	}
+	if children := getChildren(rule); len(children) != 1 || children[0] != bulk {
+		t.Errorf("getChildren() = %v, want the element rule", children)
+	}
+
+	node := buildRuleTree(rule, nil, 0, 0)
+	if got := node.typeLabel(); got != "COUNT_AT_LEAST(lineItem)" {
+		t.Errorf("typeLabel() = %q, want %q", got, "COUNT_AT_LEAST(lineItem)")
+	}
+	if got := collectionHeader(node); got != "For at least 2 items (lineItem), this condition must be satisfied:" {
+		t.Errorf("collectionHeader() = %q", got)
+	}
+}
@@ -0,0 +1,324 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// compiledFunc evaluates a compiled rule like EvaluateContext.
+type compiledFunc[T any] func(ctx context.Context, input T) (bool, error)
+
+// compiledChild is a child of a compiled composite with the properties the
+// interpreted composite looks up on every evaluation resolved up front.
+type compiledChild[T any] struct {
+	eval     compiledFunc[T]
+	nil      bool
+	blocking bool
+	// Validity window, if the child is effective-dated
+	windowed    bool
+	from, until time.Time
+}
+
+// inEffect reports whether the child is in effect at the evaluation time of
+// the context.
+func (c *compiledChild[T]) inEffect(ctx context.Context) bool {
+	return !c.windowed || withinWindow(evaluationTime(ctx), c.from, c.until)
+}
+
+// CompiledRule is a rule tree flattened into an evaluation plan of closures
+// (see Compile). It evaluates with the same outcomes and errors as the rule
+// it was compiled from, and documents and evaluates in detail as that rule.
+type CompiledRule[T any] struct {
+	rule Rule[T]
+	eval compiledFunc[T]
+}
+
+// Compile flattens a rule tree into an evaluation plan for hot paths. The
+// plan evaluates with the same outcomes, short-circuiting and errors as
+// Evaluate, but resolves the structure of the tree, the severities and the
+// validity windows of its rules once instead of on every evaluation.
+//
+// AND, OR, NOT, quantifier, constant, severity and effective-dated rules
+// and rules created with New are compiled; any other rule (e.g.
+// references, validation and user-defined rules) is evaluated as is within
+// the plan. The tree must not be modified after it is compiled.
+//
+// Example:
+//
+//	eligible := rules.Compile(rules.And("eligible", isAdult, hasAccount))
+//
+//	satisfied, err := eligible.Evaluate(customer)
+func Compile[T any](rule Rule[T]) *CompiledRule[T] {
+	if compiled, ok := rule.(*CompiledRule[T]); ok {
+		return compiled
+	}
+	return &CompiledRule[T]{
+		rule: rule,
+		eval: compileRule(rule),
+	}
+}
+
+// Name returns the name of the compiled rule.
+func (r *CompiledRule[T]) Name() string {
+	return r.rule.Name()
+}
+
+// Evaluate evaluates the plan.
+func (r *CompiledRule[T]) Evaluate(input T) (bool, error) {
+	return r.eval(context.Background(), input)
+}
+
+// EvaluateContext is like Evaluate but honors the context.
+func (r *CompiledRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	return r.eval(ctx, input)
+}
+
+// Unwrap returns the rule the plan was compiled from.
+func (r *CompiledRule[T]) Unwrap() Rule[T] {
+	return r.rule
+}
+
+// unwrapRule returns the rule the plan was compiled from for
+// introspection.
+func (r *CompiledRule[T]) unwrapRule() any {
+	return r.rule
+}
+
+// compileRule compiles a rule into a function equivalent to
+// EvaluateContext(ctx, rule, input).
+func compileRule[T any](rule Rule[T]) compiledFunc[T] {
+	switch r := rule.(type) {
+	case *CompiledRule[T]:
+		return r.eval
+	case *simpleRule[T]:
+		return compileSimple(r)
+	case *constantRule[T]:
+		value := r.value
+		return func(ctx context.Context, _ T) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			return value, nil
+		}
+	case *andRule[T]:
+		return compileAnd(r)
+	case *orRule[T]:
+		return compileOr(r)
+	case *notRule[T]:
+		return compileNot(r)
+	case *quantifierRule[T]:
+		return compileQuantifier(r.evaluated())
+	case *severityRule[T]:
+		if r.rule == nil {
+			return compileError[T](fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule))
+		}
+		return compileRule(r.rule)
+	case *effectiveRule[T]:
+		return compileEffective(r)
+	default:
+		return func(ctx context.Context, input T) (bool, error) {
+			return EvaluateContext(ctx, rule, input)
+		}
+	}
+}
+
+// compileError compiles a rule that always fails with err.
+func compileError[T any](err error) compiledFunc[T] {
+	return func(ctx context.Context, _ T) (bool, error) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return false, err
+	}
+}
+
+// compileChildren compiles the children of a composite.
+func compileChildren[T any](rules []Rule[T]) []compiledChild[T] {
+	children := make([]compiledChild[T], len(rules))
+	for i, rule := range rules {
+		if rule == nil {
+			children[i].nil = true
+			continue
+		}
+		children[i].eval = compileRule(rule)
+		children[i].blocking = severityOf(rule).isBlocking()
+		children[i].from, children[i].until, children[i].windowed = effectiveWindow(rule)
+	}
+	return children
+}
+
+// compileSimple compiles a rule created with New or NewContext.
+func compileSimple[T any](r *simpleRule[T]) compiledFunc[T] {
+	name, predicate, ctxPredicate := r.name, r.predicate, r.ctxPredicate
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		var (
+			result bool
+			err    error
+		)
+		if ctxPredicate != nil {
+			result, err = ctxPredicate(ctx, input)
+		} else {
+			result, err = predicate(input)
+		}
+		if err != nil {
+			return false, fmt.Errorf("evaluating rule %q: %w", name, err)
+		}
+		return result, nil
+	}
+}
+
+// compileAnd compiles an AND rule.
+func compileAnd[T any](r *andRule[T]) compiledFunc[T] {
+	name := r.name
+	if len(r.rules) == 0 {
+		return compileError[T](fmt.Errorf("evaluating AND rule %q: %w", name, ErrEmptyRules))
+	}
+
+	children := compileChildren(r.rules)
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		for i := range children {
+			child := &children[i]
+			if child.nil {
+				return false, fmt.Errorf("evaluating AND rule %q: %w", name, ErrNilRule)
+			}
+			if !child.inEffect(ctx) {
+				continue
+			}
+
+			satisfied, err := child.eval(ctx, input)
+			if err != nil {
+				return false, fmt.Errorf("evaluating AND rule %q: %w", name, err)
+			}
+			if !satisfied && child.blocking {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+// compileOr compiles an OR rule.
+func compileOr[T any](r *orRule[T]) compiledFunc[T] {
+	name := r.name
+	if len(r.rules) == 0 {
+		return compileError[T](fmt.Errorf("evaluating OR rule %q: %w", name, ErrEmptyRules))
+	}
+
+	children := compileChildren(r.rules)
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		for i := range children {
+			child := &children[i]
+			if child.nil {
+				return false, fmt.Errorf("evaluating OR rule %q: %w", name, ErrNilRule)
+			}
+			if !child.inEffect(ctx) {
+				continue
+			}
+
+			satisfied, err := child.eval(ctx, input)
+			if err != nil {
+				return false, fmt.Errorf("evaluating OR rule %q: %w", name, err)
+			}
+			if satisfied {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// compileNot compiles a NOT rule.
+func compileNot[T any](r *notRule[T]) compiledFunc[T] {
+	name := r.name
+	if r.rule == nil {
+		return compileError[T](fmt.Errorf("evaluating NOT rule %q: %w", name, ErrNilRule))
+	}
+
+	child := compileRule(r.rule)
+	from, until, windowed := effectiveWindow(r.rule)
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if windowed && !withinWindow(evaluationTime(ctx), from, until) {
+			return true, nil
+		}
+
+		satisfied, err := child(ctx, input)
+		if err != nil {
+			return false, fmt.Errorf("evaluating NOT rule %q: %w", name, err)
+		}
+		return !satisfied, nil
+	}
+}
+
+// compileQuantifier compiles a quantifier rule. Like EvaluateComposite, it
+// stops as soon as the outcome is settled.
+func compileQuantifier[T any](r *quantifierRule[T]) compiledFunc[T] {
+	if r.kind == RuleTypeNoneOf && len(r.rules) == 0 {
+		return compileError[T](fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, ErrEmptyRules))
+	}
+
+	children := compileChildren(r.rules)
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		count := 0
+		for i := range children {
+			child := &children[i]
+			if child.nil {
+				return false, fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, ErrNilRule)
+			}
+
+			if child.inEffect(ctx) {
+				satisfied, err := child.eval(ctx, input)
+				if err != nil {
+					return false, fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, err)
+				}
+				if satisfied {
+					count++
+				}
+			}
+
+			if r.settled(count) {
+				return r.outcome(count), nil
+			}
+		}
+		return r.outcome(count), nil
+	}
+}
+
+// compileEffective compiles an effective-dated rule, which is satisfied
+// when evaluated outside of its validity window.
+func compileEffective[T any](r *effectiveRule[T]) compiledFunc[T] {
+	if r.rule == nil {
+		return compileError[T](fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule))
+	}
+
+	from, until := r.from, r.until
+	child := compileRule(r.rule)
+	return func(ctx context.Context, input T) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if !withinWindow(evaluationTime(ctx), from, until) {
+			return true, nil
+		}
+		return child(ctx, input)
+	}
+}
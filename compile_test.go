@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	positive := New("positive", func(input testInput) (bool, error) {
+		return input.value > 0, nil
+	})
+	large := New("large", func(input testInput) (bool, error) {
+		return input.value > 100, nil
+	})
+	valid := NewContext("valid", func(_ context.Context, input testInput) (bool, error) {
+		return input.valid, nil
+	})
+	inRange := NewValidation("in range", func(input testInput) ([]Violation, error) {
+		if input.value > 100 {
+			return []Violation{{Message: "too large"}}, nil
+		}
+		return nil, nil
+	})
+	failing := New("failing", func(input testInput) (bool, error) {
+		if input.value < 0 {
+			return false, errBoom
+		}
+		return true, nil
+	})
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+	}{
+		{name: "simple", rule: positive},
+		{name: "context", rule: valid},
+		{name: "constants", rule: Or("constants", Never[testInput]("never"), Always[testInput]("always"))},
+		{name: "and", rule: And("and", positive, large, valid)},
+		{name: "or", rule: Or("or", large, valid)},
+		{name: "not", rule: Not("not", And("and", positive, valid))},
+		{name: "error", rule: And("and", positive, Or("or", failing, large))},
+		{name: "empty", rule: And[testInput]("empty")},
+		{name: "nil child", rule: Or("or", large, nil)},
+		{name: "nil none of", rule: NoneOf("none", large, nil)},
+		{name: "at least", rule: AtLeast("at least", 2, positive, large, valid)},
+		{name: "exactly", rule: Exactly("exactly", 1, positive, large, valid)},
+		{name: "at most", rule: AtMost("at most", 1, positive, large, Not("not", valid))},
+		{name: "none of", rule: NoneOf("none", large, failing)},
+		{name: "severity", rule: And("and", positive, WithSeverity(large, SeverityWarning))},
+		{
+			name: "effective",
+			rule: And("and",
+				Effective(large, time.Time{}, effectiveJan1),
+				Effective(valid, effectiveJan1, time.Time{}),
+			),
+		},
+		{name: "effective quantifier", rule: AtLeast("at least", 2, Effective(large, effectiveJan1, time.Time{}), positive)},
+		{name: "fallback", rule: And("and", positive, inRange)},
+	}
+
+	inputs := []testInput{
+		{value: -1},
+		{value: 0, valid: true},
+		{value: 50},
+		{value: 150, valid: true},
+	}
+	times := []time.Time{effectiveJan1.Add(-time.Hour), effectiveJul1}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			compiled := Compile(tt.rule)
+			if compiled.Name() != tt.rule.Name() {
+				t.Errorf("Name() = %q, want %q", compiled.Name(), tt.rule.Name())
+			}
+
+			for _, asOf := range times {
+				ctx := AsOf(context.Background(), asOf)
+				for _, input := range inputs {
+					want, wantErr := EvaluateContext(ctx, tt.rule, input)
+					got, err := compiled.EvaluateContext(ctx, input)
+					if got != want || !sameError(err, wantErr) {
+						t.Errorf("EvaluateContext(%v) as of %v = %v, %v; want %v, %v", input, asOf, got, err, want, wantErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+// sameError reports whether two errors have the same message.
+func sameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Error() == b.Error()
+}
+
+func TestCompile_Canceled(t *testing.T) {
+	t.Parallel()
+
+	compiled := Compile(And("and", Always[testInput]("always")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := compiled.EvaluateContext(ctx, testInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("EvaluateContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCompile_Transparent(t *testing.T) {
+	t.Parallel()
+
+	rule := And("eligible",
+		New("positive", func(input testInput) (bool, error) { return input.value > 0, nil }),
+		New("valid", func(input testInput) (bool, error) { return input.valid, nil }),
+	)
+	compiled := Compile(rule)
+
+	if Compile[testInput](compiled) != compiled {
+		t.Error("Expected compiling a compiled rule to return it")
+	}
+	if getRuleType(unwrapRule(compiled)) != RuleTypeAnd {
+		t.Errorf("Expected the compiled rule to unwrap to the AND rule")
+	}
+
+	result := NewEvaluator[testInput](compiled).EvaluateDetailed(testInput{value: 1})
+	if result.Satisfied || result.RuleName != "eligible" || len(result.Children) != 2 {
+		t.Errorf("Unexpected detailed result: %v", result)
+	}
+}
+
+func TestCompile_Allocations(t *testing.T) {
+	rule := Compile(Or("eligible",
+		And("large and valid",
+			New("large", func(input testInput) (bool, error) { return input.value > 100, nil }),
+			New("valid", func(input testInput) (bool, error) { return input.valid, nil }),
+		),
+		AtLeast("two of", 2,
+			New("positive", func(input testInput) (bool, error) { return input.value > 0, nil }),
+			WithSeverity(New("even", func(input testInput) (bool, error) { return input.value%2 == 0, nil }), SeverityWarning),
+			Not("invalid", New("valid", func(input testInput) (bool, error) { return input.valid, nil })),
+		),
+	))
+
+	input := testInput{value: 42}
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = rule.Evaluate(input) }); allocs != 0 {
+		t.Errorf("Evaluate() allocations = %v, want 0", allocs)
+	}
+}
@@ -0,0 +1,167 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+)
+
+// CompositeRule is implemented by rules that combine the outcomes of child
+// rules of the same input type. And, Or, Not, the quantifiers and the
+// parallel composites all implement it.
+//
+// Implementing CompositeRule makes a user-defined rule a first-class node:
+// Evaluator.EvaluateDetailed reports a result per child (stopping early in
+// short-circuit mode once Combine reports the outcome as decided), and the
+// documenters render its kind and children.
+//
+// Example:
+//
+//	// majority is satisfied when more than half of its children are.
+//	type majority[T any] struct {
+//	    name  string
+//	    rules []rules.Rule[T]
+//	}
+//
+//	func (m *majority[T]) Name() string               { return m.name }
+//	func (m *majority[T]) Children() []rules.Rule[T]  { return m.rules }
+//	func (m *majority[T]) Kind() rules.RuleType       { return rules.RuleTypeComposite }
+//	func (m *majority[T]) Evaluate(input T) (bool, error) {
+//	    return rules.EvaluateComposite(context.Background(), m, input)
+//	}
+//	func (m *majority[T]) Combine(results []rules.Result, total int) (bool, bool, error) {
+//	    satisfied := 0
+//	    for _, r := range results {
+//	        if r.Satisfied {
+//	            satisfied++
+//	        }
+//	    }
+//	    failed := len(results) - satisfied
+//	    return satisfied*2 > total, satisfied*2 > total || failed*2 >= total, nil
+//	}
+type CompositeRule[T any] interface {
+	Rule[T]
+
+	// Children returns the child rules in evaluation order.
+	Children() []Rule[T]
+
+	// Kind returns the operator kind of the rule. User-defined operators
+	// that have no matching kind should return RuleTypeComposite.
+	Kind() RuleType
+
+	// Combine computes the outcome from the results of the children
+	// evaluated so far, in child order, where total is the number of
+	// children. It reports whether the rule is satisfied and whether that
+	// outcome is decided, i.e. cannot change with the remaining children.
+	// It is called after every child and once more after the last one.
+	// A non-nil error fails the evaluation (e.g. ErrEmptyRules). Results of
+	// children that are not in effect (see Effective) have Inactive set.
+	Combine(results []Result, total int) (satisfied bool, decided bool, err error)
+}
+
+// EvaluateComposite evaluates the children of a composite rule in order and
+// combines their outcomes with its Combine method, stopping as soon as the
+// outcome is decided. It is intended for implementing Evaluate and
+// EvaluateContext of user-defined composite rules.
+func EvaluateComposite[T any](ctx context.Context, rule CompositeRule[T], input T) (bool, error) {
+	childRules := rule.Children()
+	results := make([]Result, 0, len(childRules))
+
+	for _, child := range childRules {
+		if child == nil {
+			return false, fmt.Errorf(
+				"evaluating %s rule %q: %w",
+				rule.Kind(),
+				rule.Name(),
+				ErrNilRule,
+			)
+		}
+
+		result := Result{RuleName: child.Name()}
+
+		// Plain children need no checks for severities and validity windows
+		decorated := isDecorated(child)
+		if decorated {
+			result.Severity = severityOf(child)
+		}
+
+		if !decorated || inEffect(ctx, child) {
+			satisfied, err := EvaluateContext(ctx, child, input)
+			if err != nil {
+				return false, fmt.Errorf(
+					"evaluating %s rule %q: %w",
+					rule.Kind(),
+					rule.Name(),
+					err,
+				)
+			}
+			result.Satisfied = satisfied
+		} else {
+			result.Satisfied = true
+			result.Inactive = true
+		}
+
+		results = append(results, result)
+
+		satisfied, decided, err := rule.Combine(results, len(childRules))
+		if err != nil {
+			return false, fmt.Errorf(
+				"evaluating %s rule %q: %w",
+				rule.Kind(),
+				rule.Name(),
+				err,
+			)
+		}
+		if decided {
+			return satisfied, nil
+		}
+	}
+
+	satisfied, _, err := rule.Combine(results, len(childRules))
+	if err != nil {
+		return false, fmt.Errorf(
+			"evaluating %s rule %q: %w",
+			rule.Kind(),
+			rule.Name(),
+			err,
+		)
+	}
+
+	return satisfied, nil
+}
+
+// evaluateCompositeDetailed evaluates the children of a composite rule and
+// returns their results. Evaluation stops at the first nil child or error,
+// and in short-circuit mode as soon as Combine reports the outcome decided.
+func evaluateCompositeDetailed[T any](
+	ev *evaluation,
+	rule CompositeRule[T],
+	input T,
+) (bool, []Result, error) {
+	childRules := rule.Children()
+	children := make([]Result, 0, len(childRules))
+
+	for _, childRule := range childRules {
+		if childRule == nil {
+			return false, children, ErrNilRule
+		}
+
+		childResult := evaluateRuleDetailed(ev, childRule, input)
+		children = append(children, childResult)
+		if childResult.Error != nil {
+			return false, children, childResult.Error
+		}
+
+		if ev.shortCircuit {
+			if _, decided, err := rule.Combine(children, len(childRules)); decided || err != nil {
+				break
+			}
+		}
+	}
+
+	satisfied, _, err := rule.Combine(children, len(childRules))
+	if err != nil {
+		return false, children, err
+	}
+
+	return satisfied, children, nil
+}
@@ -0,0 +1,200 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// majorityRule is a user-defined composite that is satisfied when more than
+// half of its children are satisfied.
+type majorityRule[T any] struct {
+	name  string
+	rules []Rule[T]
+}
+
+func (m *majorityRule[T]) Name() string        { return m.name }
+func (m *majorityRule[T]) Children() []Rule[T] { return m.rules }
+func (m *majorityRule[T]) Kind() RuleType      { return RuleTypeComposite }
+
+func (m *majorityRule[T]) Evaluate(input T) (bool, error) {
+	return EvaluateComposite(context.Background(), m, input)
+}
+
+func (m *majorityRule[T]) Combine(results []Result, total int) (bool, bool, error) {
+	if total == 0 {
+		return false, true, ErrEmptyRules
+	}
+
+	satisfied := 0
+	for _, result := range results {
+		if result.Satisfied {
+			satisfied++
+		}
+	}
+	failed := len(results) - satisfied
+
+	return satisfied*2 > total, satisfied*2 > total || failed*2 >= total, nil
+}
+
+func TestEvaluateComposite(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	failing := New("failing", func(input testInput) (bool, error) {
+		return false, errBoom
+	})
+
+	tests := []struct {
+		name    string
+		rule    Rule[testInput]
+		want    bool
+		wantErr error
+	}{
+		{
+			name: "majority satisfied",
+			rule: &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+				Always[testInput]("a"), Always[testInput]("b"), Never[testInput]("c"),
+			}},
+			want: true,
+		},
+		{
+			name: "majority not satisfied",
+			rule: &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+				Always[testInput]("a"), Never[testInput]("b"), Never[testInput]("c"),
+			}},
+			want: false,
+		},
+		{
+			name: "decided before error",
+			rule: &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+				Always[testInput]("a"), Always[testInput]("b"), failing,
+			}},
+			want: true,
+		},
+		{
+			name: "child error",
+			rule: &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+				Always[testInput]("a"), failing, Always[testInput]("c"),
+			}},
+			wantErr: errBoom,
+		},
+		{
+			name: "nil child",
+			rule: &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+				nil, Always[testInput]("a"),
+			}},
+			wantErr: ErrNilRule,
+		},
+		{
+			name:    "no children",
+			rule:    &majorityRule[testInput]{name: "majority"},
+			wantErr: ErrEmptyRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.rule.Evaluate(testInput{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), `evaluating COMPOSITE rule "majority"`) {
+				t.Errorf("Expected error to name the rule, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompositeDetailedEvaluation(t *testing.T) {
+	t.Parallel()
+
+	rule := &majorityRule[testInput]{name: "majority", rules: []Rule[testInput]{
+		Always[testInput]("a"),
+		Always[testInput]("b"),
+		Never[testInput]("c"),
+	}}
+	evaluator := NewEvaluator[testInput](rule)
+
+	t.Run("full evaluation reports every child", func(t *testing.T) {
+		t.Parallel()
+
+		result := evaluator.EvaluateDetailed(testInput{})
+		if !result.Satisfied || result.Error != nil {
+			t.Fatalf("Expected satisfied result, got %v", result)
+		}
+		if len(result.Children) != 3 {
+			t.Fatalf("Expected 3 children, got %d", len(result.Children))
+		}
+		if result.Children[2].RuleName != "c" || result.Children[2].Satisfied {
+			t.Errorf("Unexpected third child: %+v", result.Children[2])
+		}
+	})
+
+	t.Run("short-circuit stops once decided", func(t *testing.T) {
+		t.Parallel()
+
+		result := evaluator.EvaluateDetailedShortCircuit(testInput{})
+		if !result.Satisfied {
+			t.Fatal("Expected satisfied result")
+		}
+		if len(result.Children) != 2 {
+			t.Errorf("Expected 2 children, got %d", len(result.Children))
+		}
+	})
+
+	t.Run("nested in built-in composites", func(t *testing.T) {
+		t.Parallel()
+
+		outer := And[testInput]("outer", Always[testInput]("always"), rule)
+		result := NewEvaluator(outer).EvaluateDetailed(testInput{})
+		if !result.Satisfied {
+			t.Fatal("Expected satisfied result")
+		}
+		if got := len(result.Children[1].Children); got != 3 {
+			t.Errorf("Expected the composite to report 3 children, got %d", got)
+		}
+	})
+}
+
+func TestCompositeDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	rule := &majorityRule[testInput]{name: "majority approval", rules: []Rule[testInput]{
+		Always[testInput]("finance approved"),
+		Always[testInput]("legal approved"),
+		Always[testInput]("security approved"),
+	}}
+	if err := Register(rule, WithDomain("Approvals")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if got := getRuleType(rule); got != RuleTypeComposite {
+		t.Errorf("getRuleType() = %v, want %v", got, RuleTypeComposite)
+	}
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{"COMPOSITE", "**Child rules:**", "legal approved"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected Markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	mermaid, err := GenerateMermaid(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaid() error = %v", err)
+	}
+	if got := strings.Count(mermaid, " --> "); got != 3 {
+		t.Errorf("Expected 3 connections to the children, got %d:\n%s", got, mermaid)
+	}
+}
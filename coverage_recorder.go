@@ -0,0 +1,458 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CoverageRecorder records how the rules of evaluators are exercised across
+// many evaluations, typically a test run: how often each rule was satisfied,
+// not satisfied or failed, and which leaves of each evaluated rule tree were
+// shown to independently affect its outcome (modified condition/decision
+// coverage, MC/DC). Attach it to evaluators with WithCoverage and read the
+// results with Report.
+//
+// A CoverageRecorder is safe for concurrent use and may be shared by several
+// evaluators, so that one recorder covers a whole test suite.
+type CoverageRecorder struct {
+	mu            sync.Mutex
+	rules         map[ruleIdentity]*RuleCoverage
+	ruleOrder     []ruleIdentity
+	decisions     map[ruleIdentity]*decisionRecord
+	decisionOrder []ruleIdentity
+}
+
+// NewCoverageRecorder creates an empty coverage recorder.
+func NewCoverageRecorder() *CoverageRecorder {
+	return &CoverageRecorder{
+		rules:     make(map[ruleIdentity]*RuleCoverage),
+		decisions: make(map[ruleIdentity]*decisionRecord),
+	}
+}
+
+// WithCoverage records the evaluations of an Evaluator in the given
+// recorder. Every rule the evaluator reaches is recorded, and the rule the
+// evaluator was created for is recorded as a decision whose conditions are
+// its leaves, as in its truth table (see NewTruthTable).
+//
+// Coverage is recorded from detailed evaluations, so with a recorder
+// attached Evaluate, EvaluateContext and EvaluateFast evaluate like
+// EvaluateDetailedShortCircuit. Leaves skipped by short-circuiting are
+// treated as not affecting the outcome, as are leaves that are not in
+// effect.
+func WithCoverage(recorder *CoverageRecorder) EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.coverage = recorder
+	}
+}
+
+// RuleCoverage counts the outcomes of one rule.
+type RuleCoverage struct {
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Type is the type of the rule, e.g. "AND" or "SIMPLE".
+	Type string `json:"type"`
+	// True counts the evaluations that satisfied the rule.
+	True int `json:"true"`
+	// False counts the evaluations that did not satisfy the rule.
+	False int `json:"false"`
+	// Errors counts the evaluations that failed.
+	Errors int `json:"errors"`
+	// Inactive counts the evaluations at times the rule was not in effect
+	// (see Effective).
+	Inactive int `json:"inactive"`
+}
+
+// Covered reports whether the rule was both satisfied and not satisfied.
+func (c RuleCoverage) Covered() bool {
+	return c.True > 0 && c.False > 0
+}
+
+// label summarizes the coverage of the rule in a few words.
+func (c RuleCoverage) label() string {
+	switch {
+	case c.Covered():
+		return "covered"
+	case c.True > 0:
+		return "true only"
+	case c.False > 0:
+		return "false only"
+	default:
+		return "never decided"
+	}
+}
+
+// counts lists the counts of the rule, e.g. "true 3, false 0", leaving
+// out zero error and inactive counts.
+func (c RuleCoverage) counts() string {
+	counts := fmt.Sprintf("true %d, false %d", c.True, c.False)
+	if c.Errors > 0 {
+		counts += fmt.Sprintf(", errors %d", c.Errors)
+	}
+	if c.Inactive > 0 {
+		counts += fmt.Sprintf(", not in effect %d", c.Inactive)
+	}
+	return counts
+}
+
+// DecisionCoverage is the MC/DC coverage of the rule an evaluator was
+// created for.
+type DecisionCoverage struct {
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Evaluations counts the evaluations of the rule, including failed
+	// ones.
+	Evaluations int `json:"evaluations"`
+	// Conditions holds the coverage of each leaf of the rule, in truth
+	// table order.
+	Conditions []ConditionCoverage `json:"conditions"`
+}
+
+// Independent returns the number of conditions shown to independently
+// affect the outcome.
+func (c DecisionCoverage) Independent() int {
+	independent := 0
+	for _, condition := range c.Conditions {
+		if condition.Independent() {
+			independent++
+		}
+	}
+	return independent
+}
+
+// MCDC returns the fraction of conditions shown to independently affect the
+// outcome, or 1 if the rule has no conditions.
+func (c DecisionCoverage) MCDC() float64 {
+	if len(c.Conditions) == 0 {
+		return 1
+	}
+	return float64(c.Independent()) / float64(len(c.Conditions))
+}
+
+// ConditionCoverage is the coverage of one leaf of a decision.
+type ConditionCoverage struct {
+	// Rule is the name of the leaf.
+	Rule string `json:"rule"`
+	// True and False count the distinct observations of the decision in
+	// which the leaf was satisfied and not satisfied.
+	True  int `json:"true"`
+	False int `json:"false"`
+	// Pairs counts the independence pairs of the leaf: pairs of
+	// observations in which the leaf and the outcome differ while every
+	// other leaf is the same or did not affect the outcome.
+	Pairs int `json:"pairs"`
+}
+
+// Independent reports whether the leaf was shown to independently affect
+// the outcome.
+func (c ConditionCoverage) Independent() bool {
+	return c.Pairs > 0
+}
+
+// CoverageReport is a snapshot of a CoverageRecorder.
+type CoverageReport struct {
+	// Rules holds the coverage of every evaluated rule, in the order their
+	// first evaluation completed (children before their parent).
+	Rules []RuleCoverage `json:"rules"`
+	// Decisions holds the MC/DC coverage of the rules evaluators were
+	// created for.
+	Decisions []DecisionCoverage `json:"decisions"`
+
+	// ruleIndex and decisionIndex locate rules in the report for the HTML
+	// documentation overlay
+	ruleIndex     map[ruleIdentity]int
+	decisionIndex map[ruleIdentity]int
+}
+
+// Report returns the coverage recorded so far.
+func (c *CoverageRecorder) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CoverageReport{
+		Rules:         make([]RuleCoverage, 0, len(c.ruleOrder)),
+		Decisions:     make([]DecisionCoverage, 0, len(c.decisionOrder)),
+		ruleIndex:     make(map[ruleIdentity]int, len(c.ruleOrder)),
+		decisionIndex: make(map[ruleIdentity]int, len(c.decisionOrder)),
+	}
+	for _, id := range c.ruleOrder {
+		report.ruleIndex[id] = len(report.Rules)
+		report.Rules = append(report.Rules, *c.rules[id])
+	}
+	for _, id := range c.decisionOrder {
+		report.decisionIndex[id] = len(report.Decisions)
+		report.Decisions = append(report.Decisions, c.decisions[id].coverage())
+	}
+	return report
+}
+
+// String renders the report as text.
+func (r *CoverageReport) String() string {
+	var sb strings.Builder
+
+	covered := 0
+	for _, rule := range r.Rules {
+		if rule.Covered() {
+			covered++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Rule coverage: %d/%d rules evaluated both true and false\n", covered, len(r.Rules)))
+	for _, rule := range r.Rules {
+		sb.WriteString(fmt.Sprintf("  %s %s (%s): %s\n", coverageMark(rule.Covered()), rule.Rule, rule.Type, rule.counts()))
+	}
+
+	for _, decision := range r.Decisions {
+		sb.WriteString(fmt.Sprintf("MC/DC coverage of %q: %d/%d conditions (%d evaluations)\n",
+			decision.Rule, decision.Independent(), len(decision.Conditions), decision.Evaluations))
+		for _, condition := range decision.Conditions {
+			pairs := "no independence pair"
+			if condition.Pairs == 1 {
+				pairs = "1 independence pair"
+			} else if condition.Pairs > 1 {
+				pairs = fmt.Sprintf("%d independence pairs", condition.Pairs)
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s: %s\n", coverageMark(condition.Independent()), condition.Rule, pairs))
+		}
+	}
+
+	return sb.String()
+}
+
+// JSON renders the report as indented JSON.
+func (r *CoverageReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// rule returns the coverage of a rule.
+func (r *CoverageReport) rule(rule any) (RuleCoverage, bool) {
+	id, ok := identify(unwrapRule(rule))
+	if !ok {
+		return RuleCoverage{}, false
+	}
+	i, ok := r.ruleIndex[id]
+	if !ok {
+		return RuleCoverage{}, false
+	}
+	return r.Rules[i], true
+}
+
+// decision returns the MC/DC coverage of a rule.
+func (r *CoverageReport) decision(rule any) (DecisionCoverage, bool) {
+	id, ok := identify(unwrapRule(rule))
+	if !ok {
+		return DecisionCoverage{}, false
+	}
+	i, ok := r.decisionIndex[id]
+	if !ok {
+		return DecisionCoverage{}, false
+	}
+	return r.Decisions[i], true
+}
+
+// coverageMark returns the mark of covered and uncovered lines in text
+// reports.
+func coverageMark(covered bool) string {
+	if covered {
+		return "✓"
+	}
+	return "✗"
+}
+
+// decisionRecord accumulates the observations of a decision.
+type decisionRecord struct {
+	name        string
+	conditions  []string
+	leaves      map[ruleIdentity]int // column of each leaf in conditions
+	evaluations int
+	// observations holds the distinct outcomes of the leaves and the
+	// decision; seen holds their keys
+	observations []coverageObservation
+	seen         map[string]bool
+}
+
+// coverageObservation is the outcome of a decision and its leaves in one
+// evaluation. Leaves that did not affect the outcome are TruthUnknown.
+type coverageObservation struct {
+	conditions []Truth
+	outcome    bool
+}
+
+// key identifies the observation among the observations of its decision.
+func (o coverageObservation) key() string {
+	var sb strings.Builder
+	for _, condition := range o.conditions {
+		sb.WriteString(condition.String()[:1])
+	}
+	sb.WriteString("=" + truthOf(o.outcome).String())
+	return sb.String()
+}
+
+// coverage computes the MC/DC coverage of the decision.
+func (d *decisionRecord) coverage() DecisionCoverage {
+	coverage := DecisionCoverage{
+		Rule:        d.name,
+		Evaluations: d.evaluations,
+		Conditions:  make([]ConditionCoverage, len(d.conditions)),
+	}
+	for i, name := range d.conditions {
+		condition := ConditionCoverage{Rule: name}
+		for a, first := range d.observations {
+			switch first.conditions[i] {
+			case TruthTrue:
+				condition.True++
+			case TruthFalse:
+				condition.False++
+			}
+			for _, second := range d.observations[a+1:] {
+				if independencePair(first, second, i) {
+					condition.Pairs++
+				}
+			}
+		}
+		coverage.Conditions[i] = condition
+	}
+	return coverage
+}
+
+// independencePair reports whether two observations show that the leaf at
+// column independently affects the outcome: the leaf and the outcome
+// differ, and every other leaf is the same or did not affect the outcome in
+// one of them.
+func independencePair(first, second coverageObservation, column int) bool {
+	if first.outcome == second.outcome {
+		return false
+	}
+	a, b := first.conditions[column], second.conditions[column]
+	if !a.Known() || !b.Known() || a == b {
+		return false
+	}
+	for i := range first.conditions {
+		if i == column {
+			continue
+		}
+		a, b := first.conditions[i], second.conditions[i]
+		if a.Known() && b.Known() && a != b {
+			return false
+		}
+	}
+	return true
+}
+
+// start begins recording an evaluation of the rule an evaluator was created
+// for.
+func (c *CoverageRecorder) start(root any) *coverageRun {
+	run := &coverageRun{recorder: c}
+
+	id, ok := identify(unwrapRule(root))
+	if !ok {
+		return run
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decision, ok := c.decisions[id]
+	if !ok {
+		decision = &decisionRecord{name: getRuleName(root), seen: make(map[string]bool)}
+		// Rules without a truth table have no conditions
+		builder := &truthTableBuilder{leaves: make(map[ruleIdentity]int)}
+		if _, err := builder.node(root); err == nil {
+			decision.conditions = builder.names
+			decision.leaves = builder.leaves
+		}
+		c.decisions[id] = decision
+		c.decisionOrder = append(c.decisionOrder, id)
+	}
+	run.decision = decision
+	run.conditions = make([]Truth, len(decision.conditions))
+	return run
+}
+
+// coverageRun records a single evaluation. The rules of parallel rules
+// record concurrently, so it is guarded by the recorder's mutex.
+type coverageRun struct {
+	recorder   *CoverageRecorder
+	decision   *decisionRecord
+	conditions []Truth
+}
+
+// record records the result of a rule. Decorating rules report the result
+// of the rule they wrap, which records it itself; only the results of
+// effective-dated rules that are not in effect are recorded for them.
+func (run *coverageRun) record(rule any, result Result) {
+	if _, wrapper := rule.(wrappingRule); wrapper {
+		_, effective := rule.(interface {
+			EffectiveWindow() (from, until time.Time)
+		})
+		if !effective || !result.Inactive {
+			return
+		}
+	}
+
+	id, ok := identify(unwrapRule(rule))
+	if !ok {
+		return
+	}
+
+	c := run.recorder
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	coverage, ok := c.rules[id]
+	if !ok {
+		coverage = &RuleCoverage{
+			Rule: getRuleName(rule),
+			Type: getRuleType(unwrapRule(rule)).String(),
+		}
+		c.rules[id] = coverage
+		c.ruleOrder = append(c.ruleOrder, id)
+	}
+
+	switch {
+	case result.Error != nil:
+		coverage.Errors++
+		return
+	case result.Inactive:
+		coverage.Inactive++
+		return
+	case result.Satisfied:
+		coverage.True++
+	default:
+		coverage.False++
+	}
+
+	if run.decision == nil {
+		return
+	}
+	// A leaf evaluated more than once keeps its first outcome
+	if column, ok := run.decision.leaves[id]; ok && run.conditions[column] == TruthUnknown {
+		run.conditions[column] = truthOf(result.Satisfied)
+	}
+}
+
+// finish records the outcome of the evaluation as an observation of the
+// decision.
+func (run *coverageRun) finish(result Result) {
+	if run.decision == nil {
+		return
+	}
+
+	c := run.recorder
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	decision := run.decision
+	decision.evaluations++
+	if result.Error != nil || result.Inactive {
+		return
+	}
+
+	observation := coverageObservation{conditions: run.conditions, outcome: result.Satisfied}
+	if key := observation.key(); !decision.seen[key] {
+		decision.seen[key] = true
+		decision.observations = append(decision.observations, observation)
+	}
+}
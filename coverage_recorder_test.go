@@ -0,0 +1,219 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCoverageRecorder(t *testing.T) {
+	t.Parallel()
+
+	positive := New("positive", func(in testInput) (bool, error) { return in.value > 0, nil })
+	valid := New("valid", func(in testInput) (bool, error) { return in.valid, nil })
+	eligible := And("eligible", positive, valid)
+
+	recorder := NewCoverageRecorder()
+	evaluator := NewEvaluator(eligible, WithCoverage(recorder))
+
+	if result := evaluator.Evaluate(testInput{value: 1, valid: true}); !result.Satisfied || result.Children != nil {
+		t.Errorf("Evaluate() = %+v, want satisfied without children", result)
+	}
+	if satisfied, err := evaluator.EvaluateFast(testInput{value: 1}); satisfied || err != nil {
+		t.Errorf("EvaluateFast() = %v, %v; want false", satisfied, err)
+	}
+
+	// Only valid has flipped the outcome so far
+	decision := recorder.Report().Decisions[0]
+	if decision.Conditions[0].Independent() || !decision.Conditions[1].Independent() {
+		t.Errorf("Unexpected conditions after two evaluations: %+v", decision.Conditions)
+	}
+
+	// Short-circuiting leaves valid out of the third evaluation
+	evaluator.EvaluateDetailedShortCircuit(testInput{value: -1, valid: true})
+
+	report := recorder.Report()
+	wantRules := []RuleCoverage{
+		{Rule: "positive", Type: "SIMPLE", True: 2, False: 1},
+		{Rule: "valid", Type: "SIMPLE", True: 1, False: 1},
+		{Rule: "eligible", Type: "AND", True: 1, False: 2},
+	}
+	if len(report.Rules) != len(wantRules) {
+		t.Fatalf("Rules = %+v, want %+v", report.Rules, wantRules)
+	}
+	for i, want := range wantRules {
+		if report.Rules[i] != want {
+			t.Errorf("Rules[%d] = %+v, want %+v", i, report.Rules[i], want)
+		}
+	}
+
+	if len(report.Decisions) != 1 {
+		t.Fatalf("Decisions = %+v, want one", report.Decisions)
+	}
+	decision = report.Decisions[0]
+	if decision.Rule != "eligible" || decision.Evaluations != 3 || decision.MCDC() != 1 {
+		t.Errorf("Unexpected decision: %+v", decision)
+	}
+	if got := decision.Conditions[0]; got != (ConditionCoverage{Rule: "positive", True: 2, False: 1, Pairs: 1}) {
+		t.Errorf("Conditions[0] = %+v", got)
+	}
+
+	text := report.String()
+	for _, want := range []string{
+		"Rule coverage: 3/3 rules evaluated both true and false",
+		"✓ positive (SIMPLE): true 2, false 1",
+		`MC/DC coverage of "eligible": 2/2 conditions (3 evaluations)`,
+		"✓ valid: 1 independence pair",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() should contain %q:\n%s", want, text)
+		}
+	}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var decoded CoverageReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(decoded.Rules) != 3 || decoded.Decisions[0].Conditions[1].Pairs != 1 {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestCoverageRecorder_Outcomes(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	positive := New("positive", func(in testInput) (bool, error) { return in.value > 0, nil })
+	failing := New("failing", func(in testInput) (bool, error) {
+		if in.valid {
+			return false, errBoom
+		}
+		return true, nil
+	})
+	future := Effective(New("future", func(testInput) (bool, error) { return true, nil }),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	rule := And("checks", WithSeverity(positive, SeverityWarning), future, failing)
+
+	recorder := NewCoverageRecorder()
+	evaluator := NewEvaluator(rule, WithCoverage(recorder), WithMemoization())
+	evaluator.EvaluateDetailed(testInput{value: 1})
+	if _, err := evaluator.EvaluateFast(testInput{valid: true}); !errors.Is(err, errBoom) {
+		t.Errorf("EvaluateFast() error = %v, want errBoom", err)
+	}
+
+	report := recorder.Report()
+	got := make(map[string]RuleCoverage)
+	for _, coverage := range report.Rules {
+		got[coverage.Rule] = coverage
+	}
+	want := map[string]RuleCoverage{
+		"checks":   {Rule: "checks", Type: "AND", True: 1, Errors: 1},
+		"positive": {Rule: "positive", Type: "SIMPLE", True: 1, False: 1},
+		"future":   {Rule: "future", Type: "SIMPLE", Inactive: 2},
+		"failing":  {Rule: "failing", Type: "SIMPLE", True: 1, Errors: 1},
+	}
+	if len(got) != len(want) {
+		t.Errorf("Rules = %+v, want %+v", report.Rules, want)
+	}
+	for name, coverage := range want {
+		if got[name] != coverage {
+			t.Errorf("Coverage of %q = %+v, want %+v", name, got[name], coverage)
+		}
+	}
+
+	// The failed evaluation is not an observation of the decision
+	decision := report.Decisions[0]
+	if decision.Evaluations != 2 || len(decision.Conditions) != 3 || decision.Independent() != 0 {
+		t.Errorf("Unexpected decision: %+v", decision)
+	}
+}
+
+func TestCoverageRecorder_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	leaves := leafRules(4)
+	rule := ParallelAnd("parallel", 4, leaves...)
+
+	recorder := NewCoverageRecorder()
+	evaluators := []*Evaluator[testInput]{
+		NewEvaluator(rule, WithCoverage(recorder)),
+		NewEvaluator(Or("or", leaves[0], rule), WithCoverage(recorder)),
+	}
+
+	var wg sync.WaitGroup
+	for _, evaluator := range evaluators {
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				evaluator.EvaluateDetailedContext(context.Background(), testInput{})
+			}()
+		}
+	}
+	wg.Wait()
+
+	report := recorder.Report()
+	if len(report.Decisions) != 2 {
+		t.Fatalf("Decisions = %+v, want two", report.Decisions)
+	}
+	for _, coverage := range report.Rules {
+		want := 10
+		switch coverage.Rule {
+		case "parallel", "b", "c", "d":
+			// Full detailed evaluations do not short-circuit the OR
+			want = 20
+		case "a":
+			want = 30
+		}
+		if coverage.True != want {
+			t.Errorf("Coverage of %q = %+v, want %d true", coverage.Rule, coverage, want)
+		}
+	}
+}
+
+func TestCoverageDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	positive := New("positive", func(in testInput) (bool, error) { return in.value > 0, nil })
+	valid := New("valid", func(in testInput) (bool, error) { return in.valid, nil })
+	eligible := And("eligible", positive, valid)
+	_ = Register(eligible, WithDomain(TestOrderDomain))
+
+	recorder := NewCoverageRecorder()
+	evaluator := NewEvaluator(eligible, WithCoverage(recorder))
+	evaluator.EvaluateDetailed(testInput{value: 1, valid: true})
+	evaluator.EvaluateDetailed(testInput{value: -1, valid: true})
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{Coverage: recorder.Report()})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		`<span class="coverage-badge coverage-full" title="true 1, false 1">covered</span>`,
+		`<span class="coverage-badge coverage-partial" title="true 2, false 0">true only</span>`,
+		"MC/DC coverage: 1/2 conditions (2 evaluations)",
+		"<td>positive</td><td>1</td><td>1</td><td>1</td>",
+	} {
+		if !strings.Contains(htmlDoc, want) {
+			t.Errorf("HTML should contain %q", want)
+		}
+	}
+
+	// Without a report there is no overlay
+	htmlDoc, err = GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	if strings.Contains(htmlDoc, `class="coverage-badge`) {
+		t.Error("HTML should not contain coverage badges")
+	}
+}
@@ -0,0 +1,620 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoRowMatched is returned when no row of a decision table matches
+	// and the table has no default outcome.
+	ErrNoRowMatched = errors.New("no decision table row matched")
+	// ErrMultipleRowsMatched is returned when more than one row of a
+	// decision table with HitPolicyUnique matches.
+	ErrMultipleRowsMatched = errors.New("multiple decision table rows matched")
+)
+
+// HitPolicy determines which rows of a decision table produce its outcome
+// when several rows match.
+type HitPolicy int
+
+const (
+	// HitPolicyFirst selects the first matching row in table order.
+	HitPolicyFirst HitPolicy = iota
+	// HitPolicyUnique requires that at most one row matches; more than one
+	// match fails with ErrMultipleRowsMatched.
+	HitPolicyUnique
+	// HitPolicyPriority selects the matching row with the highest priority
+	// (see AddPriorityRow). Ties are resolved in table order.
+	HitPolicyPriority
+	// HitPolicyCollect selects all matching rows in table order.
+	HitPolicyCollect
+)
+
+// String returns the string representation of a HitPolicy.
+func (p HitPolicy) String() string {
+	switch p {
+	case HitPolicyFirst:
+		return "FIRST"
+	case HitPolicyUnique:
+		return "UNIQUE"
+	case HitPolicyPriority:
+		return "PRIORITY"
+	case HitPolicyCollect:
+		return "COLLECT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// decisionRow is a row of a decision table.
+type decisionRow[T any, R any] struct {
+	conditions []Rule[T]
+	outcome    R
+	priority   int
+}
+
+// DecisionTable maps inputs to outcomes of type R with rows of conditions,
+// in the style of DMN decision tables. A row matches if all of its
+// conditions are satisfied; which matching rows produce the outcome is
+// determined by the hit policy.
+//
+// A DecisionTable is also a Rule that is satisfied if a row matches, so it
+// can be registered, documented and combined with other rules. The
+// Markdown and HTML documenters render it as a table.
+//
+// Example:
+//
+//	shippingFee := rules.NewDecisionTable[Order, float64](
+//	    "shipping fee", rules.HitPolicyFirst, "customer", "order amount",
+//	).
+//	    AddRow(0, isPremium, nil).
+//	    AddRow(0, nil, amountOver50).
+//	    AddRow(4.99, nil, nil)
+//
+//	fee, err := shippingFee.Decide(order)
+type DecisionTable[T any, R any] struct {
+	name       string
+	policy     HitPolicy
+	inputs     []string
+	rows       []decisionRow[T, R]
+	fallback   R
+	hasDefault bool
+}
+
+// NewDecisionTable creates an empty decision table with the given hit
+// policy. The inputs label the columns of the table: the i-th condition of
+// each row belongs to the i-th input, and a nil condition matches any
+// input ("-"). Without inputs, rows are documented as a single list of
+// conditions.
+func NewDecisionTable[T any, R any](name string, policy HitPolicy, inputs ...string) *DecisionTable[T, R] {
+	return &DecisionTable[T, R]{
+		name:   name,
+		policy: policy,
+		inputs: inputs,
+	}
+}
+
+// AddRow adds a row that produces outcome if all conditions are satisfied.
+// Nil conditions match any input.
+func (t *DecisionTable[T, R]) AddRow(outcome R, conditions ...Rule[T]) *DecisionTable[T, R] {
+	return t.AddPriorityRow(0, outcome, conditions...)
+}
+
+// AddPriorityRow adds a row with a priority. With HitPolicyPriority, the
+// matching row with the highest priority produces the outcome.
+func (t *DecisionTable[T, R]) AddPriorityRow(priority int, outcome R, conditions ...Rule[T]) *DecisionTable[T, R] {
+	t.rows = append(t.rows, decisionRow[T, R]{
+		conditions: conditions,
+		outcome:    outcome,
+		priority:   priority,
+	})
+	return t
+}
+
+// Default sets the outcome used when no row matches.
+func (t *DecisionTable[T, R]) Default(outcome R) *DecisionTable[T, R] {
+	t.fallback = outcome
+	t.hasDefault = true
+	return t
+}
+
+// Name returns the name of the table.
+func (t *DecisionTable[T, R]) Name() string {
+	return t.name
+}
+
+// Kind reports that the rule is a decision table.
+func (t *DecisionTable[T, R]) Kind() RuleType {
+	return RuleTypeDecisionTable
+}
+
+// HitPolicy returns the hit policy of the table.
+func (t *DecisionTable[T, R]) HitPolicy() HitPolicy {
+	return t.policy
+}
+
+// Evaluate reports whether a row of the table matches the input. The
+// default outcome does not count as a match.
+func (t *DecisionTable[T, R]) Evaluate(input T) (bool, error) {
+	return t.EvaluateContext(context.Background(), input)
+}
+
+// EvaluateContext is like Evaluate but honors the context.
+func (t *DecisionTable[T, R]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	hits, _, err := t.evaluateRows(&evaluation{ctx: ctx}, input, false)
+	if err != nil {
+		return false, err
+	}
+	return len(hits) > 0, nil
+}
+
+// Decide returns the outcome of the table for the input: the outcome of
+// the row selected by the hit policy (the first matching row with
+// HitPolicyCollect), or the default outcome if no row matches. Without a
+// default it fails with ErrNoRowMatched.
+func (t *DecisionTable[T, R]) Decide(input T) (R, error) {
+	return t.DecideContext(context.Background(), input)
+}
+
+// DecideContext is like Decide but honors the context.
+func (t *DecisionTable[T, R]) DecideContext(ctx context.Context, input T) (R, error) {
+	decision := t.decide(&evaluation{ctx: ctx}, input, false)
+	return decision.Outcome, decision.Result.Error
+}
+
+// Collect returns the outcomes of all matching rows selected by the hit
+// policy, or the default outcome if no row matches. Without a default it
+// fails with ErrNoRowMatched.
+func (t *DecisionTable[T, R]) Collect(input T) ([]R, error) {
+	return t.CollectContext(context.Background(), input)
+}
+
+// CollectContext is like Collect but honors the context.
+func (t *DecisionTable[T, R]) CollectContext(ctx context.Context, input T) ([]R, error) {
+	decision := t.decide(&evaluation{ctx: ctx}, input, false)
+	return decision.Outcomes, decision.Result.Error
+}
+
+// Decision is the detailed outcome of a decision table (see
+// DecideDetailed).
+type Decision[R any] struct {
+	// Outcome is the outcome of the first selected row, or the default
+	// outcome if no row matched.
+	Outcome R
+	// Outcomes holds the outcomes of all selected rows. Only
+	// HitPolicyCollect selects more than one row.
+	Outcomes []R
+	// Rows holds the indexes of the selected rows (starting at 0), in the
+	// order of Outcomes.
+	Rows []int
+	// Default reports whether the default outcome was used.
+	Default bool
+	// Result is the detailed evaluation of the table, with a child for
+	// each evaluated row ("row 1", "row 2", ...) and a grandchild for each
+	// of its conditions. Its Error is set if the decision failed.
+	Result Result
+}
+
+// DecideDetailed evaluates the table and reports the outcome together with
+// the rows that matched and the results of their conditions.
+func (t *DecisionTable[T, R]) DecideDetailed(input T) Decision[R] {
+	return t.DecideDetailedContext(context.Background(), input)
+}
+
+// DecideDetailedContext is like DecideDetailed but honors the context.
+func (t *DecisionTable[T, R]) DecideDetailedContext(ctx context.Context, input T) Decision[R] {
+	return t.decide(&evaluation{ctx: ctx}, input, true)
+}
+
+// decide evaluates the table and selects its outcome.
+func (t *DecisionTable[T, R]) decide(ev *evaluation, input T, detailed bool) Decision[R] {
+	start := time.Now()
+	hits, rows, err := t.evaluateRows(ev, input, detailed)
+
+	var decision Decision[R]
+	switch {
+	case err != nil:
+	case len(hits) == 0 && t.hasDefault:
+		decision.Outcome = t.fallback
+		decision.Outcomes = []R{t.fallback}
+		decision.Default = true
+	case len(hits) == 0:
+		err = fmt.Errorf("evaluating decision table %q: %w", t.name, ErrNoRowMatched)
+	default:
+		decision.Rows = hits
+		for _, hit := range hits {
+			decision.Outcomes = append(decision.Outcomes, t.rows[hit].outcome)
+		}
+		decision.Outcome = decision.Outcomes[0]
+	}
+
+	decision.Result = Result{
+		Satisfied: err == nil && len(hits) > 0,
+		RuleName:  t.name,
+		Duration:  time.Since(start),
+		Error:     err,
+		Children:  rows,
+	}
+	return decision
+}
+
+// evaluateNested reports the evaluated rows as the children of the table
+// in detailed evaluation.
+func (t *DecisionTable[T, R]) evaluateNested(ev *evaluation, input T) (bool, []Result, error) {
+	hits, rows, err := t.evaluateRows(ev, input, true)
+	return err == nil && len(hits) > 0, rows, err
+}
+
+// evaluateRows evaluates the rows the hit policy needs and returns the
+// indexes of the selected rows. In detailed mode it also returns a result
+// for every evaluated row.
+func (t *DecisionTable[T, R]) evaluateRows(ev *evaluation, input T, detailed bool) ([]int, []Result, error) {
+	var (
+		matches []int
+		results []Result
+	)
+
+	for i, row := range t.rows {
+		var (
+			matched bool
+			err     error
+		)
+		if detailed {
+			var result Result
+			result, err = t.evaluateRowDetailed(ev, i, input)
+			matched = result.Satisfied
+			results = append(results, result)
+		} else {
+			matched, err = t.evaluateRow(ev.ctx, row, input)
+		}
+		if err != nil {
+			return nil, results, fmt.Errorf("evaluating decision table %q row %d: %w", t.name, i+1, err)
+		}
+
+		if matched {
+			matches = append(matches, i)
+			if t.policy == HitPolicyFirst {
+				break
+			}
+		}
+	}
+
+	hits, err := t.selectRows(matches)
+	return hits, results, err
+}
+
+// selectRows applies the hit policy to the matching rows.
+func (t *DecisionTable[T, R]) selectRows(matches []int) ([]int, error) {
+	if len(matches) <= 1 {
+		return matches, nil
+	}
+
+	switch t.policy {
+	case HitPolicyUnique:
+		return nil, fmt.Errorf(
+			"evaluating decision table %q: %w: rows %s",
+			t.name,
+			ErrMultipleRowsMatched,
+			formatRowNumbers(matches),
+		)
+	case HitPolicyPriority:
+		best := matches[0]
+		for _, i := range matches[1:] {
+			if t.rows[i].priority > t.rows[best].priority {
+				best = i
+			}
+		}
+		return []int{best}, nil
+	case HitPolicyCollect:
+		return matches, nil
+	default:
+		return matches[:1], nil
+	}
+}
+
+// evaluateRow reports whether all conditions of a row are satisfied. Like
+// And, it ignores conditions that are not in effect and failed conditions
+// with a severity below SeverityError.
+func (t *DecisionTable[T, R]) evaluateRow(ctx context.Context, row decisionRow[T, R], input T) (bool, error) {
+	for _, condition := range row.conditions {
+		if condition == nil || !inEffect(ctx, condition) {
+			continue
+		}
+
+		satisfied, err := EvaluateContext(ctx, condition, input)
+		if err != nil {
+			return false, err
+		}
+		if !satisfied && severityOf(condition).isBlocking() {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// evaluateRowDetailed evaluates the conditions of a row, building a result
+// named after the row.
+func (t *DecisionTable[T, R]) evaluateRowDetailed(ev *evaluation, i int, input T) (Result, error) {
+	start := time.Now()
+	result := Result{RuleName: fmt.Sprintf("row %d", i+1)}
+
+	var conditions []Result
+	for _, condition := range t.rows[i].conditions {
+		if condition == nil {
+			continue
+		}
+		conditionResult := evaluateRuleDetailed(ev, condition, input)
+		conditions = append(conditions, conditionResult)
+		if conditionResult.Error != nil {
+			result.Error = conditionResult.Error
+			break
+		}
+		if ev.shortCircuit && !conditionResult.Satisfied && conditionResult.Severity.isBlocking() {
+			break
+		}
+	}
+
+	result.Children = conditions
+	result.Duration = time.Since(start)
+	if result.Error != nil {
+		return result, result.Error
+	}
+
+	result.Satisfied = true
+	for _, condition := range conditions {
+		if !condition.Satisfied && condition.Severity.isBlocking() {
+			result.Satisfied = false
+		}
+	}
+	return result, nil
+}
+
+// Analyze reports problems with the rows of the table: rows that can never
+// be selected because an earlier or higher-priority row matches whenever
+// they do, overlapping rows in a table with HitPolicyUnique, and rows whose
+// number of conditions does not match the inputs.
+//
+// A row covers another if each of its conditions is nil or the same rule
+// as the other row's condition for that input. Conditions are compared by
+// identity, not evaluated. Additionally, the rows are evaluated against the
+// samples to find inputs that match no row (if the table has no default)
+// or, with HitPolicyUnique, more than one row.
+func (t *DecisionTable[T, R]) Analyze(samples ...T) ([]Finding, error) {
+	a := &analyzer{}
+	path := []string{t.name}
+
+	if len(t.rows) == 0 {
+		a.report(FindingEmptyComposite, SeverityError, path, "decision table has no rows")
+	}
+
+	for i, row := range t.rows {
+		if len(t.inputs) > 0 && len(row.conditions) != len(t.inputs) {
+			a.report(FindingMalformedRow, SeverityError, t.rowPath(i),
+				"has %d conditions but the table has %d inputs", len(row.conditions), len(t.inputs))
+		}
+	}
+
+	for j := range t.rows {
+		for i := 0; i < j; i++ {
+			t.analyzeCoverage(a, i, j)
+		}
+	}
+
+	for s, sample := range samples {
+		var matches []int
+		for i, row := range t.rows {
+			matched, err := t.evaluateRow(context.Background(), row, sample)
+			if err != nil {
+				return nil, fmt.Errorf("evaluating decision table %q row %d on sample %d: %w", t.name, i+1, s+1, err)
+			}
+			if matched {
+				matches = append(matches, i)
+			}
+		}
+
+		switch {
+		case len(matches) == 0 && !t.hasDefault:
+			a.report(FindingMissingRow, SeverityWarning, path, "no row matches sample %d", s+1)
+		case len(matches) > 1 && t.policy == HitPolicyUnique:
+			a.report(FindingOverlappingRows, SeverityError, path,
+				"rows %s match sample %d", formatRowNumbers(matches), s+1)
+		}
+	}
+
+	return a.findings, nil
+}
+
+// analyzeCoverage reports row j, which comes after row i, if one of them
+// matches whenever the other does.
+func (t *DecisionTable[T, R]) analyzeCoverage(a *analyzer, i, j int) {
+	first, second := t.rows[i], t.rows[j]
+	firstCovers := t.covers(first, second)
+	secondCovers := t.covers(second, first)
+	if !firstCovers && !secondCovers {
+		return
+	}
+
+	switch t.policy {
+	case HitPolicyUnique:
+		a.report(FindingOverlappingRows, SeverityError, t.rowPath(j),
+			"overlaps row %d", i+1)
+	case HitPolicyFirst:
+		if firstCovers {
+			a.report(FindingUnreachableRow, SeverityWarning, t.rowPath(j),
+				"is never selected because row %d matches whenever it does", i+1)
+		}
+	case HitPolicyPriority:
+		if firstCovers && first.priority >= second.priority {
+			a.report(FindingUnreachableRow, SeverityWarning, t.rowPath(j),
+				"is never selected because row %d matches whenever it does", i+1)
+		} else if secondCovers && second.priority > first.priority {
+			a.report(FindingUnreachableRow, SeverityWarning, t.rowPath(i),
+				"is never selected because row %d matches whenever it does", j+1)
+		}
+	}
+}
+
+// covers reports whether row a matches whenever row b does.
+func (t *DecisionTable[T, R]) covers(a, b decisionRow[T, R]) bool {
+	if len(t.inputs) > 0 {
+		for i, condition := range a.conditions {
+			if condition == nil {
+				continue
+			}
+			if i >= len(b.conditions) || !sameRule(condition, b.conditions[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Without inputs, a covers b if each of its conditions is one of b's
+	for _, condition := range a.conditions {
+		if condition == nil {
+			continue
+		}
+		found := false
+		for _, other := range b.conditions {
+			if sameRule(condition, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// rowPath returns the finding path of a row.
+func (t *DecisionTable[T, R]) rowPath(i int) []string {
+	return []string{t.name, fmt.Sprintf("row %d", i+1)}
+}
+
+// layout describes the table for documentation.
+func (t *DecisionTable[T, R]) layout() *decisionTableLayout {
+	layout := &decisionTableLayout{
+		HitPolicy:  t.policy,
+		Inputs:     t.inputs,
+		HasDefault: t.hasDefault,
+	}
+	if len(layout.Inputs) == 0 {
+		layout.Inputs = []string{"Conditions"}
+	}
+	if t.hasDefault {
+		layout.Default = fmt.Sprint(t.fallback)
+	}
+
+	for _, row := range t.rows {
+		layout.Rows = append(layout.Rows, decisionRowLayout{
+			Cells:    t.cells(row),
+			Outcome:  fmt.Sprint(row.outcome),
+			Priority: row.priority,
+		})
+	}
+
+	return layout
+}
+
+// cells returns the documented cells of a row, one per input. Conditions
+// beyond the last input are added to the last cell.
+func (t *DecisionTable[T, R]) cells(row decisionRow[T, R]) []string {
+	columns := len(t.inputs)
+	if columns == 0 {
+		columns = 1
+	}
+
+	names := make([][]string, columns)
+	for i, condition := range row.conditions {
+		column := i
+		if len(t.inputs) == 0 || column >= columns {
+			column = columns - 1
+		}
+		if condition != nil {
+			names[column] = append(names[column], condition.Name())
+		}
+	}
+
+	cells := make([]string, columns)
+	for i, cellNames := range names {
+		cells[i] = "-"
+		if len(cellNames) > 0 {
+			cells[i] = strings.Join(cellNames, " and ")
+		}
+	}
+	return cells
+}
+
+// decisionTableLayout describes a decision table for documentation.
+type decisionTableLayout struct {
+	HitPolicy  HitPolicy
+	Inputs     []string
+	Rows       []decisionRowLayout
+	Default    string
+	HasDefault bool
+}
+
+// decisionRowLayout describes a row of a decision table for documentation.
+type decisionRowLayout struct {
+	Cells    []string
+	Outcome  string
+	Priority int
+}
+
+// header returns the column headers of the documented table.
+func (l *decisionTableLayout) header() []string {
+	header := append([]string{"#"}, l.Inputs...)
+	if l.HitPolicy == HitPolicyPriority {
+		header = append(header, "Priority")
+	}
+	return append(header, "Outcome")
+}
+
+// rows returns the cells of the documented table, including a final row
+// for the default outcome.
+func (l *decisionTableLayout) rows() [][]string {
+	var rows [][]string
+	for i, row := range l.Rows {
+		cells := append([]string{fmt.Sprint(i + 1)}, row.Cells...)
+		if l.HitPolicy == HitPolicyPriority {
+			cells = append(cells, fmt.Sprint(row.Priority))
+		}
+		rows = append(rows, append(cells, row.Outcome))
+	}
+
+	if l.HasDefault {
+		cells := []string{"otherwise"}
+		for range l.Inputs {
+			cells = append(cells, "-")
+		}
+		if l.HitPolicy == HitPolicyPriority {
+			cells = append(cells, "-")
+		}
+		rows = append(rows, append(cells, l.Default))
+	}
+
+	return rows
+}
+
+// sameRule reports whether two rules are the same instance.
+func sameRule(a, b any) bool {
+	aID, aOK := identify(a)
+	bID, bOK := identify(b)
+	return aOK && bOK && aID == bID
+}
+
+// formatRowNumbers formats row indexes as 1-based row numbers, e.g.
+// "1, 3".
+func formatRowNumbers(rows []int) string {
+	numbers := make([]string, len(rows))
+	for i, row := range rows {
+		numbers[i] = fmt.Sprint(row + 1)
+	}
+	return strings.Join(numbers, ", ")
+}
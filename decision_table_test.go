@@ -0,0 +1,226 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// shippingInput is the input of the shipping fee decision tables.
+type shippingInput struct {
+	premium bool
+	amount  float64
+}
+
+var (
+	isPremiumCustomer = New("is premium", func(in shippingInput) (bool, error) {
+		return in.premium, nil
+	})
+	amountOver50 = New("amount over 50", func(in shippingInput) (bool, error) {
+		return in.amount > 50, nil
+	})
+)
+
+func TestDecisionTable_HitPolicies(t *testing.T) {
+	t.Parallel()
+
+	newTable := func(policy HitPolicy) *DecisionTable[shippingInput, string] {
+		return NewDecisionTable[shippingInput, string]("shipping fee", policy, "customer", "order amount").
+			AddPriorityRow(1, "free (premium)", isPremiumCustomer, nil).
+			AddPriorityRow(2, "free (amount)", nil, amountOver50)
+	}
+	both := shippingInput{premium: true, amount: 100}
+
+	tests := []struct {
+		name    string
+		policy  HitPolicy
+		want    []string
+		wantErr error
+	}{
+		{name: "first", policy: HitPolicyFirst, want: []string{"free (premium)"}},
+		{name: "priority", policy: HitPolicyPriority, want: []string{"free (amount)"}},
+		{name: "collect", policy: HitPolicyCollect, want: []string{"free (premium)", "free (amount)"}},
+		{name: "unique", policy: HitPolicyUnique, wantErr: ErrMultipleRowsMatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTable(tt.policy).Collect(both)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Collect() error = %v, want %v", err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Collect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+
+		table := newTable(HitPolicyFirst)
+		if _, err := table.Decide(shippingInput{}); !errors.Is(err, ErrNoRowMatched) {
+			t.Errorf("Expected ErrNoRowMatched, got %v", err)
+		}
+		if satisfied, err := table.Evaluate(shippingInput{}); err != nil || satisfied {
+			t.Errorf("Evaluate() = %v, %v; want false, nil", satisfied, err)
+		}
+
+		table.Default("4.99")
+		if got, err := table.Decide(shippingInput{}); err != nil || got != "4.99" {
+			t.Errorf("Decide() = %q, %v; want 4.99, nil", got, err)
+		}
+	})
+
+	t.Run("condition error", func(t *testing.T) {
+		t.Parallel()
+
+		failing := New("failing", func(in shippingInput) (bool, error) {
+			return false, ErrEvaluationFailed
+		})
+		table := NewDecisionTable[shippingInput, int]("table", HitPolicyFirst).AddRow(1, failing)
+
+		_, err := table.Decide(shippingInput{})
+		if !errors.Is(err, ErrEvaluationFailed) || !strings.Contains(err.Error(), `decision table "table" row 1`) {
+			t.Errorf("Expected a wrapped evaluation error, got %v", err)
+		}
+	})
+}
+
+func TestDecisionTable_DecideDetailed(t *testing.T) {
+	t.Parallel()
+
+	table := NewDecisionTable[shippingInput, float64]("shipping fee", HitPolicyFirst, "customer", "order amount").
+		AddRow(0, isPremiumCustomer, nil).
+		AddRow(0, nil, amountOver50).
+		AddRow(2.99, nil, nil).
+		Default(4.99)
+
+	decision := table.DecideDetailed(shippingInput{amount: 75})
+	if decision.Outcome != 0 || len(decision.Rows) != 1 || decision.Rows[0] != 1 || decision.Default {
+		t.Fatalf("Unexpected decision: %+v", decision)
+	}
+
+	// Evaluation stops at the first matching row
+	rows := decision.Result.Children
+	if len(rows) != 2 || rows[0].Satisfied || !rows[1].Satisfied || rows[1].RuleName != "row 2" {
+		t.Errorf("Unexpected row results: %v", decision.Result)
+	}
+	if len(rows[0].Children) != 1 || rows[0].Children[0].RuleName != "is premium" {
+		t.Errorf("Expected the row's condition result, got %v", rows[0])
+	}
+
+	// Evaluators report the rows as children as well
+	result := NewEvaluator[shippingInput](table).EvaluateDetailed(shippingInput{amount: 75})
+	if !result.Satisfied || len(result.Children) != 2 {
+		t.Errorf("Unexpected evaluator result: %v", result)
+	}
+}
+
+func TestDecisionTable_Analyze(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unreachable row", func(t *testing.T) {
+		t.Parallel()
+
+		table := NewDecisionTable[shippingInput, int]("table", HitPolicyFirst, "customer", "order amount").
+			AddRow(1, isPremiumCustomer, nil).
+			AddRow(2, isPremiumCustomer, amountOver50)
+
+		findings, err := table.Analyze()
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if !hasFinding(findings, FindingUnreachableRow, "table > row 2") {
+			t.Errorf("Expected row 2 to be unreachable, got %v", findings)
+		}
+	})
+
+	t.Run("overlapping rows", func(t *testing.T) {
+		t.Parallel()
+
+		table := NewDecisionTable[shippingInput, int]("table", HitPolicyUnique, "customer", "order amount").
+			AddRow(1, isPremiumCustomer, nil).
+			AddRow(2, nil, amountOver50)
+
+		findings, err := table.Analyze(shippingInput{premium: true, amount: 100}, shippingInput{})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if !hasFinding(findings, FindingOverlappingRows, "table") {
+			t.Errorf("Expected overlapping rows, got %v", findings)
+		}
+		if !hasFinding(findings, FindingMissingRow, "table") {
+			t.Errorf("Expected a missing row, got %v", findings)
+		}
+	})
+
+	t.Run("malformed row", func(t *testing.T) {
+		t.Parallel()
+
+		table := NewDecisionTable[shippingInput, int]("table", HitPolicyFirst, "customer", "order amount").
+			AddRow(1, isPremiumCustomer)
+
+		findings, _ := table.Analyze()
+		if !hasFinding(findings, FindingMalformedRow, "table > row 1") {
+			t.Errorf("Expected a malformed row, got %v", findings)
+		}
+	})
+
+	t.Run("well-formed table", func(t *testing.T) {
+		t.Parallel()
+
+		table := NewDecisionTable[shippingInput, int]("table", HitPolicyUnique, "customer", "order amount").
+			AddRow(1, isPremiumCustomer, nil).
+			AddRow(2, Not("not premium", isPremiumCustomer), amountOver50).
+			Default(3)
+
+		findings, err := table.Analyze(shippingInput{premium: true}, shippingInput{amount: 100}, shippingInput{})
+		if err != nil || len(findings) > 0 {
+			t.Errorf("Analyze() = %v, %v; want no findings", findings, err)
+		}
+	})
+}
+
+func TestDecisionTableDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	table := NewDecisionTable[shippingInput, float64]("shipping fee", HitPolicyPriority, "customer", "order amount").
+		AddPriorityRow(2, 0, isPremiumCustomer, nil).
+		AddPriorityRow(1, 1.99, nil, amountOver50).
+		Default(4.99)
+	_ = Register(table, WithDomain(TestOrderDomain))
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"### shipping fee (DECISION_TABLE)",
+		"**Hit policy**: PRIORITY",
+		"| # | customer | order amount | Priority | Outcome |",
+		"| 1 | is premium | - | 2 | 0 |",
+		"| otherwise | - | - | - | 4.99 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		`<table class="decision-table">`,
+		"<th>order amount</th>",
+		"<td>amount over 50</td>",
+	} {
+		if !strings.Contains(htmlDoc, want) {
+			t.Errorf("HTML should contain %q", want)
+		}
+	}
+}
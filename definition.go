@@ -0,0 +1,417 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidDefinition is returned when a rule definition is malformed,
+	// e.g. has an unknown type or the wrong number of rules.
+	ErrInvalidDefinition = errors.New("invalid rule definition")
+	// ErrUnknownPredicate is returned when a rule definition refers to a
+	// predicate that is not registered.
+	ErrUnknownPredicate = errors.New("unknown predicate")
+	// ErrNotExportable is returned when a rule tree contains a rule that
+	// cannot be expressed as a rule definition.
+	ErrNotExportable = errors.New("rule cannot be exported")
+)
+
+// RuleDefinition is the declarative form of a rule tree, suitable for
+// keeping the structure of composite rules in JSON or YAML files. Leaves
+// refer to predicates: rules constructed in Go code and registered under a
+// ref name (see WithRefName).
+//
+// A definition is either a predicate reference, which sets Predicate (and
+// optionally Name, for readability), or a composite, which sets Type and
+// Rules. The supported types are AND, OR, NOT, AT_LEAST, EXACTLY, AT_MOST
+// and NONE_OF; quantifiers other than NONE_OF also set Threshold.
+//
+// The fields have JSON and YAML tags, so YAML files can be decoded into a
+// RuleDefinition with a YAML library of choice and loaded with Load.
+//
+// Example (JSON):
+//
+//	{
+//	  "name": "free shipping",
+//	  "type": "OR",
+//	  "domains": ["orders"],
+//	  "rules": [
+//	    {"predicate": "customer.premium"},
+//	    {"predicate": "order.amount-over-100"}
+//	  ]
+//	}
+type RuleDefinition struct {
+	Name        string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Type        string              `json:"type,omitempty" yaml:"type,omitempty"`
+	Predicate   string              `json:"predicate,omitempty" yaml:"predicate,omitempty"`
+	Threshold   int                 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Domains     []Domain            `json:"domains,omitempty" yaml:"domains,omitempty"`
+	Group       string              `json:"group,omitempty" yaml:"group,omitempty"`
+	RefName     string              `json:"refName,omitempty" yaml:"refName,omitempty"`
+	Metadata    *MetadataDefinition `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Rules       []RuleDefinition    `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// MetadataDefinition is the declarative form of RuleMetadata. Times are
+// formatted as RFC 3339.
+type MetadataDefinition struct {
+	RequirementID       string   `json:"requirementId,omitempty" yaml:"requirementId,omitempty"`
+	BusinessDescription string   `json:"businessDescription,omitempty" yaml:"businessDescription,omitempty"`
+	Owner               string   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Version             string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Tags                []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	CreatedAt           string   `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+	UpdatedAt           string   `json:"updatedAt,omitempty" yaml:"updatedAt,omitempty"`
+	RelatedRules        []string `json:"relatedRules,omitempty" yaml:"relatedRules,omitempty"`
+	Dependencies        []Domain `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// Load builds the rule tree of a definition, resolving predicates in the
+// default registry. See LoadIn.
+func Load[T any](def RuleDefinition) (Rule[T], error) {
+	return LoadIn[T](DefaultRegistry, def)
+}
+
+// LoadIn builds the rule tree of a definition, resolving predicates by ref
+// name in the given registry. The root rule, and every composite with
+// domains, a group, a description, a ref name or metadata, is registered
+// in the registry accordingly, so loaded rules are documented like
+// hand-written ones.
+//
+// Predicates are resolved only if the registry implements Lookuper.
+// Loading fails with ErrUnknownPredicate if a predicate is not registered,
+// with ErrReferenceType if it has a different input type, and with
+// ErrInvalidDefinition if the definition is malformed. Errors name the
+// path of the failing definition, e.g. "free shipping > customer.premium".
+// If loading fails, the rules built so far are unregistered again.
+func LoadIn[T any](registry Registry, def RuleDefinition) (Rule[T], error) {
+	l := &loader[T]{registry: registry}
+	rule, err := l.load(def, []string{def.label("rule")}, true)
+	if err != nil {
+		unregisterBuilt(registry, l.built)
+		return nil, err
+	}
+
+	for _, registration := range l.registrations {
+		if err := registry.Register(registration.rule, registration.opts...); err != nil {
+			unregisterBuilt(registry, l.built)
+			return nil, definitionError(registration.path, "%w", err)
+		}
+	}
+	return rule, nil
+}
+
+// LoadJSON decodes a JSON rule definition and loads it with Load. Unknown
+// fields are rejected.
+func LoadJSON[T any](data []byte) (Rule[T], error) {
+	var def RuleDefinition
+	if err := decodeJSONDefinition(data, &def); err != nil {
+		return nil, fmt.Errorf("decoding rule definition: %w", err)
+	}
+	return Load[T](def)
+}
+
+// decodeJSONDefinition decodes a JSON rule definition, rejecting unknown
+// fields.
+func decodeJSONDefinition(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// loader builds rule trees from definitions and collects the composites
+// it builds and their registrations, the root's last.
+type loader[T any] struct {
+	registry      Registry
+	built         []any
+	registrations []definedRegistration
+}
+
+// definedRegistration is the registration of a rule built from a
+// definition.
+type definedRegistration struct {
+	rule any
+	opts []RegistrationOption
+	path []string
+}
+
+// load builds the rule of a definition at the given path.
+func (l *loader[T]) load(def RuleDefinition, path []string, root bool) (Rule[T], error) {
+	if def.Predicate != "" {
+		return l.loadPredicate(def, path)
+	}
+
+	if def.Name == "" {
+		return nil, definitionError(path, "%w: missing name", ErrInvalidDefinition)
+	}
+
+	children := make([]Rule[T], len(def.Rules))
+	for i, childDef := range def.Rules {
+		childPath := append(path[:len(path):len(path)], childDef.label(fmt.Sprintf("rules[%d]", i)))
+		child, err := l.load(childDef, childPath, false)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = child
+	}
+
+	rule, err := buildDefinedRule(def, children)
+	if err != nil {
+		return nil, definitionError(path, "%w", err)
+	}
+	l.built = append(l.built, rule)
+
+	if root || def.hasRegistration() {
+		opts, err := def.registrationOptions()
+		if err != nil {
+			return nil, definitionError(path, "%w", err)
+		}
+		l.registrations = append(l.registrations, definedRegistration{rule: rule, opts: opts, path: path})
+	}
+
+	return rule, nil
+}
+
+// unregisterBuilt removes the composites built from a definition from the
+// registry, and from the default registry, in which composites with domains
+// register themselves when they are built (see And).
+func unregisterBuilt(registry Registry, built []any) {
+	for _, rule := range built {
+		unregister(registry, rule)
+		unregister(DefaultRegistry, rule)
+	}
+}
+
+// loadPredicate resolves a predicate reference.
+func (l *loader[T]) loadPredicate(def RuleDefinition, path []string) (Rule[T], error) {
+	if def.Type != "" || len(def.Rules) > 0 || def.Threshold != 0 || def.hasRegistration() {
+		return nil, definitionError(path, "%w: predicate references only have a name", ErrInvalidDefinition)
+	}
+
+	registered, ok := lookup(l.registry, def.Predicate)
+	if !ok {
+		return nil, definitionError(path, "%w %q", ErrUnknownPredicate, def.Predicate)
+	}
+	rule, ok := registered.Rule.(Rule[T])
+	if !ok {
+		return nil, definitionError(path, "%w: predicate %q is a %T", ErrReferenceType, def.Predicate, registered.Rule)
+	}
+	return rule, nil
+}
+
+// buildDefinedRule builds a composite rule of a definition.
+func buildDefinedRule[T any](def RuleDefinition, children []Rule[T]) (Rule[T], error) {
+	if def.Type == "" {
+		return nil, fmt.Errorf("%w: missing type or predicate", ErrInvalidDefinition)
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("%w: %s has no rules", ErrInvalidDefinition, def.Type)
+	}
+
+	switch def.Type {
+	case "AND":
+		return And(def.Name, children...), nil
+	case "OR":
+		return Or(def.Name, children...), nil
+	case "NOT":
+		if len(children) != 1 {
+			return nil, fmt.Errorf("%w: NOT has %d rules, expected 1", ErrInvalidDefinition, len(children))
+		}
+		return Not(def.Name, children[0]), nil
+	case "AT_LEAST":
+		return AtLeast(def.Name, def.Threshold, children...), nil
+	case "EXACTLY":
+		return Exactly(def.Name, def.Threshold, children...), nil
+	case "AT_MOST":
+		return AtMost(def.Name, def.Threshold, children...), nil
+	case "NONE_OF":
+		return NoneOf(def.Name, children...), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalidDefinition, def.Type)
+	}
+}
+
+// label returns the name of the definition used in error paths: its name,
+// its predicate, or fallback.
+func (def RuleDefinition) label(fallback string) string {
+	switch {
+	case def.Name != "":
+		return def.Name
+	case def.Predicate != "":
+		return def.Predicate
+	default:
+		return fallback
+	}
+}
+
+// hasRegistration reports whether the definition sets registration
+// details.
+func (def RuleDefinition) hasRegistration() bool {
+	return len(def.Domains) > 0 || def.Group != "" || def.Description != "" ||
+		def.RefName != "" || def.Metadata != nil
+}
+
+// registrationOptions returns the registration options of a definition.
+func (def RuleDefinition) registrationOptions() ([]RegistrationOption, error) {
+	var opts []RegistrationOption
+	if def.Group != "" {
+		opts = append(opts, WithGroup(def.Group, def.Domains...))
+	} else if len(def.Domains) > 0 {
+		opts = append(opts, WithDomains(def.Domains...))
+	}
+	if def.Description != "" {
+		opts = append(opts, WithRegistrationDescription(def.Description))
+	}
+	if def.RefName != "" {
+		opts = append(opts, WithRefName(def.RefName))
+	}
+	if def.Metadata != nil {
+		metadata, err := def.Metadata.toRuleMetadata()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRegistrationMetadata(metadata))
+	}
+	return opts, nil
+}
+
+// toRuleMetadata converts the definition to RuleMetadata.
+func (m *MetadataDefinition) toRuleMetadata() (RuleMetadata, error) {
+	metadata := RuleMetadata{
+		RequirementID:       m.RequirementID,
+		BusinessDescription: m.BusinessDescription,
+		Owner:               m.Owner,
+		Version:             m.Version,
+		Tags:                m.Tags,
+		RelatedRules:        m.RelatedRules,
+		Dependencies:        m.Dependencies,
+	}
+
+	var err error
+	if m.CreatedAt != "" {
+		if metadata.CreatedAt, err = time.Parse(time.RFC3339, m.CreatedAt); err != nil {
+			return RuleMetadata{}, fmt.Errorf("%w: createdAt: %w", ErrInvalidDefinition, err)
+		}
+	}
+	if m.UpdatedAt != "" {
+		if metadata.UpdatedAt, err = time.Parse(time.RFC3339, m.UpdatedAt); err != nil {
+			return RuleMetadata{}, fmt.Errorf("%w: updatedAt: %w", ErrInvalidDefinition, err)
+		}
+	}
+	return metadata, nil
+}
+
+// newMetadataDefinition converts RuleMetadata to its definition.
+func newMetadataDefinition(metadata *RuleMetadata) *MetadataDefinition {
+	def := &MetadataDefinition{
+		RequirementID:       metadata.RequirementID,
+		BusinessDescription: metadata.BusinessDescription,
+		Owner:               metadata.Owner,
+		Version:             metadata.Version,
+		Tags:                metadata.Tags,
+		RelatedRules:        metadata.RelatedRules,
+		Dependencies:        metadata.Dependencies,
+	}
+	if !metadata.CreatedAt.IsZero() {
+		def.CreatedAt = metadata.CreatedAt.Format(time.RFC3339)
+	}
+	if !metadata.UpdatedAt.IsZero() {
+		def.UpdatedAt = metadata.UpdatedAt.Format(time.RFC3339)
+	}
+	return def
+}
+
+// definitionError wraps an error with the path of the failing definition.
+func definitionError(path []string, format string, args ...any) error {
+	return fmt.Errorf("loading rule definition %q: %w", strings.Join(path, " > "), fmt.Errorf(format, args...))
+}
+
+// Export converts a rule tree to its definition, looking up registrations
+// in the default registry. See ExportIn.
+func Export[T any](rule Rule[T]) (RuleDefinition, error) {
+	return ExportIn(DefaultRegistry, rule)
+}
+
+// ExportIn converts a rule tree to its definition, the inverse of LoadIn.
+// AND, OR, NOT and quantifier rules become composites carrying their
+// registered domains, group, description, ref name and metadata. Any other
+// rule becomes a predicate reference and must be registered under a ref
+// name (see WithRefName), otherwise ExportIn fails with ErrNotExportable.
+func ExportIn[T any](registry Registry, rule Rule[T]) (RuleDefinition, error) {
+	registrations := make(map[uintptr]RegisteredRule)
+	for _, registered := range registry.AllRules() {
+		registrations[getRulePointer(registered.Rule)] = registered
+	}
+	return exportRule(registrations, rule, nil)
+}
+
+// ExportJSON converts a rule tree to its indented JSON definition, looking
+// up registrations in the default registry.
+func ExportJSON[T any](rule Rule[T]) ([]byte, error) {
+	def, err := Export(rule)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(def, "", "  ")
+}
+
+// exportRule converts a rule to its definition. path holds the names of its
+// ancestors.
+func exportRule[T any](registrations map[uintptr]RegisteredRule, rule Rule[T], path []string) (RuleDefinition, error) {
+	if rule == nil {
+		return RuleDefinition{}, fmt.Errorf("exporting rule %q: %w", strings.Join(path, " > "), ErrNilRule)
+	}
+	path = append(path[:len(path):len(path)], rule.Name())
+	registered := registrations[getRulePointer(rule)]
+
+	var (
+		def      RuleDefinition
+		children []Rule[T]
+	)
+	switch r := rule.(type) {
+	case *andRule[T]:
+		def.Type, children = RuleTypeAnd.String(), r.rules
+	case *orRule[T]:
+		def.Type, children = RuleTypeOr.String(), r.rules
+	case *notRule[T]:
+		def.Type, children = RuleTypeNot.String(), []Rule[T]{r.rule}
+	case *quantifierRule[T]:
+		def.Type, children = r.kind.String(), r.rules
+		if r.kind != RuleTypeNoneOf {
+			def.Threshold = r.n
+		}
+	default:
+		if registered.RefName == "" {
+			return RuleDefinition{}, fmt.Errorf(
+				"exporting rule %q: %w: %T is not registered under a ref name",
+				strings.Join(path, " > "), ErrNotExportable, rule,
+			)
+		}
+		return RuleDefinition{Name: rule.Name(), Predicate: registered.RefName}, nil
+	}
+
+	def.Name = rule.Name()
+	def.Description = registered.Description
+	def.Domains = registered.Domains
+	def.Group = registered.Group
+	def.RefName = registered.RefName
+	if registered.Metadata != nil {
+		def.Metadata = newMetadataDefinition(registered.Metadata)
+	}
+
+	for _, child := range children {
+		childDef, err := exportRule(registrations, child, path)
+		if err != nil {
+			return RuleDefinition{}, err
+		}
+		def.Rules = append(def.Rules, childDef)
+	}
+
+	return def, nil
+}
@@ -0,0 +1,319 @@
+package rules
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newPredicateRegistry returns a registry with the predicates of the rule
+// definitions registered under ref names.
+func newPredicateRegistry(t *testing.T) Registry {
+	t.Helper()
+
+	registry := NewRegistry()
+	predicates := map[string]any{
+		"order.amount-over-100": New("amount over 100", func(o TestOrder) (bool, error) {
+			return o.Amount > 100, nil
+		}),
+		"order.domestic": New("domestic", func(o TestOrder) (bool, error) {
+			return o.Country == "SE", nil
+		}),
+		"order.blocked-country": New("blocked country", func(o TestOrder) (bool, error) {
+			return o.Country == "XX", nil
+		}),
+		"user.vip": New("vip", func(u TestUser) (bool, error) {
+			return u.IsVIP, nil
+		}),
+	}
+	for refName, predicate := range predicates {
+		if err := registry.Register(predicate, WithRefName(refName)); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	return registry
+}
+
+// freeShippingDefinition is a definition using every registration detail.
+var freeShippingDefinition = RuleDefinition{
+	Name:        "free shipping",
+	Type:        "AND",
+	Description: "Orders that ship for free",
+	Group:       "Shipping",
+	Domains:     []Domain{TestOrderDomain},
+	RefName:     "order.free-shipping",
+	Metadata: &MetadataDefinition{
+		RequirementID: "SHIP-1",
+		Owner:         "logistics",
+		CreatedAt:     "2025-01-01T00:00:00Z",
+	},
+	Rules: []RuleDefinition{
+		{
+			Name:      "qualifies",
+			Type:      "AT_LEAST",
+			Threshold: 1,
+			Rules: []RuleDefinition{
+				{Name: "amount over 100", Predicate: "order.amount-over-100"},
+				{Name: "domestic", Predicate: "order.domestic"},
+			},
+		},
+		{
+			Name:  "not blocked",
+			Type:  "NOT",
+			Rules: []RuleDefinition{{Name: "blocked country", Predicate: "order.blocked-country"}},
+		},
+	},
+}
+
+func TestLoadIn(t *testing.T) {
+	t.Parallel()
+
+	registry := newPredicateRegistry(t)
+	rule, err := LoadIn[TestOrder](registry, freeShippingDefinition)
+	if err != nil {
+		t.Fatalf("LoadIn() error = %v", err)
+	}
+
+	tests := []struct {
+		order TestOrder
+		want  bool
+	}{
+		{order: TestOrder{Amount: 150, Country: "US"}, want: true},
+		{order: TestOrder{Amount: 50, Country: "SE"}, want: true},
+		{order: TestOrder{Amount: 50, Country: "US"}, want: false},
+		{order: TestOrder{Amount: 150, Country: "XX"}, want: false},
+	}
+	for _, tt := range tests {
+		if got, err := rule.Evaluate(tt.order); err != nil || got != tt.want {
+			t.Errorf("Evaluate(%+v) = %v, %v; want %v", tt.order, got, err, tt.want)
+		}
+	}
+
+	registered, ok := lookup(registry, "order.free-shipping")
+	if !ok {
+		t.Fatal("Expected the loaded rule to be registered under its ref name")
+	}
+	if registered.Group != "Shipping" || len(registered.Domains) != 1 || registered.Description != "Orders that ship for free" {
+		t.Errorf("Unexpected registration: %+v", registered)
+	}
+	if registered.Metadata == nil || registered.Metadata.RequirementID != "SHIP-1" || registered.Metadata.CreatedAt.Year() != 2025 {
+		t.Errorf("Unexpected metadata: %+v", registered.Metadata)
+	}
+}
+
+func TestLoadIn_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		def      RuleDefinition
+		wantErr  error
+		wantPath string
+	}{
+		{
+			name:     "unknown predicate",
+			def:      RuleDefinition{Name: "root", Type: "OR", Rules: []RuleDefinition{{Predicate: "order.unknown"}}},
+			wantErr:  ErrUnknownPredicate,
+			wantPath: "root > order.unknown",
+		},
+		{
+			name:     "type mismatch",
+			def:      RuleDefinition{Name: "root", Type: "OR", Rules: []RuleDefinition{{Predicate: "user.vip"}}},
+			wantErr:  ErrReferenceType,
+			wantPath: "root > user.vip",
+		},
+		{
+			name:     "unsupported type",
+			def:      RuleDefinition{Name: "root", Type: "XOR", Rules: []RuleDefinition{{Predicate: "order.domestic"}}},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root",
+		},
+		{
+			name: "missing type",
+			def: RuleDefinition{Name: "root", Type: "AND", Rules: []RuleDefinition{
+				{Rules: []RuleDefinition{{Predicate: "order.domestic"}}},
+			}},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root > rules[0]",
+		},
+		{
+			name: "not with two rules",
+			def: RuleDefinition{Name: "root", Type: "NOT", Rules: []RuleDefinition{
+				{Predicate: "order.domestic"}, {Predicate: "order.amount-over-100"},
+			}},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root",
+		},
+		{
+			name:     "no rules",
+			def:      RuleDefinition{Name: "root", Type: "AND"},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root",
+		},
+		{
+			name: "registered predicate reference",
+			def: RuleDefinition{Name: "root", Type: "AND", Rules: []RuleDefinition{
+				{Predicate: "order.domestic", Domains: []Domain{TestOrderDomain}},
+			}},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root > order.domestic",
+		},
+		{
+			name: "invalid metadata time",
+			def: RuleDefinition{
+				Name:     "root",
+				Type:     "AND",
+				Metadata: &MetadataDefinition{CreatedAt: "yesterday"},
+				Rules:    []RuleDefinition{{Predicate: "order.domestic"}},
+			},
+			wantErr:  ErrInvalidDefinition,
+			wantPath: "root",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadIn[TestOrder](newPredicateRegistry(t), tt.def)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadIn() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), `loading rule definition "`+tt.wantPath+`"`) {
+				t.Errorf("Expected the error to name %q, got %v", tt.wantPath, err)
+			}
+		})
+	}
+}
+
+func TestLoad_UnregistersOnError(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	// Composites of predicates with domains register themselves when built
+	_ = Register(New("domestic", func(o TestOrder) (bool, error) {
+		return o.Country == "SE", nil
+	}), WithDomain(TestOrderDomain), WithRefName("order.domestic"))
+
+	tests := []struct {
+		name    string
+		def     RuleDefinition
+		wantErr error
+	}{
+		{
+			name: "later child fails to load",
+			def: RuleDefinition{Name: "root", Type: "AND", Rules: []RuleDefinition{
+				{Name: "a", Type: "OR", Rules: []RuleDefinition{{Predicate: "order.domestic"}}},
+				{Predicate: "order.unknown"},
+			}},
+			wantErr: ErrUnknownPredicate,
+		},
+		{
+			name: "registration fails",
+			def: RuleDefinition{Name: "root", Type: "AND", Rules: []RuleDefinition{
+				{Name: "a", Type: "OR", RefName: "dup", Rules: []RuleDefinition{{Predicate: "order.domestic"}}},
+				{Name: "b", Type: "OR", RefName: "dup", Rules: []RuleDefinition{{Predicate: "order.domestic"}}},
+			}},
+			wantErr: ErrDuplicateRefName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load[TestOrder](tt.def); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+			if rules := DefaultRegistry.AllRules(); len(rules) != 1 {
+				t.Errorf("Expected only the predicate to stay registered, got %d rules", len(rules))
+			}
+			if _, ok := Lookup("dup"); ok {
+				t.Error("Expected the ref name to be free")
+			}
+		})
+	}
+}
+
+func TestExportIn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		registry := newPredicateRegistry(t)
+		rule, err := LoadIn[TestOrder](registry, freeShippingDefinition)
+		if err != nil {
+			t.Fatalf("LoadIn() error = %v", err)
+		}
+
+		def, err := ExportIn(registry, rule)
+		if err != nil {
+			t.Fatalf("ExportIn() error = %v", err)
+		}
+		if !reflect.DeepEqual(def, freeShippingDefinition) {
+			t.Errorf("ExportIn() = %+v, want %+v", def, freeShippingDefinition)
+		}
+	})
+
+	t.Run("unregistered leaf", func(t *testing.T) {
+		t.Parallel()
+
+		rule := Or("root", New("anonymous", func(o TestOrder) (bool, error) { return true, nil }))
+		_, err := ExportIn(NewRegistry(), rule)
+		if !errors.Is(err, ErrNotExportable) || !strings.Contains(err.Error(), `"root > anonymous"`) {
+			t.Errorf("Expected ErrNotExportable, got %v", err)
+		}
+	})
+}
+
+func TestLoadJSON(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	_ = Register(New("amount over 100", func(o TestOrder) (bool, error) {
+		return o.Amount > 100, nil
+	}), WithRefName("order.amount-over-100"))
+	_ = Register(New("domestic", func(o TestOrder) (bool, error) {
+		return o.Country == "SE", nil
+	}), WithRefName("order.domestic"))
+
+	data := []byte(`{
+		"name": "free shipping",
+		"type": "OR",
+		"domains": ["order"],
+		"rules": [
+			{"predicate": "order.amount-over-100"},
+			{"predicate": "order.domestic"}
+		]
+	}`)
+
+	rule, err := LoadJSON[TestOrder](data)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if got, err := rule.Evaluate(TestOrder{Country: "SE"}); err != nil || !got {
+		t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+	}
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	if !strings.Contains(md, "### free shipping (OR)") {
+		t.Errorf("Markdown should document the loaded rule:\n%s", md)
+	}
+
+	exported, err := ExportJSON(rule)
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	for _, want := range []string{`"type": "OR"`, `"domains": [`, `"predicate": "order.domestic"`} {
+		if !strings.Contains(string(exported), want) {
+			t.Errorf("Exported JSON should contain %q:\n%s", want, exported)
+		}
+	}
+
+	if _, err := LoadJSON[TestOrder]([]byte(`{"name": "x", "typ": "OR"}`)); err == nil {
+		t.Error("Expected unknown fields to be rejected")
+	}
+}
@@ -82,12 +82,23 @@
 // Use an Evaluator to get detailed results including child rule results:
 //
 //	evaluator := rules.NewEvaluator(rule)
-//	result := evaluator.EvaluateDetailed(ctx, input)
+//	result := evaluator.EvaluateDetailed(input)
 //
 //	// Check results
 //	fmt.Println(result.String())  // Pretty-printed tree
 //	fmt.Println(result.Duration)  // Evaluation time
 //
+// # Context Support
+//
+// Use NewContext for predicates that need cancellation or deadlines, and
+// EvaluateContext or Evaluator.EvaluateDetailedContext to evaluate a tree
+// with a context:
+//
+//	rule := rules.NewContext("in stock", func(ctx context.Context, o Order) (bool, error) {
+//	    return inventory.InStock(ctx, o.SKU)
+//	})
+//	satisfied, err := rules.EvaluateContext(ctx, rule, order)
+//
 // # Error Handling
 //
 // The package follows Go best practices for error handling:
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // Documentation Generation System
@@ -104,6 +105,23 @@ type DocumentOptions struct {
 
 	// ShowCrossDomainLinks highlights cross-domain dependencies
 	ShowCrossDomainLinks bool
+
+	// EffectiveAt filters to rules in effect at this time (zero = all);
+	// see Effective
+	EffectiveAt time.Time
+
+	// IncludeTruthTables adds the truth table of each documented composite
+	// rule (see NewTruthTable)
+	IncludeTruthTables bool
+
+	// MaxTruthTableRows limits the rows of truth tables (0 =
+	// DefaultMaxTruthTableRows); larger tables are left out
+	MaxTruthTableRows int
+
+	// Coverage overlays a coverage report on HTML documentation: every rule
+	// gets a coverage badge and the rules evaluators were created for get
+	// their MC/DC coverage (see CoverageRecorder)
+	Coverage *CoverageReport
 }
 
 // RuleType represents the type of a rule.
@@ -120,6 +138,42 @@ const (
 	RuleTypeNot
 	// RuleTypeUnknown represents an unknown rule type.
 	RuleTypeUnknown
+	// RuleTypeAtLeast represents a rule satisfied by at least N children.
+	RuleTypeAtLeast
+	// RuleTypeExactly represents a rule satisfied by exactly N children.
+	RuleTypeExactly
+	// RuleTypeAtMost represents a rule satisfied by at most N children.
+	RuleTypeAtMost
+	// RuleTypeNoneOf represents a rule satisfied when no child is satisfied.
+	RuleTypeNoneOf
+	// RuleTypeMapped represents a rule evaluated on a value mapped from the
+	// input (see Map).
+	RuleTypeMapped
+	// RuleTypeComposite represents a user-defined composite rule (see
+	// CompositeRule).
+	RuleTypeComposite
+	// RuleTypeForAll represents a rule satisfied by every element of a
+	// collection.
+	RuleTypeForAll
+	// RuleTypeForAny represents a rule satisfied by at least one element of
+	// a collection.
+	RuleTypeForAny
+	// RuleTypeForNone represents a rule satisfied by no element of a
+	// collection.
+	RuleTypeForNone
+	// RuleTypeCountAtLeast represents a rule satisfied by at least N
+	// elements of a collection.
+	RuleTypeCountAtLeast
+	// RuleTypeReference represents a reference to a registered rule (see
+	// Ref).
+	RuleTypeReference
+	// RuleTypeDecisionTable represents a decision table (see
+	// DecisionTable).
+	RuleTypeDecisionTable
+	// RuleTypeScorecard represents a scorecard (see Scorecard).
+	RuleTypeScorecard
+	// RuleTypeRuleSet represents a rule set (see RuleSet).
+	RuleTypeRuleSet
 )
 
 // String returns the string representation of a RuleType.
@@ -133,11 +187,133 @@ func (rt RuleType) String() string {
 		return "OR"
 	case RuleTypeNot:
 		return "NOT"
+	case RuleTypeAtLeast:
+		return "AT_LEAST"
+	case RuleTypeExactly:
+		return "EXACTLY"
+	case RuleTypeAtMost:
+		return "AT_MOST"
+	case RuleTypeNoneOf:
+		return "NONE_OF"
+	case RuleTypeMapped:
+		return "MAPPED"
+	case RuleTypeComposite:
+		return "COMPOSITE"
+	case RuleTypeForAll:
+		return "FOR_ALL"
+	case RuleTypeForAny:
+		return "FOR_ANY"
+	case RuleTypeForNone:
+		return "FOR_NONE"
+	case RuleTypeCountAtLeast:
+		return "COUNT_AT_LEAST"
+	case RuleTypeReference:
+		return "REF"
+	case RuleTypeDecisionTable:
+		return "DECISION_TABLE"
+	case RuleTypeScorecard:
+		return "SCORECARD"
+	case RuleTypeRuleSet:
+		return "RULE_SET"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// isQuantifier reports whether the rule type counts satisfied children.
+func (rt RuleType) isQuantifier() bool {
+	switch rt {
+	case RuleTypeAtLeast, RuleTypeExactly, RuleTypeAtMost, RuleTypeNoneOf:
+		return true
+	default:
+		return false
+	}
+}
+
+// isCollection reports whether the rule type evaluates a rule against the
+// elements of a collection.
+func (rt RuleType) isCollection() bool {
+	switch rt {
+	case RuleTypeForAll, RuleTypeForAny, RuleTypeForNone, RuleTypeCountAtLeast:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasThreshold reports whether rules of this type compare against a number
+// of satisfied children or elements.
+func (rt RuleType) hasThreshold() bool {
+	return rt.isQuantifier() || rt == RuleTypeCountAtLeast
+}
+
+// quantifierPhrase describes how many children of a quantifier rule must be
+// satisfied, e.g. "at least 2 of".
+func quantifierPhrase(ruleType RuleType, threshold int) string {
+	switch ruleType {
+	case RuleTypeAtLeast:
+		return fmt.Sprintf("at least %d of", threshold)
+	case RuleTypeExactly:
+		return fmt.Sprintf("exactly %d of", threshold)
+	case RuleTypeAtMost:
+		return fmt.Sprintf("at most %d of", threshold)
+	case RuleTypeNoneOf:
+		return "none of"
+	default:
+		return ""
+	}
+}
+
+// quantifierHeader returns the sentence introducing the children of a
+// quantifier rule, e.g. "At least 2 of these conditions must be satisfied:".
+func quantifierHeader(ruleType RuleType, threshold int) string {
+	if ruleType == RuleTypeNoneOf {
+		return "None of these conditions may be satisfied:"
+	}
+
+	phrase := quantifierPhrase(ruleType, threshold)
+	return strings.ToUpper(phrase[:1]) + phrase[1:] + " these conditions must be satisfied:"
+}
+
+// collectionPhrase describes for how many elements of a collection rule the
+// element rule must be satisfied, e.g. "for each item".
+func collectionPhrase(ruleType RuleType, threshold int) string {
+	switch ruleType {
+	case RuleTypeForAll:
+		return "for each item"
+	case RuleTypeForAny:
+		return "for at least one item"
+	case RuleTypeForNone:
+		return "for no item"
+	case RuleTypeCountAtLeast:
+		return fmt.Sprintf("for at least %d items", threshold)
+	default:
+		return ""
+	}
+}
+
+// collectionHeader returns the sentence introducing the element rule of a
+// collection rule, e.g. "For each item (LineItem), this condition must be
+// satisfied:".
+func collectionHeader(node *ruleNode) string {
+	phrase := collectionPhrase(node.Type, node.Threshold)
+	phrase = strings.ToUpper(phrase[:1]) + phrase[1:]
+	if node.TargetType != "" {
+		phrase += fmt.Sprintf(" (%s)", node.TargetType)
+	}
+
+	if node.Type == RuleTypeForNone {
+		return phrase + " may this condition be satisfied:"
+	}
+	return phrase + ", this condition must be satisfied:"
+}
+
+// mappedHeader returns the sentence introducing the wrapped rule of a mapped
+// rule, e.g. "Applied to the User mapped from Request:".
+func mappedHeader(node *ruleNode) string {
+	return fmt.Sprintf("Applied to the %s mapped from %s:", node.TargetType, node.SourceType)
+}
+
 // ruleNode represents a node in the rule hierarchy tree.
 type ruleNode struct {
 	Rule        any
@@ -147,12 +323,45 @@ type ruleNode struct {
 	Domains     []Domain
 	Group       string
 	Metadata    *RuleMetadata
-	Children    []*ruleNode
-	Depth       int
+	Severity    Severity
+	HasSeverity bool
+	Threshold   int
+	SourceType  string
+	TargetType  string
+	RefName     string // ref name the rule is registered under
+	Reference   string // ref name of the rule a reference refers to
+	// Validity window of effective-dated rules
+	EffectiveFrom  time.Time
+	EffectiveUntil time.Time
+	HasWindow      bool
+	Table          *decisionTableLayout // rows of decision tables
+	Scorecard      *scorecardLayout     // criteria and thresholds of scorecards
+	RuleSet        *ruleSetLayout       // rules and outcomes of rule sets
+	Children       []*ruleNode
+	Depth          int
+}
+
+// typeLabel returns the rule type as shown in documentation, including the
+// mapped types of mapped rules, e.g. "MAPPED(User ← Request)", and the
+// element type of collection rules, e.g. "FOR_ALL(LineItem)".
+func (n *ruleNode) typeLabel() string {
+	if n.Type == RuleTypeMapped && n.TargetType != "" {
+		return fmt.Sprintf("%s(%s ← %s)", n.Type, n.TargetType, n.SourceType)
+	}
+	if n.Type.isCollection() && n.TargetType != "" {
+		return fmt.Sprintf("%s(%s)", n.Type, n.TargetType)
+	}
+	return n.Type.String()
 }
 
 // getRuleType detects the type of a rule through reflection.
 func getRuleType(rule any) RuleType {
+	// Rules that know their own kind, including user-defined composites,
+	// take precedence
+	if typed, ok := rule.(interface{ Kind() RuleType }); ok {
+		return typed.Kind()
+	}
+
 	// Use reflection to check the underlying type name
 	typeName := fmt.Sprintf("%T", rule)
 
@@ -225,38 +434,71 @@ func buildRuleTree(rule any, registered *RegisteredRule, depth int, maxDepth int
 		return nil
 	}
 
+	// Decorating rules are documented as the rule they wrap
+	structure := unwrapRule(rule)
+
 	node := &ruleNode{
 		Rule:  rule,
 		Name:  getRuleName(rule),
-		Type:  getRuleType(rule),
+		Type:  getRuleType(structure),
 		Depth: depth,
 	}
+	node.Severity, node.HasSeverity = explicitSeverity(rule)
+	node.EffectiveFrom, node.EffectiveUntil, node.HasWindow = effectiveWindow(rule)
+
+	// Add the threshold of quantifier rules
+	if quantifier, ok := structure.(interface{ Threshold() int }); ok {
+		node.Threshold = quantifier.Threshold()
+	}
+
+	// Add the source and target types of mapped rules
+	if mapped, ok := structure.(interface {
+		SourceType() string
+		TargetType() string
+	}); ok {
+		node.SourceType = mapped.SourceType()
+		node.TargetType = mapped.TargetType()
+	}
+
+	// Add the ref name of references; they link to their target instead of
+	// documenting it again
+	if ref, ok := structure.(reference); ok {
+		node.Reference = ref.RefName()
+	}
+
+	// Add the rows of decision tables
+	if table, ok := structure.(interface{ layout() *decisionTableLayout }); ok {
+		node.Table = table.layout()
+	}
+
+	// Add the criteria and thresholds of scorecards
+	if scorecard, ok := structure.(interface{ layout() *scorecardLayout }); ok {
+		node.Scorecard = scorecard.layout()
+	}
+
+	// Add the rules and outcomes of rule sets
+	if ruleSet, ok := structure.(interface{ layout() *ruleSetLayout }); ok {
+		node.RuleSet = ruleSet.layout()
+	}
 
 	// Add metadata from registry if available
 	if registered != nil {
 		node.Description = registered.Description
 		node.Domains = registered.Domains
 		node.Group = registered.Group
+		node.RefName = registered.RefName
 		node.Metadata = registered.Metadata
 	}
 
 	// Build children
-	children := getChildren(rule)
+	children := getChildren(structure)
 	for _, child := range children {
 		if child == nil {
 			continue
 		}
 
 		// Look up child in registry
-		var childRegistered *RegisteredRule
-		allRules := AllRules()
-		childPtr := getRulePointer(child)
-		for _, r := range allRules {
-			if getRulePointer(r.Rule) == childPtr {
-				childRegistered = &r
-				break
-			}
-		}
+		childRegistered := lookupRegisteredRule(child)
 
 		childNode := buildRuleTree(child, childRegistered, depth+1, maxDepth)
 		if childNode != nil {
@@ -267,6 +509,31 @@ func buildRuleTree(rule any, registered *RegisteredRule, depth int, maxDepth int
 	return node
 }
 
+// documentRuleTree builds the tree of a registered rule as configured by
+// the document options. With EffectiveAt set, child rules that are not in
+// effect at that time are left out.
+func documentRuleTree(regRule *RegisteredRule, opts DocumentOptions) *ruleNode {
+	node := buildRuleTree(regRule.Rule, regRule, 0, opts.MaxDepth)
+	if !opts.EffectiveAt.IsZero() {
+		pruneIneffectiveRules(node, opts.EffectiveAt)
+	}
+	return node
+}
+
+// pruneIneffectiveRules removes the descendants of a node that are not in
+// effect at time t.
+func pruneIneffectiveRules(node *ruleNode, t time.Time) {
+	children := node.Children[:0]
+	for _, child := range node.Children {
+		if child.HasWindow && !withinWindow(t, child.EffectiveFrom, child.EffectiveUntil) {
+			continue
+		}
+		pruneIneffectiveRules(child, t)
+		children = append(children, child)
+	}
+	node.Children = children
+}
+
 // groupRulesByGroup groups rules by their group name.
 func groupRulesByGroup(rules []RegisteredRule) map[string][]RegisteredRule {
 	grouped := make(map[string][]RegisteredRule)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -268,6 +269,56 @@ func writeHTMLHeader(sb *strings.Builder, opts DocumentOptions) {
         .rule-card .type-and { background: #27ae60; color: white; }
         .rule-card .type-or { background: #f39c12; color: white; }
         .rule-card .type-not { background: #e74c3c; color: white; }
+        .rule-card .type-at_least,
+        .rule-card .type-exactly,
+        .rule-card .type-at_most,
+        .rule-card .type-none_of { background: #8e44ad; color: white; }
+        .rule-card .type-mapped { background: #16a085; color: white; }
+        .rule-card .type-composite { background: #34495e; color: white; }
+        .rule-card .type-for_all,
+        .rule-card .type-for_any,
+        .rule-card .type-for_none,
+        .rule-card .type-count_at_least { background: #d35400; color: white; }
+        .rule-card .type-ref { background: #7f8c8d; color: white; }
+        .rule-card .type-decision_table { background: #c0392b; color: white; }
+        .rule-card .type-scorecard { background: #2980b9; color: white; }
+        .rule-card .type-rule_set { background: #d35400; color: white; }
+
+        .rule-card .severity-badge {
+            display: inline-block;
+            padding: 4px 10px;
+            border-radius: 4px;
+            font-size: 0.75rem;
+            font-weight: bold;
+            margin-left: 6px;
+            text-transform: uppercase;
+        }
+
+        .rule-card .severity-error { background: #c0392b; color: white; }
+        .rule-card .severity-warning { background: #f1c40f; color: #333; }
+        .rule-card .severity-info { background: #95a5a6; color: white; }
+
+        .rule-card .effective-badge {
+            display: inline-block;
+            padding: 4px 10px;
+            border-radius: 4px;
+            font-size: 0.75rem;
+            margin-left: 6px;
+            background: #ecf0f1;
+            color: #2c3e50;
+        }
+
+        .rule-card .coverage-badge {
+            display: inline-block;
+            padding: 4px 10px;
+            border-radius: 4px;
+            font-size: 0.75rem;
+            margin-left: 6px;
+        }
+
+        .rule-card .coverage-full { background: #27ae60; color: white; }
+        .rule-card .coverage-partial { background: #f39c12; color: white; }
+        .rule-card .coverage-none { background: #c0392b; color: white; }
 
         .rule-card .description {
             color: #555;
@@ -348,6 +399,54 @@ func writeHTMLHeader(sb *strings.Builder, opts DocumentOptions) {
             font-size: 1.1rem;
         }
 
+        .hit-policy {
+            margin-top: 15px;
+            color: #2c3e50;
+        }
+
+        .decision-table {
+            width: 100%;
+            margin-top: 10px;
+            border-collapse: collapse;
+            font-size: 0.9rem;
+        }
+
+        .decision-table th,
+        .decision-table td {
+            padding: 8px 12px;
+            border: 1px solid #dee2e6;
+            text-align: left;
+        }
+
+        .decision-table th {
+            background: #ecf0f1;
+            color: #2c3e50;
+        }
+
+        .table-caption {
+            margin-top: 15px;
+            color: #2c3e50;
+        }
+
+        .rule-table {
+            width: 100%;
+            margin-top: 10px;
+            border-collapse: collapse;
+            font-size: 0.9rem;
+        }
+
+        .rule-table th,
+        .rule-table td {
+            padding: 8px 12px;
+            border: 1px solid #dee2e6;
+            text-align: left;
+        }
+
+        .rule-table th {
+            background: #ecf0f1;
+            color: #2c3e50;
+        }
+
         .collapsible-content {
             max-height: 10000px;
             overflow: hidden;
@@ -512,24 +611,31 @@ func writeHTMLFlatRules(sb *strings.Builder, rules []RegisteredRule, opts Docume
 // writeHTMLRule writes a single rule card.
 func writeHTMLRule(sb *strings.Builder, regRule RegisteredRule, opts DocumentOptions) {
 	// Build the rule tree
-	node := buildRuleTree(regRule.Rule, &regRule, 0, opts.MaxDepth)
+	node := documentRuleTree(&regRule, opts)
 
 	ruleID := fmt.Sprintf("rule-%p", regRule.Rule)
 
-	sb.WriteString(`                <div class="rule-card">
+	// Anchor rules that references can link to
+	if node.RefName != "" {
+		sb.WriteString(fmt.Sprintf(`                <div class="rule-card" id="%s">
+`, html.EscapeString(referenceAnchor(node.RefName))))
+	} else {
+		sb.WriteString(`                <div class="rule-card">
 `)
+	}
 
 	// Rule header
 	sb.WriteString(fmt.Sprintf(`                    <h3 class="rule-header" onclick="toggleRule('%s')">
                         <span class="toggle-icon">▼</span>
                         <span>%s</span>
-                        <span class="type-badge type-%s">%s</span>
+                        <span class="type-badge type-%s">%s</span>%s
                     </h3>
 `,
 		ruleID,
 		html.EscapeString(node.Name),
 		strings.ToLower(node.Type.String()),
-		html.EscapeString(node.Type.String())))
+		html.EscapeString(node.typeLabel()),
+		htmlSeverityBadge(node)+htmlEffectiveBadge(node)+htmlCoverageBadge(node, opts)))
 
 	// Collapsible content
 	sb.WriteString(fmt.Sprintf(`                    <div class="collapsible-content" id="%s">
@@ -584,6 +690,23 @@ func writeHTMLRule(sb *strings.Builder, regRule RegisteredRule, opts DocumentOpt
 	sb.WriteString(`                        </div>
 `)
 
+	// Decision table rows, scorecard weights and rule set outcomes
+	if node.Table != nil {
+		writeHTMLDecisionTable(sb, node.Table)
+	}
+	if node.Scorecard != nil {
+		writeHTMLScorecard(sb, node.Scorecard)
+	}
+	if node.RuleSet != nil {
+		writeHTMLRuleSet(sb, node.RuleSet)
+	}
+	if opts.IncludeTruthTables {
+		writeHTMLTruthTable(sb, node, opts)
+	}
+	if opts.Coverage != nil {
+		writeHTMLDecisionCoverage(sb, node, opts.Coverage)
+	}
+
 	// Children
 	if len(node.Children) > 0 {
 		writeHTMLChildren(sb, node, opts)
@@ -688,6 +811,15 @@ func writeHTMLChildren(sb *strings.Builder, node *ruleNode, opts DocumentOptions
 	case RuleTypeNot:
 		sb.WriteString(`                            <div class="children-header">Negation of:</div>
 `)
+	case RuleTypeAtLeast, RuleTypeExactly, RuleTypeAtMost, RuleTypeNoneOf:
+		sb.WriteString(fmt.Sprintf(`                            <div class="children-header">%s</div>
+`, html.EscapeString(quantifierHeader(node.Type, node.Threshold))))
+	case RuleTypeMapped:
+		sb.WriteString(fmt.Sprintf(`                            <div class="children-header">%s</div>
+`, html.EscapeString(mappedHeader(node))))
+	case RuleTypeForAll, RuleTypeForAny, RuleTypeForNone, RuleTypeCountAtLeast:
+		sb.WriteString(fmt.Sprintf(`                            <div class="children-header">%s</div>
+`, html.EscapeString(collectionHeader(node))))
 	default:
 		sb.WriteString(`                            <div class="children-header">Child rules:</div>
 `)
@@ -706,11 +838,18 @@ func writeHTMLChildren(sb *strings.Builder, node *ruleNode, opts DocumentOptions
 func writeHTMLChildRule(sb *strings.Builder, child *ruleNode, opts DocumentOptions) {
 	sb.WriteString(`                            <div class="child-rule">
                                 <h4>`)
-	sb.WriteString(html.EscapeString(child.Name))
-	sb.WriteString(fmt.Sprintf(` <span class="type-badge type-%s">%s</span></h4>
+	if child.Reference != "" {
+		sb.WriteString(fmt.Sprintf(`<a href="#%s">%s</a>`,
+			html.EscapeString(referenceAnchor(child.Reference)),
+			html.EscapeString(child.Reference)))
+	} else {
+		sb.WriteString(html.EscapeString(child.Name))
+	}
+	sb.WriteString(fmt.Sprintf(` <span class="type-badge type-%s">%s</span>%s</h4>
 `,
 		strings.ToLower(child.Type.String()),
-		html.EscapeString(child.Type.String())))
+		html.EscapeString(child.typeLabel()),
+		htmlSeverityBadge(child)+htmlEffectiveBadge(child)+htmlCoverageBadge(child, opts)))
 
 	if child.Description != "" {
 		sb.WriteString(`                                <div class="description">`)
@@ -732,6 +871,16 @@ func writeHTMLChildRule(sb *strings.Builder, child *ruleNode, opts DocumentOptio
 `)
 	}
 
+	if child.Table != nil {
+		writeHTMLDecisionTable(sb, child.Table)
+	}
+	if child.Scorecard != nil {
+		writeHTMLScorecard(sb, child.Scorecard)
+	}
+	if child.RuleSet != nil {
+		writeHTMLRuleSet(sb, child.RuleSet)
+	}
+
 	// Recursively write children
 	if len(child.Children) > 0 {
 		writeHTMLChildren(sb, child, opts)
@@ -741,6 +890,165 @@ func writeHTMLChildRule(sb *strings.Builder, child *ruleNode, opts DocumentOptio
 `)
 }
 
+// writeHTMLDecisionTable writes the rows of a decision table.
+func writeHTMLDecisionTable(sb *strings.Builder, table *decisionTableLayout) {
+	sb.WriteString(fmt.Sprintf(`                        <div class="hit-policy"><strong>Hit policy</strong>: %s</div>
+                        <table class="decision-table">
+                            <thead>
+                                <tr>`, table.HitPolicy))
+	for _, cell := range table.header() {
+		sb.WriteString(fmt.Sprintf("<th>%s</th>", html.EscapeString(cell)))
+	}
+	sb.WriteString(`</tr>
+                            </thead>
+                            <tbody>
+`)
+
+	for _, row := range table.rows() {
+		sb.WriteString(`                                <tr>`)
+		for _, cell := range row {
+			sb.WriteString(fmt.Sprintf("<td>%s</td>", html.EscapeString(cell)))
+		}
+		sb.WriteString(`</tr>
+`)
+	}
+
+	sb.WriteString(`                            </tbody>
+                        </table>
+`)
+}
+
+// writeHTMLRuleSet writes the rules and outcomes of a rule set.
+func writeHTMLRuleSet(sb *strings.Builder, ruleSet *ruleSetLayout) {
+	writeHTMLTable(sb, fmt.Sprintf("Strategy: %s", ruleSet.Strategy), ruleSet.header(), ruleSet.rows())
+}
+
+// writeHTMLScorecard writes the criteria and thresholds of a scorecard.
+func writeHTMLScorecard(sb *strings.Builder, scorecard *scorecardLayout) {
+	rows := make([][]string, len(scorecard.Criteria))
+	for i, criterion := range scorecard.Criteria {
+		rows[i] = []string{criterion.Name, formatPoints(criterion.Points)}
+	}
+	writeHTMLTable(sb, "Scoring", []string{"Criterion", "Points"}, rows)
+
+	if len(scorecard.Thresholds) == 0 {
+		return
+	}
+
+	rows = make([][]string, len(scorecard.Thresholds))
+	for i, threshold := range scorecard.Thresholds {
+		rows[i] = []string{threshold.Score, threshold.Outcome}
+	}
+	writeHTMLTable(sb, "Thresholds", []string{"Score", "Outcome"}, rows)
+}
+
+// writeHTMLTruthTable writes the truth table of a composite rule, or why it
+// was left out.
+func writeHTMLTruthTable(sb *strings.Builder, node *ruleNode, opts DocumentOptions) {
+	table, err := documentTruthTable(node.Rule, opts)
+	switch {
+	case err != nil:
+		sb.WriteString(fmt.Sprintf(`                        <div class="table-caption"><strong>Truth table</strong> omitted: %s</div>
+`, html.EscapeString(err.Error())))
+	case table != nil:
+		writeHTMLTable(sb, table.caption(), table.header(), table.rows())
+	}
+}
+
+// writeHTMLDecisionCoverage writes the MC/DC coverage of a rule that
+// evaluators were created for.
+func writeHTMLDecisionCoverage(sb *strings.Builder, node *ruleNode, report *CoverageReport) {
+	decision, ok := report.decision(node.Rule)
+	if !ok {
+		return
+	}
+
+	rows := make([][]string, len(decision.Conditions))
+	for i, condition := range decision.Conditions {
+		rows[i] = []string{
+			condition.Rule,
+			strconv.Itoa(condition.True),
+			strconv.Itoa(condition.False),
+			strconv.Itoa(condition.Pairs),
+		}
+	}
+	caption := fmt.Sprintf("MC/DC coverage: %d/%d conditions (%d evaluations)",
+		decision.Independent(), len(decision.Conditions), decision.Evaluations)
+	writeHTMLTable(sb, caption, []string{"Condition", "True", "False", "Independence pairs"}, rows)
+}
+
+// writeHTMLTable writes a captioned table of escaped cells.
+func writeHTMLTable(sb *strings.Builder, caption string, header []string, rows [][]string) {
+	sb.WriteString(fmt.Sprintf(`                        <div class="table-caption"><strong>%s</strong></div>
+                        <table class="rule-table">
+                            <thead>
+                                <tr>`, html.EscapeString(caption)))
+	for _, cell := range header {
+		sb.WriteString(fmt.Sprintf("<th>%s</th>", html.EscapeString(cell)))
+	}
+	sb.WriteString(`</tr>
+                            </thead>
+                            <tbody>
+`)
+
+	for _, row := range rows {
+		sb.WriteString(`                                <tr>`)
+		for _, cell := range row {
+			sb.WriteString(fmt.Sprintf("<td>%s</td>", html.EscapeString(cell)))
+		}
+		sb.WriteString(`</tr>
+`)
+	}
+
+	sb.WriteString(`                            </tbody>
+                        </table>
+`)
+}
+
+// htmlEffectiveBadge returns the validity window badge for a rule node, or
+// an empty string if the rule is not effective-dated.
+func htmlEffectiveBadge(node *ruleNode) string {
+	if !node.HasWindow {
+		return ""
+	}
+
+	return fmt.Sprintf(` <span class="effective-badge">%s</span>`,
+		html.EscapeString(formatEffectiveWindow(node.EffectiveFrom, node.EffectiveUntil)))
+}
+
+// htmlCoverageBadge returns the coverage badge for a rule node, or an empty
+// string if no coverage report is overlaid.
+func htmlCoverageBadge(node *ruleNode, opts DocumentOptions) string {
+	if opts.Coverage == nil {
+		return ""
+	}
+
+	coverage, ok := opts.Coverage.rule(node.Rule)
+	if !ok {
+		return ` <span class="coverage-badge coverage-none">not evaluated</span>`
+	}
+	class := "coverage-partial"
+	if coverage.Covered() {
+		class = "coverage-full"
+	}
+	return fmt.Sprintf(` <span class="coverage-badge %s" title="%s">%s</span>`,
+		class,
+		html.EscapeString(coverage.counts()),
+		html.EscapeString(coverage.label()))
+}
+
+// htmlSeverityBadge returns the severity badge for a rule node, or an empty
+// string if no severity was set on the rule.
+func htmlSeverityBadge(node *ruleNode) string {
+	if !node.HasSeverity {
+		return ""
+	}
+
+	return fmt.Sprintf(` <span class="severity-badge severity-%s">%s</span>`,
+		node.Severity,
+		html.EscapeString(node.Severity.String()))
+}
+
 // writeHTMLFooter writes the HTML footer with JavaScript.
 func writeHTMLFooter(sb *strings.Builder) {
 	sb.WriteString(`    </div>
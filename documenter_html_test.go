@@ -695,3 +695,33 @@ func TestGenerateHTML_HTMLEscaping(t *testing.T) {
 		t.Error("HTML should contain escaped angle brackets")
 	}
 }
+
+func TestGenerateHTML_Quantifiers(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	country := NewWithDomain("country check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Country == "US", nil
+	})
+	_ = Exactly("single match", 1, amount, country)
+
+	html, err := GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+
+	expectedContent := []string{
+		`<span class="type-badge type-exactly">EXACTLY</span>`,
+		`<div class="children-header">Exactly 1 of these conditions must be satisfied:</div>`,
+		".rule-card .type-exactly",
+	}
+
+	for _, expected := range expectedContent {
+		if !strings.Contains(html, expected) {
+			t.Errorf("HTML should contain %q", expected)
+		}
+	}
+}
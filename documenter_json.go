@@ -7,14 +7,23 @@ import (
 
 // JSONRuleDoc represents a rule in JSON documentation format.
 type JSONRuleDoc struct {
-	Name        string        `json:"name"`
-	Description string        `json:"description,omitempty"`
-	Type        string        `json:"type"`
-	Domains     []string      `json:"domains,omitempty"`
-	Group       string        `json:"group,omitempty"`
-	Metadata    *JSONMetadata `json:"metadata,omitempty"`
-	Children    []JSONRuleDoc `json:"children,omitempty"`
-	Depth       int           `json:"depth"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Severity    string `json:"severity,omitempty"`
+	Threshold   *int   `json:"threshold,omitempty"`
+	SourceType  string `json:"sourceType,omitempty"`
+	TargetType  string `json:"targetType,omitempty"`
+	RefName     string `json:"refName,omitempty"`
+	Ref         string `json:"ref,omitempty"`
+	// Validity window of effective-dated rules (RFC 3339)
+	EffectiveFrom  string        `json:"effectiveFrom,omitempty"`
+	EffectiveUntil string        `json:"effectiveUntil,omitempty"`
+	Domains        []string      `json:"domains,omitempty"`
+	Group          string        `json:"group,omitempty"`
+	Metadata       *JSONMetadata `json:"metadata,omitempty"`
+	Children       []JSONRuleDoc `json:"children,omitempty"`
+	Depth          int           `json:"depth"`
 }
 
 // JSONMetadata represents rule metadata in JSON format.
@@ -152,15 +161,35 @@ func GenerateGroupJSON(groupName string, opts DocumentOptions) (string, error) {
 // buildJSONRuleDoc builds a JSON rule documentation structure.
 func buildJSONRuleDoc(regRule RegisteredRule, opts DocumentOptions) JSONRuleDoc {
 	// Build the rule tree
-	node := buildRuleTree(regRule.Rule, &regRule, 0, opts.MaxDepth)
+	node := documentRuleTree(&regRule, opts)
 
 	ruleDoc := JSONRuleDoc{
 		Name:        node.Name,
 		Description: node.Description,
 		Type:        node.Type.String(),
+		SourceType:  node.SourceType,
+		TargetType:  node.TargetType,
+		RefName:     node.RefName,
+		Ref:         node.Reference,
 		Depth:       node.Depth,
 	}
 
+	// Add severity
+	if node.HasSeverity {
+		ruleDoc.Severity = node.Severity.String()
+	}
+
+	// Add validity window
+	if node.HasWindow {
+		ruleDoc.EffectiveFrom, ruleDoc.EffectiveUntil = jsonEffectiveWindow(node)
+	}
+
+	// Add quantifier threshold
+	if node.Type.hasThreshold() {
+		threshold := node.Threshold
+		ruleDoc.Threshold = &threshold
+	}
+
 	// Add domains
 	for _, domain := range node.Domains {
 		ruleDoc.Domains = append(ruleDoc.Domains, string(domain))
@@ -189,9 +218,29 @@ func buildJSONRuleDocFromNode(node *ruleNode, opts DocumentOptions) JSONRuleDoc
 		Name:        node.Name,
 		Description: node.Description,
 		Type:        node.Type.String(),
+		SourceType:  node.SourceType,
+		TargetType:  node.TargetType,
+		RefName:     node.RefName,
+		Ref:         node.Reference,
 		Depth:       node.Depth,
 	}
 
+	// Add severity
+	if node.HasSeverity {
+		ruleDoc.Severity = node.Severity.String()
+	}
+
+	// Add validity window
+	if node.HasWindow {
+		ruleDoc.EffectiveFrom, ruleDoc.EffectiveUntil = jsonEffectiveWindow(node)
+	}
+
+	// Add quantifier threshold
+	if node.Type.hasThreshold() {
+		threshold := node.Threshold
+		ruleDoc.Threshold = &threshold
+	}
+
 	// Add domains
 	for _, domain := range node.Domains {
 		ruleDoc.Domains = append(ruleDoc.Domains, string(domain))
@@ -240,3 +289,15 @@ func buildJSONMetadata(metadata *RuleMetadata) *JSONMetadata {
 
 	return jsonMeta
 }
+
+// jsonEffectiveWindow formats the validity window of a rule node, leaving
+// open sides empty.
+func jsonEffectiveWindow(node *ruleNode) (from, until string) {
+	if !node.EffectiveFrom.IsZero() {
+		from = node.EffectiveFrom.Format(time.RFC3339)
+	}
+	if !node.EffectiveUntil.IsZero() {
+		until = node.EffectiveUntil.Format(time.RFC3339)
+	}
+	return from, until
+}
@@ -648,3 +648,41 @@ func TestGenerateJSON_ValidFormat(t *testing.T) {
 		t.Error("Version should not be empty")
 	}
 }
+
+func TestGenerateJSON_Quantifiers(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	_ = AtMost("at most one", 0, amount)
+
+	out, err := GenerateJSON(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+
+	var doc JSONDocumentation
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	for _, rule := range doc.Rules {
+		if rule.Name != "at most one" {
+			continue
+		}
+		if rule.Type != "AT_MOST" {
+			t.Errorf("Type = %q, want %q", rule.Type, "AT_MOST")
+		}
+		if rule.Threshold == nil || *rule.Threshold != 0 {
+			t.Errorf("Threshold = %v, want 0", rule.Threshold)
+		}
+		if len(rule.Children) != 1 {
+			t.Errorf("Expected 1 child, got %d", len(rule.Children))
+		}
+		return
+	}
+
+	t.Error("JSON should contain the quantifier rule")
+}
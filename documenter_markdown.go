@@ -79,6 +79,11 @@ func filterRegisteredRules(rules []RegisteredRule, opts DocumentOptions) []Regis
 	var filtered []RegisteredRule
 
 	for _, rule := range rules {
+		// Check validity window
+		if !opts.EffectiveAt.IsZero() && !inEffectAt(rule.Rule, opts.EffectiveAt) {
+			continue
+		}
+
 		// Check exclude list
 		excluded := false
 		for _, exclude := range opts.ExcludeDomains {
@@ -195,11 +200,16 @@ func generateMarkdownFlat(rules []RegisteredRule, opts DocumentOptions, header s
 // generateRuleMarkdown generates Markdown documentation for a single rule.
 func generateRuleMarkdown(sb *strings.Builder, regRule RegisteredRule, opts DocumentOptions, headerLevel int) {
 	// Build the rule tree
-	node := buildRuleTree(regRule.Rule, &regRule, 0, opts.MaxDepth)
+	node := documentRuleTree(&regRule, opts)
+
+	// Anchor rules that references can link to
+	if node.RefName != "" {
+		sb.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n\n", referenceAnchor(node.RefName)))
+	}
 
 	// Write rule header
 	headerPrefix := strings.Repeat("#", headerLevel)
-	sb.WriteString(fmt.Sprintf("%s %s (%s)\n\n", headerPrefix, node.Name, node.Type))
+	sb.WriteString(fmt.Sprintf("%s %s (%s)\n\n", headerPrefix, markdownRuleName(node), node.typeLabel()))
 
 	// Write description
 	if node.Description != "" {
@@ -207,7 +217,15 @@ func generateRuleMarkdown(sb *strings.Builder, regRule RegisteredRule, opts Docu
 	}
 
 	// Write basic info
-	sb.WriteString(fmt.Sprintf("**Type**: %s\n\n", node.Type))
+	sb.WriteString(fmt.Sprintf("**Type**: %s\n\n", node.typeLabel()))
+
+	// Write severity
+	if node.HasSeverity {
+		sb.WriteString(fmt.Sprintf("**Severity**: %s\n\n", node.Severity))
+	}
+	if node.HasWindow {
+		sb.WriteString(fmt.Sprintf("**Effective**: %s\n\n", formatEffectiveWindow(node.EffectiveFrom, node.EffectiveUntil)))
+	}
 
 	// Write domains
 	if len(node.Domains) > 0 {
@@ -232,6 +250,20 @@ func generateRuleMarkdown(sb *strings.Builder, regRule RegisteredRule, opts Docu
 		writeMetadata(sb, node.Metadata)
 	}
 
+	// Write decision table rows, scorecard weights and rule set outcomes
+	if node.Table != nil {
+		writeDecisionTable(sb, node.Table)
+	}
+	if node.Scorecard != nil {
+		writeScorecard(sb, node.Scorecard)
+	}
+	if node.RuleSet != nil {
+		writeRuleSet(sb, node.RuleSet)
+	}
+	if opts.IncludeTruthTables {
+		writeTruthTable(sb, node, opts)
+	}
+
 	// Write children
 	if len(node.Children) > 0 {
 		writeChildren(sb, node, opts, headerLevel+1)
@@ -283,6 +315,12 @@ func writeChildren(sb *strings.Builder, node *ruleNode, opts DocumentOptions, he
 		sb.WriteString("**At least one of these conditions must be satisfied:**\n\n")
 	case RuleTypeNot:
 		sb.WriteString("**Negation of:**\n\n")
+	case RuleTypeAtLeast, RuleTypeExactly, RuleTypeAtMost, RuleTypeNoneOf:
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", quantifierHeader(node.Type, node.Threshold)))
+	case RuleTypeMapped:
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", mappedHeader(node)))
+	case RuleTypeForAll, RuleTypeForAny, RuleTypeForNone, RuleTypeCountAtLeast:
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", collectionHeader(node)))
 	default:
 		sb.WriteString("**Child rules:**\n\n")
 	}
@@ -293,15 +331,31 @@ func writeChildren(sb *strings.Builder, node *ruleNode, opts DocumentOptions, he
 	}
 }
 
+// markdownRuleName returns the name of a rule node, linking references to
+// the rule they refer to.
+func markdownRuleName(node *ruleNode) string {
+	if node.Reference != "" {
+		return fmt.Sprintf("[%s](#%s)", node.Reference, referenceAnchor(node.Reference))
+	}
+	return node.Name
+}
+
 // writeChildRule writes a single child rule.
 func writeChildRule(sb *strings.Builder, child *ruleNode, opts DocumentOptions, headerLevel int) {
 	headerPrefix := strings.Repeat("#", headerLevel)
-	sb.WriteString(fmt.Sprintf("%s %s (%s)\n\n", headerPrefix, child.Name, child.Type))
+	sb.WriteString(fmt.Sprintf("%s %s (%s)\n\n", headerPrefix, markdownRuleName(child), child.typeLabel()))
 
 	if child.Description != "" {
 		sb.WriteString(fmt.Sprintf("%s\n\n", child.Description))
 	}
 
+	if child.HasSeverity {
+		sb.WriteString(fmt.Sprintf("**Severity**: %s\n\n", child.Severity))
+	}
+	if child.HasWindow {
+		sb.WriteString(fmt.Sprintf("**Effective**: %s\n\n", formatEffectiveWindow(child.EffectiveFrom, child.EffectiveUntil)))
+	}
+
 	// Show domains for child if different from parent
 	if len(child.Domains) > 0 {
 		if len(child.Domains) == 1 {
@@ -315,12 +369,104 @@ func writeChildRule(sb *strings.Builder, child *ruleNode, opts DocumentOptions,
 		}
 	}
 
+	if child.Table != nil {
+		writeDecisionTable(sb, child.Table)
+	}
+	if child.Scorecard != nil {
+		writeScorecard(sb, child.Scorecard)
+	}
+	if child.RuleSet != nil {
+		writeRuleSet(sb, child.RuleSet)
+	}
+
 	// Recursively write children
 	if len(child.Children) > 0 {
 		writeChildren(sb, child, opts, headerLevel+1)
 	}
 }
 
+// writeDecisionTable writes the rows of a decision table as a Markdown
+// table.
+func writeDecisionTable(sb *strings.Builder, table *decisionTableLayout) {
+	sb.WriteString(fmt.Sprintf("**Hit policy**: %s\n\n", table.HitPolicy))
+	writeMarkdownTable(sb, table.header(), table.rows())
+}
+
+// writeRuleSet writes the rules and outcomes of a rule set as a Markdown
+// table.
+func writeRuleSet(sb *strings.Builder, ruleSet *ruleSetLayout) {
+	sb.WriteString(fmt.Sprintf("**Strategy**: %s\n\n", ruleSet.Strategy))
+	writeMarkdownTable(sb, ruleSet.header(), ruleSet.rows())
+}
+
+// writeTruthTable writes the truth table of a composite rule, or why it was
+// left out.
+func writeTruthTable(sb *strings.Builder, node *ruleNode, opts DocumentOptions) {
+	table, err := documentTruthTable(node.Rule, opts)
+	if table == nil && err == nil {
+		return
+	}
+
+	sb.WriteString("**Truth table:**\n\n")
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("*Omitted: %s*\n\n", err))
+		return
+	}
+	if table.Pruned {
+		sb.WriteString("*\"-\" marks a rule whose outcome does not matter.*\n\n")
+	}
+	writeMarkdownTable(sb, table.header(), table.rows())
+}
+
+// writeMarkdownTable writes a Markdown table with a header row.
+func writeMarkdownTable(sb *strings.Builder, header []string, rows [][]string) {
+	writeMarkdownTableRow(sb, header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownTableRow(sb, separator)
+
+	for _, row := range rows {
+		writeMarkdownTableRow(sb, row)
+	}
+	sb.WriteString("\n")
+}
+
+// writeScorecard writes the criteria and thresholds of a scorecard as
+// Markdown tables.
+func writeScorecard(sb *strings.Builder, scorecard *scorecardLayout) {
+	sb.WriteString("**Scoring:**\n\n")
+	writeMarkdownTableRow(sb, []string{"Criterion", "Points"})
+	writeMarkdownTableRow(sb, []string{"---", "---"})
+	for _, criterion := range scorecard.Criteria {
+		writeMarkdownTableRow(sb, []string{criterion.Name, formatPoints(criterion.Points)})
+	}
+	sb.WriteString("\n")
+
+	if len(scorecard.Thresholds) == 0 {
+		return
+	}
+
+	sb.WriteString("**Thresholds:**\n\n")
+	writeMarkdownTableRow(sb, []string{"Score", "Outcome"})
+	writeMarkdownTableRow(sb, []string{"---", "---"})
+	for _, threshold := range scorecard.Thresholds {
+		writeMarkdownTableRow(sb, []string{threshold.Score, threshold.Outcome})
+	}
+	sb.WriteString("\n")
+}
+
+// writeMarkdownTableRow writes a row of a Markdown table, escaping pipes in
+// the cells.
+func writeMarkdownTableRow(sb *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	sb.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+}
+
 // collectDomainsFromRegisteredRules collects unique domains from a list of registered rules.
 func collectDomainsFromRegisteredRules(rules []RegisteredRule) []Domain {
 	domainSet := make(map[Domain]bool)
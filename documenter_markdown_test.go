@@ -526,3 +526,101 @@ func TestRuleType_String(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateMarkdown_Quantifiers(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	country := NewWithDomain("country check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Country == "US", nil
+	})
+	_ = AtLeast("premium order", 1, amount, country)
+	_ = NoneOf("blocked order", amount, country)
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+
+	expectedContent := []string{
+		"premium order (AT_LEAST)",
+		"**At least 1 of these conditions must be satisfied:**",
+		"blocked order (NONE_OF)",
+		"**None of these conditions may be satisfied:**",
+	}
+
+	for _, expected := range expectedContent {
+		if !strings.Contains(md, expected) {
+			t.Errorf("Markdown should contain %q", expected)
+		}
+	}
+}
+
+func TestGenerateMarkdown_MappedRules(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	country := NewWithDomain("country check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Country == "US", nil
+	})
+	_ = Map("order from context", And("order validation", amount, country), func(ctx TestContext) TestOrder {
+		return ctx.Order
+	})
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+
+	expectedContent := []string{
+		"### order from context (MAPPED(TestOrder ← TestContext))",
+		"**Applied to the TestOrder mapped from TestContext:**",
+		"#### order validation (AND)",
+		"##### amount check (SIMPLE)",
+	}
+
+	for _, expected := range expectedContent {
+		if !strings.Contains(md, expected) {
+			t.Errorf("Markdown should contain %q:\n%s", expected, md)
+		}
+	}
+}
+
+func TestGenerateMarkdown_CollectionRules(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	_ = ForAll("every order", amount, func(orders []TestOrder) []TestOrder {
+		return orders
+	})
+	_ = ForNone("no small order", amount, func(orders []TestOrder) []TestOrder {
+		return orders
+	})
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+
+	expectedContent := []string{
+		"### every order (FOR_ALL(TestOrder))",
+		"**For each item (TestOrder), this condition must be satisfied:**",
+		"**For no item (TestOrder) may this condition be satisfied:**",
+		"#### amount check (SIMPLE)",
+	}
+
+	for _, expected := range expectedContent {
+		if !strings.Contains(md, expected) {
+			t.Errorf("Markdown should contain %q:\n%s", expected, md)
+		}
+	}
+}
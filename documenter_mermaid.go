@@ -161,15 +161,27 @@ func writeMermaidRule(sb *strings.Builder, regRule *RegisteredRule, opts Documen
 	indent := strings.Repeat("    ", indentLevel)
 
 	// Build the rule tree to get type information
-	node := buildRuleTree(regRule.Rule, regRule, 0, opts.MaxDepth)
+	node := documentRuleTree(regRule, opts)
 
 	// Generate node ID
 	nodeID := getMermaidNodeID(regRule.Rule)
 
 	// Generate node label
 	label := node.Name
-	if opts.IncludeMetadata {
-		label = fmt.Sprintf("%s<br/>%s", label, node.Type.String())
+	if opts.IncludeMetadata || node.Type == RuleTypeMapped {
+		label = fmt.Sprintf("%s<br/>%s", label, node.typeLabel())
+	}
+	if node.Type.isQuantifier() {
+		label = fmt.Sprintf("%s<br/>%s", label, quantifierPhrase(node.Type, node.Threshold))
+	}
+	if node.Type.isCollection() {
+		label = fmt.Sprintf("%s<br/>%s", label, collectionPhrase(node.Type, node.Threshold))
+	}
+	if node.HasSeverity {
+		label = fmt.Sprintf("%s<br/>[%s]", label, node.Severity)
+	}
+	if node.HasWindow {
+		label = fmt.Sprintf("%s<br/>%s", label, formatEffectiveWindow(node.EffectiveFrom, node.EffectiveUntil))
 	}
 
 	// Choose node shape based on rule type
@@ -187,7 +199,7 @@ func writeMermaidRule(sb *strings.Builder, regRule *RegisteredRule, opts Documen
 // writeMermaidConnections writes connections between parent and child rules.
 func writeMermaidConnections(sb *strings.Builder, regRule *RegisteredRule, opts DocumentOptions) {
 	// Build rule tree
-	node := buildRuleTree(regRule.Rule, regRule, 0, opts.MaxDepth)
+	node := documentRuleTree(regRule, opts)
 
 	if len(node.Children) == 0 {
 		return
@@ -202,6 +214,18 @@ func writeMermaidConnections(sb *strings.Builder, regRule *RegisteredRule, opts
 		// Determine arrow style based on parent type
 		arrow := getConnectionArrow(node.Type)
 
+		// Label edges to children that carry a severity or validity window
+		var edgeLabels []string
+		if child.HasSeverity {
+			edgeLabels = append(edgeLabels, child.Severity.String())
+		}
+		if child.HasWindow {
+			edgeLabels = append(edgeLabels, formatEffectiveWindow(child.EffectiveFrom, child.EffectiveUntil))
+		}
+		if len(edgeLabels) > 0 {
+			arrow = fmt.Sprintf("%s|%s|", arrow, strings.Join(edgeLabels, ", "))
+		}
+
 		sb.WriteString(fmt.Sprintf("    %s %s %s\n", parentID, arrow, childID))
 	}
 }
@@ -232,6 +256,10 @@ func getMermaidNodeShape(ruleType RuleType) mermaidNodeShape {
 		return mermaidNodeShape{Open: "{", Close: "}"} // Rhombus
 	case RuleTypeNot:
 		return mermaidNodeShape{Open: "[(", Close: ")]"} // Stadium
+	case RuleTypeAtLeast, RuleTypeExactly, RuleTypeAtMost, RuleTypeNoneOf:
+		return mermaidNodeShape{Open: "{{", Close: "}}"} // Hexagon
+	case RuleTypeForAll, RuleTypeForAny, RuleTypeForNone, RuleTypeCountAtLeast:
+		return mermaidNodeShape{Open: "[/", Close: "\\]"} // Trapezoid
 	default:
 		return mermaidNodeShape{Open: "[", Close: "]"} // Rectangle
 	}
@@ -252,7 +280,13 @@ func getConnectionArrow(parentType RuleType) string {
 }
 
 // getMermaidNodeID generates a unique node ID for Mermaid.
+// Decorating rules share the ID of the rule they wrap, and resolvable
+// references the ID of the rule they refer to, so that edges link to it.
 func getMermaidNodeID(rule any) string {
+	rule = unwrapRule(rule)
+	if target := resolveReference(rule); target != nil {
+		rule = unwrapRule(target)
+	}
 	ptr := getRulePointer(rule)
 	return fmt.Sprintf("R%X", ptr)
 }
@@ -711,3 +711,29 @@ func TestEscapeMermaidLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateMermaid_Quantifiers(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	country := NewWithDomain("country check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Country == "US", nil
+	})
+	premium := AtLeast("premium order", 2, amount, country)
+
+	mermaid, err := GenerateMermaid(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaid() error = %v", err)
+	}
+
+	expectedNode := getMermaidNodeID(premium) + `{{"premium order<br/>at least 2 of"}}`
+	if !strings.Contains(mermaid, expectedNode) {
+		t.Errorf("Mermaid should contain %q:\n%s", expectedNode, mermaid)
+	}
+	if !strings.Contains(mermaid, getMermaidNodeID(premium)+" --> "+getMermaidNodeID(amount)) {
+		t.Error("Mermaid should connect the quantifier to its children")
+	}
+}
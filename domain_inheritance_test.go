@@ -352,7 +352,7 @@ func TestNoneOf_DomainInheritance(t *testing.T) {
 
 	noneOfRule := NoneOf("none of", rule1, rule2)
 
-	// NoneOf creates Not(Or(...)), so should inherit all domains
+	// NoneOf should inherit all domains from its children
 	allRules := AllRules()
 	var noneOfRuleEntry *RegisteredRule
 	for _, r := range allRules {
@@ -606,3 +606,55 @@ func TestMixedRules_DomainInheritance(t *testing.T) {
 	// Clean up
 	DefaultRegistry.Clear()
 }
+
+func TestMap_DomainInheritance(t *testing.T) {
+	// Clear registry before test
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	orderRule := NewWithDomain("order rule", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+
+	mapped := Map("context order rule", orderRule, func(ctx TestContext) TestOrder {
+		return ctx.Order
+	})
+
+	registered := lookupRegisteredRule(mapped)
+	if registered == nil {
+		t.Fatal("Mapped rule not found in registry")
+	}
+
+	if len(registered.Domains) != 1 || registered.Domains[0] != TestOrderDomain {
+		t.Errorf("Mapped rule domains = %v, want [%v]", registered.Domains, TestOrderDomain)
+	}
+
+	// The domain should propagate further up the tree
+	parent := And("context rules", mapped)
+	if registered := lookupRegisteredRule(parent); registered == nil || len(registered.Domains) != 1 {
+		t.Error("Parent of mapped rule should inherit its domain")
+	}
+}
+
+func TestForAll_DomainInheritance(t *testing.T) {
+	// Clear registry before test
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	orderRule := NewWithDomain("order rule", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+
+	forAll := ForAll("all orders", orderRule, func(orders []TestOrder) []TestOrder {
+		return orders
+	})
+
+	registered := lookupRegisteredRule(forAll)
+	if registered == nil {
+		t.Fatal("ForAll rule not found in registry")
+	}
+
+	if len(registered.Domains) != 1 || registered.Domains[0] != TestOrderDomain {
+		t.Errorf("ForAll rule domains = %v, want [%v]", registered.Domains, TestOrderDomain)
+	}
+}
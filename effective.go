@@ -0,0 +1,169 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// effectiveRule decorates a rule with a validity window.
+type effectiveRule[T any] struct {
+	rule  Rule[T]
+	from  time.Time
+	until time.Time
+}
+
+// Effective wraps a rule so that it is only in effect from the time from
+// (inclusive) until the time until (exclusive). A zero from or until leaves
+// the window open on that side. The wrapped rule keeps its name and
+// structure.
+//
+// The evaluation time is taken from the context (see AsOf and WithClock)
+// and defaults to the current time. A rule that is not in effect is ignored
+// by And, Or, Not, the quantifiers, the collection rules and the parallel
+// composites, is reported as Inactive in detailed results, and is satisfied
+// when evaluated on its own.
+//
+// Example:
+//
+//	jan1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+//
+//	rule := rules.And("order validation",
+//	    rules.Effective(minimumAmount50, time.Time{}, jan1),
+//	    rules.Effective(minimumAmount100, jan1, time.Time{}),
+//	)
+func Effective[T any](rule Rule[T], from, until time.Time) Rule[T] {
+	return &effectiveRule[T]{
+		rule:  rule,
+		from:  from,
+		until: until,
+	}
+}
+
+func (r *effectiveRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *effectiveRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if r.rule == nil {
+		return false, fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule)
+	}
+
+	// A rule that is not in effect is vacuously satisfied
+	if !withinWindow(evaluationTime(ctx), r.from, r.until) {
+		return true, nil
+	}
+
+	return EvaluateContext(ctx, r.rule, input)
+}
+
+func (r *effectiveRule[T]) Name() string {
+	if r.rule == nil {
+		return "unnamed"
+	}
+	return r.rule.Name()
+}
+
+// EffectiveWindow returns the validity window of the rule. Zero times mean
+// the window is open on that side.
+func (r *effectiveRule[T]) EffectiveWindow() (from, until time.Time) {
+	return r.from, r.until
+}
+
+// Unwrap returns the wrapped rule.
+func (r *effectiveRule[T]) Unwrap() Rule[T] {
+	return r.rule
+}
+
+// unwrapRule returns the wrapped rule for introspection.
+func (r *effectiveRule[T]) unwrapRule() any {
+	return r.rule
+}
+
+// withinWindow reports whether t lies within [from, until), where zero
+// times leave the window open.
+func withinWindow(t, from, until time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !until.IsZero() && !t.Before(until) {
+		return false
+	}
+	return true
+}
+
+// effectiveWindow returns the validity window set on a rule or on any rule
+// it wraps, and whether one was set at all.
+func effectiveWindow(rule any) (from, until time.Time, ok bool) {
+	for rule != nil {
+		if w, isWindowed := rule.(interface {
+			EffectiveWindow() (time.Time, time.Time)
+		}); isWindowed {
+			from, until = w.EffectiveWindow()
+			return from, until, true
+		}
+
+		wrapper, isWrapper := rule.(wrappingRule)
+		if !isWrapper {
+			break
+		}
+		rule = wrapper.unwrapRule()
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// inEffectAt reports whether a rule is in effect at time t.
+func inEffectAt(rule any, t time.Time) bool {
+	from, until, ok := effectiveWindow(rule)
+	return !ok || withinWindow(t, from, until)
+}
+
+// inEffect reports whether a rule is in effect at the evaluation time of
+// the context.
+func inEffect(ctx context.Context, rule any) bool {
+	from, until, ok := effectiveWindow(rule)
+	return !ok || withinWindow(evaluationTime(ctx), from, until)
+}
+
+// evaluationTimeKey is the context key of the evaluation time.
+type evaluationTimeKey struct{}
+
+// AsOf returns a context that evaluates rules as of the time t, e.g. to
+// check which effective-dated rules (see Effective) applied in the past.
+func AsOf(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, evaluationTimeKey{}, t)
+}
+
+// evaluationTime returns the evaluation time of the context, or the current
+// time if none was set.
+func evaluationTime(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(evaluationTimeKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// formatEffectiveWindow describes a validity window, e.g.
+// "from 2025-01-01 until 2026-01-01".
+func formatEffectiveWindow(from, until time.Time) string {
+	switch {
+	case from.IsZero() && until.IsZero():
+		return "always"
+	case from.IsZero():
+		return fmt.Sprintf("until %s", formatEffectiveTime(until))
+	case until.IsZero():
+		return fmt.Sprintf("from %s", formatEffectiveTime(from))
+	default:
+		return fmt.Sprintf("from %s until %s", formatEffectiveTime(from), formatEffectiveTime(until))
+	}
+}
+
+// formatEffectiveTime formats a window boundary as a date, adding the time
+// of day when it is not midnight.
+func formatEffectiveTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,265 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	effectiveJan1 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	effectiveJul1 = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// minimumValue returns a rule that requires the input value to be at least
+// min.
+func minimumValue(min int) Rule[testInput] {
+	return New("minimum value", func(input testInput) (bool, error) {
+		return input.value >= min, nil
+	})
+}
+
+func TestEffective(t *testing.T) {
+	t.Parallel()
+
+	// The minimum value is raised from 50 to 100 on January 1st
+	rule := And("order validation",
+		Effective(minimumValue(50), time.Time{}, effectiveJan1),
+		Effective(minimumValue(100), effectiveJan1, time.Time{}),
+	)
+
+	tests := []struct {
+		name  string
+		asOf  time.Time
+		value int
+		want  bool
+	}{
+		{name: "old minimum met", asOf: effectiveJan1.Add(-time.Hour), value: 75, want: true},
+		{name: "old minimum not met", asOf: effectiveJan1.Add(-time.Hour), value: 25, want: false},
+		{name: "new minimum not met", asOf: effectiveJan1, value: 75, want: false},
+		{name: "new minimum met", asOf: effectiveJul1, value: 150, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := AsOf(context.Background(), tt.asOf)
+			got, err := EvaluateContext(ctx, rule, testInput{value: tt.value})
+			if err != nil {
+				t.Fatalf("EvaluateContext() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffective_Composites(t *testing.T) {
+	t.Parallel()
+
+	ctx := AsOf(context.Background(), effectiveJan1)
+	expired := Effective(Never[testInput]("expired"), time.Time{}, effectiveJan1)
+	upcoming := Effective(Always[testInput]("upcoming"), effectiveJul1, time.Time{})
+	upcomingNever := Effective(Never[testInput]("upcoming never"), effectiveJul1, time.Time{})
+	elements := func(testInput) []testInput { return []testInput{{}, {}} }
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+		want bool
+	}{
+		{name: "and ignores expired rule", rule: And("and", Always[testInput]("always"), expired), want: true},
+		{name: "or ignores upcoming rule", rule: Or("or", Never[testInput]("never"), upcoming), want: false},
+		{name: "at least ignores upcoming rule", rule: AtLeast("at least", 1, upcoming, Never[testInput]("never")), want: false},
+		{name: "none of ignores expired rule", rule: NoneOf("none of", expired), want: true},
+		{name: "not ignores upcoming rule", rule: Not("not", upcomingNever), want: true},
+		{name: "for all ignores upcoming rule", rule: ForAll("for all", upcomingNever, elements), want: true},
+		{name: "for any ignores upcoming rule", rule: ForAny("for any", upcoming, elements), want: false},
+		{name: "for none ignores upcoming rule", rule: ForNone("for none", upcoming, elements), want: true},
+		{name: "count at least ignores upcoming rule", rule: CountAtLeast("count at least", 1, upcoming, elements), want: false},
+		{name: "parallel and ignores expired rule", rule: ParallelAnd("parallel and", 0, Always[testInput]("always"), expired), want: true},
+		{name: "parallel or ignores upcoming rule", rule: ParallelOr("parallel or", 0, Never[testInput]("never"), upcoming), want: false},
+		{name: "rule on its own is satisfied", rule: expired, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EvaluateContext(ctx, tt.rule, testInput{})
+			if err != nil {
+				t.Fatalf("EvaluateContext() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateContext() = %v, want %v", got, tt.want)
+			}
+
+			detailed := NewEvaluator(tt.rule).EvaluateDetailedContext(ctx, testInput{})
+			if detailed.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailedContext() = %v, want %v", detailed.Satisfied, tt.want)
+			}
+
+			shortCircuit := NewEvaluator(tt.rule).EvaluateDetailedShortCircuitContext(ctx, testInput{})
+			if shortCircuit.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailedShortCircuitContext() = %v, want %v", shortCircuit.Satisfied, tt.want)
+			}
+
+			if compiled, err := Compile(tt.rule).EvaluateContext(ctx, testInput{}); err != nil || compiled != tt.want {
+				t.Errorf("Compile().EvaluateContext() = %v, %v; want %v", compiled, err, tt.want)
+			}
+
+			if truth, err := EvaluateTruth(ctx, tt.rule, testInput{}); err != nil || truth != truthOf(tt.want) {
+				t.Errorf("EvaluateTruth() = %v, %v; want %v", truth, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffective_NilRule(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Effective[testInput](nil, time.Time{}, time.Time{}).Evaluate(testInput{}); !errors.Is(err, ErrNilRule) {
+		t.Errorf("Expected ErrNilRule, got %v", err)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	rule := And("order validation",
+		Effective(minimumValue(100), effectiveJan1, time.Time{}),
+	)
+	clock := func() time.Time { return effectiveJan1.Add(-time.Hour) }
+	evaluator := NewEvaluator(rule, WithClock(clock))
+
+	t.Run("evaluates as of the clock", func(t *testing.T) {
+		t.Parallel()
+
+		if got, err := evaluator.EvaluateFast(testInput{value: 1}); err != nil || !got {
+			t.Errorf("EvaluateFast() = %v, %v; want true, nil", got, err)
+		}
+		if result := evaluator.Evaluate(testInput{value: 1}); !result.Satisfied {
+			t.Errorf("Evaluate() = %v, want satisfied", result)
+		}
+
+		result := evaluator.EvaluateDetailed(testInput{value: 1})
+		if !result.Satisfied {
+			t.Fatal("Expected rule to be satisfied")
+		}
+		child := result.Children[0]
+		if !child.Inactive || !child.Satisfied || child.RuleName != "minimum value" {
+			t.Errorf("Expected an inactive child, got %+v", child)
+		}
+		if !strings.Contains(result.String(), "[not in effect]") {
+			t.Errorf("Expected inactive marker in output, got:\n%s", result.String())
+		}
+	})
+
+	t.Run("context takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := AsOf(context.Background(), effectiveJul1)
+		result := evaluator.EvaluateDetailedContext(ctx, testInput{value: 1})
+		if result.Satisfied {
+			t.Error("Expected rule to not be satisfied")
+		}
+		if result.Children[0].Inactive {
+			t.Error("Expected the child to be in effect")
+		}
+	})
+}
+
+func TestFormatEffectiveWindow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from, until time.Time
+		want        string
+	}{
+		{want: "always"},
+		{from: effectiveJan1, want: "from 2025-01-01"},
+		{until: effectiveJul1, want: "until 2025-07-01"},
+		{from: effectiveJan1, until: effectiveJul1, want: "from 2025-01-01 until 2025-07-01"},
+		{from: effectiveJan1.Add(90 * time.Minute), want: "from 2025-01-01 01:30"},
+	}
+
+	for _, tt := range tests {
+		if got := formatEffectiveWindow(tt.from, tt.until); got != tt.want {
+			t.Errorf("formatEffectiveWindow(%v, %v) = %q, want %q", tt.from, tt.until, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	current := Effective(New("minimum order of 100", func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	}), effectiveJan1, time.Time{})
+	previous := Effective(New("minimum order of 50", func(o TestOrder) (bool, error) {
+		return o.Amount >= 50, nil
+	}), time.Time{}, effectiveJan1)
+	_ = Register(And("order validation", current, previous), WithDomain(TestOrderDomain))
+
+	retired := Effective(Never[TestOrder]("retired rule"), time.Time{}, effectiveJan1)
+	_ = Register(retired, WithDomain(TestOrderDomain))
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"**Effective**: from 2025-01-01",
+		"**Effective**: until 2025-01-01",
+		"retired rule",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	// Only rules in effect on the given date are documented
+	opts := DocumentOptions{EffectiveAt: effectiveJul1}
+	md, err = GenerateMarkdown(opts)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	if !strings.Contains(md, "minimum order of 100") {
+		t.Errorf("Markdown should contain the current rule:\n%s", md)
+	}
+	for _, unwanted := range []string{"minimum order of 50", "retired rule"} {
+		if strings.Contains(md, unwanted) {
+			t.Errorf("Markdown should not contain %q:\n%s", unwanted, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	if !strings.Contains(htmlDoc, `<span class="effective-badge">from 2025-01-01</span>`) {
+		t.Error("HTML should contain the effective badge")
+	}
+
+	jsonDoc, err := GenerateJSON(opts)
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonDoc, `"effectiveFrom": "2025-01-01T00:00:00Z"`) ||
+		strings.Contains(jsonDoc, "retired rule") {
+		t.Errorf("JSON should contain only rules in effect with their window:\n%s", jsonDoc)
+	}
+
+	mermaid, err := GenerateMermaid(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaid() error = %v", err)
+	}
+	if !strings.Contains(mermaid, "|from 2025-01-01|") {
+		t.Errorf("Mermaid should label the edge with the window:\n%s", mermaid)
+	}
+}
@@ -1,7 +1,9 @@
 package rules
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -17,22 +19,104 @@ type Result struct {
 	Error error
 	// Children contains results for child rules (for hierarchical rules).
 	Children []Result
+	// Severity is the severity level of the rule (see WithSeverity).
+	Severity Severity
+	// RuleViolations contains the violations reported by this rule itself
+	// (see NewValidation and WithViolation). Use Violations to collect the
+	// violations of the whole tree.
+	RuleViolations []Violation
+	// Cached indicates that the result was reused from an earlier evaluation
+	// of the same rule during this evaluation (see WithMemoization).
+	Cached bool
+	// Inactive indicates that the rule was not in effect at the evaluation
+	// time (see Effective). Such rules are reported as satisfied and are
+	// ignored by their parent.
+	Inactive bool
+	// Points holds the points a criterion contributed to a scorecard, or
+	// the total score of a scorecard (see Scorecard).
+	Points int
 }
 
 // Evaluator provides detailed evaluation of rules with result tracking.
 type Evaluator[T any] struct {
-	rule Rule[T]
+	rule   Rule[T]
+	config evaluatorConfig
+}
+
+// evaluatorConfig holds the configuration of an Evaluator.
+type evaluatorConfig struct {
+	memoize   bool
+	clock     func() time.Time
+	coverage  *CoverageRecorder
+	overrides []Override
+}
+
+// detailedOnly reports whether the configuration needs detailed
+// evaluations, which record coverage and apply overrides.
+func (c evaluatorConfig) detailedOnly() bool {
+	return c.coverage != nil || len(c.overrides) > 0
+}
+
+// EvaluatorOption configures an Evaluator.
+type EvaluatorOption func(*evaluatorConfig)
+
+// WithMemoization makes detailed evaluations evaluate each rule at most once
+// per input. When the same rule instance appears in several branches of the
+// tree, later occurrences reuse the first result and are marked as Cached.
+//
+// Results are memoized by rule identity for the duration of one call to
+// EvaluateDetailed or EvaluateDetailedShortCircuit (or their context
+// variants). Rules below Map and the collection rules are memoized
+// separately for every input they are evaluated on. Results with an error
+// are not memoized.
+func WithMemoization() EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.memoize = true
+	}
+}
+
+// WithClock sets the clock that provides the evaluation time for
+// effective-dated rules (see Effective), e.g. to evaluate rules as of a past
+// or future date. It defaults to time.Now. A time set on the context with
+// AsOf takes precedence.
+func WithClock(clock func() time.Time) EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.clock = clock
+	}
 }
 
 // NewEvaluator creates a new evaluator for the given rule.
-func NewEvaluator[T any](rule Rule[T]) *Evaluator[T] {
-	return &Evaluator[T]{rule: rule}
+func NewEvaluator[T any](rule Rule[T], opts ...EvaluatorOption) *Evaluator[T] {
+	e := &Evaluator[T]{rule: rule}
+	for _, opt := range opts {
+		opt(&e.config)
+	}
+	return e
 }
 
 // Evaluate evaluates the rule and returns a detailed result with timing information.
 func (e *Evaluator[T]) Evaluate(input T) Result {
+	return e.EvaluateContext(context.Background(), input)
+}
+
+// EvaluateContext evaluates the rule with the given context and returns a
+// detailed result with timing information. If the context is done before or
+// during evaluation, the result's Error wraps the context's error.
+func (e *Evaluator[T]) EvaluateContext(ctx context.Context, input T) Result {
+	if e.config.detailedOnly() {
+		start := time.Now()
+		result := e.evaluateDetailed(e.newEvaluation(ctx, true), input)
+		return Result{
+			Satisfied: result.Satisfied,
+			RuleName:  e.rule.Name(),
+			Duration:  time.Since(start),
+			Error:     result.Error,
+		}
+	}
+
+	ctx = e.withClock(ctx)
 	start := time.Now()
-	satisfied, err := e.rule.Evaluate(input)
+	satisfied, err := EvaluateContext(ctx, e.rule, input)
 	duration := time.Since(start)
 
 	return Result{
@@ -46,6 +130,13 @@ func (e *Evaluator[T]) Evaluate(input T) Result {
 // EvaluateFast evaluates the rule without timing overhead for maximum performance.
 // Use this when you don't need timing information in the result.
 func (e *Evaluator[T]) EvaluateFast(input T) (bool, error) {
+	if e.config.detailedOnly() {
+		result := e.EvaluateContext(context.Background(), input)
+		return result.Satisfied, result.Error
+	}
+	if e.config.clock != nil {
+		return EvaluateContext(e.withClock(context.Background()), e.rule, input)
+	}
 	return e.rule.Evaluate(input)
 }
 
@@ -53,116 +144,269 @@ func (e *Evaluator[T]) EvaluateFast(input T) (bool, error) {
 // including child rule results for hierarchical rules.
 // This evaluates all children to provide a complete view.
 func (e *Evaluator[T]) EvaluateDetailed(input T) Result {
-	return e.evaluateRuleDetailed(e.rule, input, false)
+	return e.EvaluateDetailedContext(context.Background(), input)
+}
+
+// EvaluateDetailedContext is like EvaluateDetailed but propagates the given
+// context to every rule in the tree. Evaluation stops as soon as the context
+// is done, and the affected results carry the context's error.
+func (e *Evaluator[T]) EvaluateDetailedContext(ctx context.Context, input T) Result {
+	return e.evaluateDetailed(e.newEvaluation(ctx, false), input)
 }
 
 // EvaluateDetailedShortCircuit evaluates the rule and returns a detailed result
 // with short-circuit optimization. For AND rules, stops on first failure.
 // For OR rules, stops on first success. This is faster but provides incomplete child results.
 func (e *Evaluator[T]) EvaluateDetailedShortCircuit(input T) Result {
-	return e.evaluateRuleDetailed(e.rule, input, true)
+	return e.EvaluateDetailedShortCircuitContext(context.Background(), input)
+}
+
+// EvaluateDetailedShortCircuitContext is like EvaluateDetailedShortCircuit but
+// propagates the given context to every rule in the tree.
+func (e *Evaluator[T]) EvaluateDetailedShortCircuitContext(ctx context.Context, input T) Result {
+	return e.evaluateDetailed(e.newEvaluation(ctx, true), input)
+}
+
+// evaluateDetailed evaluates the rule in the given evaluation and records
+// its outcome when coverage is recorded.
+func (e *Evaluator[T]) evaluateDetailed(ev *evaluation, input T) Result {
+	result := evaluateRuleDetailed(ev, e.rule, input)
+	if ev.coverage != nil {
+		ev.coverage.finish(result)
+	}
+	return result
+}
+
+// withClock sets the evaluation time from the configured clock on the
+// context, unless the context already carries one.
+func (e *Evaluator[T]) withClock(ctx context.Context) context.Context {
+	if e.config.clock == nil {
+		return ctx
+	}
+	if _, ok := ctx.Value(evaluationTimeKey{}).(time.Time); ok {
+		return ctx
+	}
+	return AsOf(ctx, e.config.clock())
+}
+
+// newEvaluation creates the state for a single detailed evaluation.
+func (e *Evaluator[T]) newEvaluation(ctx context.Context, shortCircuit bool) *evaluation {
+	ev := &evaluation{ctx: e.withClock(ctx), shortCircuit: shortCircuit}
+	// The outcome of rules selected by path depends on where they appear
+	if e.config.memoize && !hasPathOverride(e.config.overrides) {
+		ev.memo = newMemo()
+	}
+	if e.config.coverage != nil {
+		ev.coverage = e.config.coverage.start(e.rule)
+	}
+	if len(e.config.overrides) > 0 {
+		ev.overrides = e.config.overrides
+	}
+	return ev
+}
+
+// evaluation holds the state shared by all rules during a single detailed
+// evaluation of a rule tree.
+type evaluation struct {
+	ctx          context.Context
+	shortCircuit bool
+	// memo caches rule results when memoization is enabled; scope
+	// identifies the input the rules are currently evaluated on.
+	memo  *memo
+	scope int
+	// coverage records the results of rules when a coverage recorder is
+	// attached (see WithCoverage)
+	coverage *coverageRun
+	// overrides force the outcome of rules (see WithOverrides); path is the
+	// path of the rule whose children are evaluated
+	overrides []Override
+	path      string
+}
+
+// nested returns the state for evaluating a rule tree on another input,
+// such as the rules below Map.
+func (ev *evaluation) nested() *evaluation {
+	nested := *ev
+	if ev.memo != nil {
+		nested.scope = ev.memo.newScope()
+	}
+	return &nested
+}
+
+// nestedRule is implemented by rules that evaluate a rule tree of another
+// input type, such as mapped rules. They build their child results by
+// evaluating the nested tree themselves.
+type nestedRule[T any] interface {
+	evaluateNested(ev *evaluation, input T) (bool, []Result, error)
+}
+
+// evaluateRuleDetailed evaluates a rule and, for hierarchical rules, each of
+// its children, building the corresponding Result tree. With memoization
+// enabled, a rule that was already evaluated in the same scope is not
+// evaluated again. Overridden rules are not evaluated at all.
+func evaluateRuleDetailed[T any](
+	ev *evaluation,
+	rule Rule[T],
+	input T,
+) Result {
+	ev, result, overridden := ev.enter(rule)
+	if !overridden {
+		result = evaluateRuleMemoized(ev, rule, input)
+	}
+	if ev.coverage != nil {
+		ev.coverage.record(rule, result)
+	}
+	return result
+}
+
+// evaluateRuleMemoized evaluates a rule for evaluateRuleDetailed, reusing
+// memoized results.
+func evaluateRuleMemoized[T any](
+	ev *evaluation,
+	rule Rule[T],
+	input T,
+) Result {
+	if ev.memo == nil {
+		return evaluateRuleUncached(ev, rule, input)
+	}
+
+	key, ok := ev.memo.key(rule, ev.scope)
+	if !ok {
+		return evaluateRuleUncached(ev, rule, input)
+	}
+
+	if cached, ok := ev.memo.load(key); ok {
+		cached.Cached = true
+		cached.Duration = 0
+		return cached
+	}
+
+	result := evaluateRuleUncached(ev, rule, input)
+	if result.Error == nil {
+		ev.memo.store(key, result)
+	}
+
+	return result
 }
 
-func (e *Evaluator[T]) evaluateRuleDetailed(
+// evaluateRuleUncached evaluates a rule for evaluateRuleDetailed.
+func evaluateRuleUncached[T any](
+	ev *evaluation,
 	rule Rule[T],
 	input T,
-	shortCircuit bool,
 ) Result {
 	start := time.Now()
 
 	var children []Result
+	var violations []Violation
 	var satisfied bool
 	var err error
 
+	// Stop descending as soon as the context is done
+	if ctxErr := ev.ctx.Err(); ctxErr != nil {
+		return Result{
+			RuleName: rule.Name(),
+			Error:    ctxErr,
+		}
+	}
+
 	// Check if rule is hierarchical and evaluate children
 	// Compute result directly from children to avoid double evaluation
 	switch r := rule.(type) {
-	case *andRule[T]:
-		if len(r.rules) == 0 {
-			err = ErrEmptyRules
+	case *violationRule[T]:
+		if r.rule == nil {
+			err = ErrNilRule
 			satisfied = false
-		} else {
-			satisfied = true
-			children = make([]Result, 0, len(r.rules))
-			for _, childRule := range r.rules {
-				if childRule == nil {
-					err = ErrNilRule
-					satisfied = false
-					break
-				}
-				childResult := e.evaluateRuleDetailed(childRule, input, shortCircuit)
-				children = append(children, childResult)
-				if childResult.Error != nil {
-					err = childResult.Error
-					satisfied = false
-					break
-				}
-				if !childResult.Satisfied {
-					satisfied = false
-					if shortCircuit {
-						break
-					}
-					// Continue evaluating remaining children for complete detailed view
-				}
-			}
+			break
 		}
-	case *orRule[T]:
-		if len(r.rules) == 0 {
-			err = ErrEmptyRules
-			satisfied = false
-		} else {
+		// Violation wrappers are transparent: report the wrapped rule's result
+		result := evaluateRuleDetailed(ev, r.rule, input)
+		if !result.Satisfied && result.Error == nil {
+			// Copy the violations, which may be shared with a memoized
+			// result or the validation function
+			result.RuleViolations = append(slices.Clone(result.RuleViolations), r.violation)
+			fillViolationRule(result.RuleViolations, result.RuleName)
+		}
+		return result
+	case *severityRule[T]:
+		if r.rule == nil {
+			err = ErrNilRule
 			satisfied = false
-			children = make([]Result, 0, len(r.rules))
-			for _, childRule := range r.rules {
-				if childRule == nil {
-					err = ErrNilRule
-					satisfied = false
-					break
-				}
-				childResult := e.evaluateRuleDetailed(childRule, input, shortCircuit)
-				children = append(children, childResult)
-				if childResult.Error != nil {
-					err = childResult.Error
-					satisfied = false
-					break
-				}
-				if childResult.Satisfied {
-					satisfied = true
-					if shortCircuit {
-						break
-					}
-					// Continue evaluating remaining children for complete detailed view
-				}
-			}
+			break
 		}
-	case *notRule[T]:
+		// Severity wrappers are transparent: report the wrapped rule's result
+		result := evaluateRuleDetailed(ev, r.rule, input)
+		result.Severity = r.severity
+		return result
+	case *validationRule[T]:
+		violations, err = r.violations(ev.ctx, input)
+		satisfied = err == nil && len(violations) == 0
+		// Leave the slice returned by the validation function unchanged
+		violations = slices.Clone(violations)
+		fillViolationRule(violations, rule.Name())
+	case *parallelRule[T]:
+		satisfied, children, err = evaluateParallelDetailed(ev, r, input)
+	case *effectiveRule[T]:
 		if r.rule == nil {
 			err = ErrNilRule
 			satisfied = false
-		} else {
-			children = make([]Result, 0, 1)
-			childResult := e.evaluateRuleDetailed(r.rule, input, shortCircuit)
-			children = append(children, childResult)
-			if childResult.Error != nil {
-				err = childResult.Error
-				satisfied = false
-			} else {
-				satisfied = !childResult.Satisfied
+			break
+		}
+		if !inEffect(ev.ctx, r) {
+			return Result{
+				Satisfied: true,
+				RuleName:  rule.Name(),
+				Inactive:  true,
 			}
 		}
+		// Effective-dated rules in effect are transparent
+		return evaluateRuleDetailed(ev, r.rule, input)
+	case *refRule[T]:
+		target, resolveErr := r.target()
+		if resolveErr != nil {
+			err = resolveErr
+			satisfied = false
+			break
+		}
+		// References are transparent: report the referenced rule's result
+		return evaluateRuleDetailed(ev, target, input)
+	case *Reloadable[T]:
+		// Reloadable rules are transparent: report the active version's result
+		return evaluateRuleDetailed(ev, r.Current(), input)
+	case *CompiledRule[T]:
+		// Compiled rules are transparent: report the tree they were compiled from
+		return evaluateRuleDetailed(ev, r.rule, input)
+	case *Scorecard[T]:
+		return r.score(ev, input).Result
+	case nestedRule[T]:
+		satisfied, children, err = r.evaluateNested(ev, input)
+	case *quantifierRule[T]:
+		satisfied, children, err = evaluateCompositeDetailed(ev, r.evaluated(), input)
+	case CompositeRule[T]:
+		satisfied, children, err = evaluateCompositeDetailed(ev, r, input)
 	default:
 		// For simple rules, evaluate directly
-		satisfied, err = rule.Evaluate(input)
+		satisfied, err = EvaluateContext(ev.ctx, rule, input)
 	}
 
 	duration := time.Since(start)
 
 	return Result{
-		Satisfied: satisfied,
-		RuleName:  rule.Name(),
-		Duration:  duration,
-		Error:     err,
-		Children:  children,
+		Satisfied:      satisfied,
+		RuleName:       rule.Name(),
+		Duration:       duration,
+		Error:          err,
+		Children:       children,
+		RuleViolations: violations,
+	}
+}
+
+// fillViolationRule sets the rule name on violations that do not have one.
+func fillViolationRule(violations []Violation, ruleName string) {
+	for i := range violations {
+		if violations[i].Rule == "" {
+			violations[i].Rule = ruleName
+		}
 	}
 }
 
@@ -193,10 +437,30 @@ func (r Result) stringWithIndent(indent int) string {
 		r.Duration,
 	)
 
+	if r.Severity != SeverityError {
+		result += fmt.Sprintf(" [%s]", r.Severity)
+	}
+
+	if r.Cached {
+		result += " [cached]"
+	}
+
+	if r.Inactive {
+		result += " [not in effect]"
+	}
+
+	if r.Points != 0 {
+		result += fmt.Sprintf(" [%s points]", formatPoints(r.Points))
+	}
+
 	if r.Error != nil {
 		result += fmt.Sprintf(" - Error: %v", r.Error)
 	}
 
+	for _, violation := range r.RuleViolations {
+		result += fmt.Sprintf("\n%s  ! %s", prefix, violation)
+	}
+
 	for _, child := range r.Children {
 		result += "\n" + child.stringWithIndent(indent+1)
 	}
@@ -230,3 +494,60 @@ func (r Result) UnsatisfiedRules() []string {
 
 	return unsatisfied
 }
+
+// Violations returns all violations that contributed to the rule not being
+// satisfied, in evaluation order. It recursively traverses unsatisfied results
+// and their children; violations below a satisfied result are ignored because
+// they did not affect the outcome.
+func (r Result) Violations() []Violation {
+	if r.Satisfied {
+		return nil
+	}
+
+	var violations []Violation
+	violations = append(violations, r.RuleViolations...)
+
+	for _, child := range r.Children {
+		violations = append(violations, child.Violations()...)
+	}
+
+	return violations
+}
+
+// Warnings returns the results of all rules with SeverityWarning that were
+// not satisfied, anywhere in the evaluation tree. Such failures do not fail
+// an enclosing AND rule, so they can be present even when the tree as a
+// whole is satisfied.
+func (r Result) Warnings() []Result {
+	if !r.Satisfied && r.Severity == SeverityWarning {
+		return []Result{r}
+	}
+
+	var warnings []Result
+	for _, child := range r.Children {
+		warnings = append(warnings, child.Warnings()...)
+	}
+
+	return warnings
+}
+
+// Errors returns the results of the error-severity rules that caused the
+// evaluation to fail. It descends through unsatisfied results and reports
+// the deepest ones: the leaves, or a composite (such as a NOT rule) whose
+// children do not explain its failure.
+func (r Result) Errors() []Result {
+	if r.Satisfied || !r.Severity.isBlocking() {
+		return nil
+	}
+
+	var errs []Result
+	for _, child := range r.Children {
+		errs = append(errs, child.Errors()...)
+	}
+
+	if len(errs) == 0 {
+		return []Result{r}
+	}
+
+	return errs
+}
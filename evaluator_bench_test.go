@@ -409,3 +409,75 @@ func BenchmarkEvaluateFast(b *testing.B) {
 		_, _ = evaluator.EvaluateFast(input)
 	}
 }
+
+// benchOrderValidation builds a rule tree mixing AND, OR, quantifier and
+// severity rules.
+func benchOrderValidation() Rule[benchInput] {
+	hasItems := New("has items", func(input benchInput) (bool, error) {
+		return input.value > 0, nil
+	})
+	validQuantity := New("valid quantity", func(input benchInput) (bool, error) {
+		return input.value > 0 && input.value <= 1000, nil
+	})
+	isActive := New("is active", func(input benchInput) (bool, error) {
+		return input.active, nil
+	})
+	isPending := New("is pending", func(input benchInput) (bool, error) {
+		return input.status == "pending", nil
+	})
+	isConfirmed := New("is confirmed", func(input benchInput) (bool, error) {
+		return input.status == "confirmed", nil
+	})
+	isBulk := New("is bulk", func(input benchInput) (bool, error) {
+		return input.value > 100, nil
+	})
+
+	return And("order validation",
+		And("basic validation", hasItems, validQuantity),
+		Or("status validation", isConfirmed, isPending),
+		AtLeast("eligibility", 2, isActive, isBulk, Not("not confirmed", isConfirmed)),
+		WithSeverity(isBulk, SeverityWarning),
+	)
+}
+
+// Benchmark: Interpreted rule tree vs. its compiled evaluation plan
+func BenchmarkCompiledVsInterpreted(b *testing.B) {
+	input := benchInput{value: 5, status: "pending", active: true}
+	plans := []struct {
+		name string
+		rule Rule[benchInput]
+	}{
+		{name: "interpreted", rule: benchOrderValidation()},
+		{name: "compiled", rule: Compile(benchOrderValidation())},
+	}
+
+	for _, plan := range plans {
+		b.Run(plan.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_, _ = plan.rule.Evaluate(input)
+			}
+		})
+	}
+}
+
+// Benchmark: Compiled AND rule with 10 children
+func BenchmarkCompiledAndRule10Children(b *testing.B) {
+	rules := make([]Rule[benchInput], 10)
+	for i := 0; i < 10; i++ {
+		threshold := i * 10
+		rules[i] = New("check", func(input benchInput) (bool, error) {
+			return input.value > threshold, nil
+		})
+	}
+
+	andRule := Compile(And("all checks", rules...))
+	input := benchInput{value: 150}
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = andRule.Evaluate(input)
+	}
+}
@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -565,6 +567,39 @@ func TestResultUnsatisfiedRules(t *testing.T) {
 	}
 }
 
+func TestEvaluatorDetailedContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rule := And(
+		"checkout",
+		New("cancels", func(input testInput) (bool, error) {
+			cancel()
+			return false, nil
+		}),
+		New("never reached", func(input testInput) (bool, error) {
+			t.Error("Expected evaluation to stop after cancellation")
+			return true, nil
+		}),
+	)
+
+	result := NewEvaluator(rule).EvaluateDetailedContext(ctx, testInput{})
+
+	if !errors.Is(result.Error, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", result.Error)
+	}
+
+	if len(result.Children) != 2 {
+		t.Fatalf("Expected 2 children, got %d", len(result.Children))
+	}
+
+	if !errors.Is(result.Children[1].Error, context.Canceled) {
+		t.Errorf("Expected second child to report context.Canceled, got %v", result.Children[1].Error)
+	}
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -0,0 +1,882 @@
+package rules
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidExpression is wrapped by errors of CompileExpr. Use errors.As
+// with *ExprError to get the position of the error.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// ExprError reports a syntax or type error in an expression, with its
+// position in the source.
+type ExprError struct {
+	// Offset is the byte offset of the error in the source.
+	Offset int
+	// Line and Column are the 1-based position of the error. Columns count
+	// characters, not bytes.
+	Line   int
+	Column int
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the position and message of the error, e.g.
+// "1:8: unknown field \"amout\"".
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Unwrap returns ErrInvalidExpression.
+func (e *ExprError) Unwrap() error {
+	return ErrInvalidExpression
+}
+
+// ExprOption configures CompileExpr.
+type ExprOption[T any] func(*exprConfig[T])
+
+// exprConfig holds the configuration of CompileExpr.
+type exprConfig[T any] struct {
+	accessors map[string]exprAccessor[T]
+}
+
+// exprAccessor is a field registered with WithExprField.
+type exprAccessor[T any] struct {
+	typ reflect.Type
+	get func(T) reflect.Value
+}
+
+// WithExprField makes a computed value available to expressions under the
+// given name. Registered fields take precedence over struct fields of the
+// same name and may contain dots, e.g. "customer.age".
+func WithExprField[T any, V any](name string, get func(T) V) ExprOption[T] {
+	return func(c *exprConfig[T]) {
+		c.accessors[name] = exprAccessor[T]{
+			typ: reflect.TypeFor[V](),
+			get: func(input T) reflect.Value {
+				return reflect.ValueOf(get(input))
+			},
+		}
+	}
+}
+
+// CompileExpr compiles a boolean expression over the fields of T into a
+// rule tree of And, Or, Not and leaf rules, so the rule evaluates,
+// documents and registers like a hand-written one. The root rule is named
+// name; nested rules are named after their source text, e.g.
+// "amount >= 100".
+//
+// Expressions support:
+//
+//   - comparisons with ==, !=, <, <=, > and >=
+//   - list membership with in, e.g. country in ["US", "CA"]
+//   - &&, || and ! with the usual precedence, and parentheses
+//   - number, string ("..."), true and false literals
+//
+// Fields are exported struct fields of T, matched by their JSON name or
+// case-insensitively by their Go name. Nested fields are separated by dots,
+// e.g. customer.tier, and pointers are followed; a nil pointer makes the
+// comparison fail with ErrUnknown (see EvaluatePartial). Numeric fields
+// compare as float64. Further fields can be registered with WithExprField.
+//
+// Syntax and type errors are reported as *ExprError with the position of
+// the error.
+//
+// Example:
+//
+//	eligible, err := rules.CompileExpr[Order]("eligible for promotion",
+//	    `amount >= 100 && country in ["US", "CA"]`)
+func CompileExpr[T any](name, source string, opts ...ExprOption[T]) (Rule[T], error) {
+	config := &exprConfig[T]{accessors: make(map[string]exprAccessor[T])}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	rule, err := compileExpr(name, source, config)
+	if err != nil {
+		return nil, fmt.Errorf("compiling expression %q: %w", name, err)
+	}
+	return rule, nil
+}
+
+// compileExpr parses and type checks the source and builds the rule tree.
+func compileExpr[T any](name, source string, config *exprConfig[T]) (Rule[T], error) {
+	p := &exprParser{source: source}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+
+	c := &exprCompiler[T]{name: name, source: source, config: config}
+	return c.compile(node, true)
+}
+
+// exprTokenKind identifies the kind of an expression token.
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokTrue
+	tokFalse
+	tokIn
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+	tokDot
+	tokMinus
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNe
+	tokLt
+	tokLe
+	tokGt
+	tokGe
+)
+
+// exprToken is a token of an expression, spanning source[pos:end].
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// String describes the token for error messages.
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// exprOperators maps operators to their tokens, longest first.
+var exprOperators = []struct {
+	text string
+	kind exprTokenKind
+}{
+	{"&&", tokAnd}, {"||", tokOr}, {"==", tokEq}, {"!=", tokNe}, {"<=", tokLe}, {">=", tokGe},
+	{"<", tokLt}, {">", tokGt}, {"!", tokNot}, {"(", tokLParen}, {")", tokRParen},
+	{"[", tokLBracket}, {"]", tokRBracket}, {",", tokComma}, {".", tokDot}, {"-", tokMinus},
+}
+
+// exprNode is a node of the syntax tree, spanning source[pos:end].
+type exprNode interface {
+	span() (pos, end int)
+}
+
+type (
+	// exprLogical is a chain of && or || operands.
+	exprLogical struct {
+		op       exprTokenKind
+		operands []exprNode
+		pos, end int
+	}
+	// exprNegation is a negated expression.
+	exprNegation struct {
+		operand  exprNode
+		pos, end int
+	}
+	// exprParen is a parenthesized expression.
+	exprParen struct {
+		inner    exprNode
+		pos, end int
+	}
+	// exprComparison compares two operands.
+	exprComparison struct {
+		left, right exprOperand
+		op          exprToken
+	}
+	// exprMembership tests whether an operand is one of a list of values.
+	exprMembership struct {
+		operand exprOperand
+		list    []exprOperand
+		op      exprToken
+		end     int
+	}
+	// exprCondition is an operand used as a condition, e.g. a boolean
+	// field.
+	exprCondition struct {
+		operand exprOperand
+	}
+)
+
+// exprOperand is a field or a literal.
+type exprOperand struct {
+	path     []string // field path; nil for literals
+	literal  any      // float64, string or bool
+	pos, end int
+}
+
+func (n *exprLogical) span() (int, int)    { return n.pos, n.end }
+func (n *exprNegation) span() (int, int)   { return n.pos, n.end }
+func (n *exprParen) span() (int, int)      { return n.pos, n.end }
+func (n *exprComparison) span() (int, int) { return n.left.pos, n.right.end }
+func (n *exprMembership) span() (int, int) { return n.operand.pos, n.end }
+func (n *exprCondition) span() (int, int)  { return n.operand.pos, n.operand.end }
+
+// exprParser tokenizes and parses an expression.
+type exprParser struct {
+	source string
+	tokens []exprToken
+	next   int
+}
+
+// errorf returns an *ExprError at the byte offset.
+func (p *exprParser) errorf(offset int, format string, args ...any) error {
+	return newExprError(p.source, offset, fmt.Sprintf(format, args...))
+}
+
+// newExprError returns an *ExprError at the byte offset of the source.
+func newExprError(source string, offset int, message string) *ExprError {
+	line, lineStart := 1, 0
+	for i := 0; i < offset && i < len(source); i++ {
+		if source[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return &ExprError{
+		Offset:  offset,
+		Line:    line,
+		Column:  utf8.RuneCountInString(source[lineStart:min(offset, len(source))]) + 1,
+		Message: message,
+	}
+}
+
+// tokenize splits the source into tokens.
+func (p *exprParser) tokenize() error {
+	src := p.source
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			p.tokens = append(p.tokens, exprToken{kind: identKind(src[start:i]), text: src[start:i], pos: start, end: i})
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9') {
+				i++
+			}
+			p.tokens = append(p.tokens, exprToken{kind: tokNumber, text: src[start:i], pos: start, end: i})
+		case r == '"':
+			start := i
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return p.errorf(start, "unterminated string")
+			}
+			i++
+			p.tokens = append(p.tokens, exprToken{kind: tokString, text: src[start:i], pos: start, end: i})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(src[i:], op.text) {
+					p.tokens = append(p.tokens, exprToken{kind: op.kind, text: op.text, pos: i, end: i + len(op.text)})
+					i += len(op.text)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return p.errorf(i, "unexpected character %q", r)
+			}
+		}
+	}
+
+	p.tokens = append(p.tokens, exprToken{kind: tokEOF, pos: len(src), end: len(src)})
+	return nil
+}
+
+// identKind returns the token kind of an identifier or keyword.
+func identKind(text string) exprTokenKind {
+	switch text {
+	case "true":
+		return tokTrue
+	case "false":
+		return tokFalse
+	case "in":
+		return tokIn
+	default:
+		return tokIdent
+	}
+}
+
+// peek returns the next token without consuming it.
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.next]
+}
+
+// advance consumes and returns the next token.
+func (p *exprParser) advance() exprToken {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+// expect consumes the next token, which must be of the given kind.
+func (p *exprParser) expect(kind exprTokenKind, what string) (exprToken, error) {
+	tok := p.advance()
+	if tok.kind != kind {
+		return tok, p.errorf(tok.pos, "expected %s, found %s", what, tok)
+	}
+	return tok, nil
+}
+
+// parseOr parses a chain of || operands.
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseLogical(tokOr, p.parseAnd)
+}
+
+// parseAnd parses a chain of && operands.
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseLogical(tokAnd, p.parseUnary)
+}
+
+// parseLogical parses a chain of operands joined by op.
+func (p *exprParser) parseLogical(op exprTokenKind, parseOperand func() (exprNode, error)) (exprNode, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []exprNode{first}
+	for p.peek().kind == op {
+		p.advance()
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	pos, _ := first.span()
+	_, end := operands[len(operands)-1].span()
+	return &exprLogical{op: op, operands: operands, pos: pos, end: end}, nil
+}
+
+// parseUnary parses a negation or a primary expression.
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokNot {
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		_, end := operand.span()
+		return &exprNegation{operand: operand, pos: tok.pos, end: end}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression, a comparison, a
+// membership test or a condition.
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokLParen {
+		p.advance()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.expect(tokRParen, `")"`)
+		if err != nil {
+			return nil, err
+		}
+		return &exprParen{inner: inner, pos: tok.pos, end: closing.end}, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op := p.peek(); op.kind {
+	case tokEq, tokNe, tokLt, tokLe, tokGt, tokGe:
+		p.advance()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &exprComparison{left: left, right: right, op: op}, nil
+	case tokIn:
+		p.advance()
+		list, end, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &exprMembership{operand: left, list: list, op: op, end: end}, nil
+	default:
+		return &exprCondition{operand: left}, nil
+	}
+}
+
+// parseList parses a bracketed list of operands and returns the end of the
+// closing bracket.
+func (p *exprParser) parseList() ([]exprOperand, int, error) {
+	if _, err := p.expect(tokLBracket, `"["`); err != nil {
+		return nil, 0, err
+	}
+
+	var list []exprOperand
+	for p.peek().kind != tokRBracket {
+		if len(list) > 0 {
+			if _, err := p.expect(tokComma, `"," or "]"`); err != nil {
+				return nil, 0, err
+			}
+		}
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, operand)
+	}
+
+	closing := p.advance()
+	return list, closing.end, nil
+}
+
+// parseOperand parses a field path or a literal.
+func (p *exprParser) parseOperand() (exprOperand, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokIdent:
+		operand := exprOperand{path: []string{tok.text}, pos: tok.pos, end: tok.end}
+		for p.peek().kind == tokDot {
+			p.advance()
+			field, err := p.expect(tokIdent, "a field name")
+			if err != nil {
+				return operand, err
+			}
+			operand.path = append(operand.path, field.text)
+			operand.end = field.end
+		}
+		return operand, nil
+	case tokNumber, tokMinus:
+		pos := tok.pos
+		if tok.kind == tokMinus {
+			var err error
+			if tok, err = p.expect(tokNumber, "a number"); err != nil {
+				return exprOperand{}, err
+			}
+		}
+		value, err := strconv.ParseFloat(p.source[pos:tok.end], 64)
+		if err != nil {
+			return exprOperand{}, p.errorf(pos, "invalid number %q", p.source[pos:tok.end])
+		}
+		return exprOperand{literal: value, pos: pos, end: tok.end}, nil
+	case tokString:
+		value, err := strconv.Unquote(tok.text)
+		if err != nil {
+			return exprOperand{}, p.errorf(tok.pos, "invalid string %s", tok.text)
+		}
+		return exprOperand{literal: value, pos: tok.pos, end: tok.end}, nil
+	case tokTrue, tokFalse:
+		return exprOperand{literal: tok.kind == tokTrue, pos: tok.pos, end: tok.end}, nil
+	default:
+		return exprOperand{}, p.errorf(tok.pos, "expected a field or value, found %s", tok)
+	}
+}
+
+// exprType is the type of an operand.
+type exprType int
+
+const (
+	exprNumber exprType = iota
+	exprString
+	exprBool
+)
+
+// String returns the name of the type for error messages.
+func (t exprType) String() string {
+	switch t {
+	case exprNumber:
+		return "number"
+	case exprString:
+		return "string"
+	default:
+		return "boolean"
+	}
+}
+
+// exprValue is a type-checked operand that extracts its value (a float64,
+// string or bool) from the input.
+type exprValue[T any] struct {
+	typ exprType
+	get func(T) (any, error)
+}
+
+// exprCompiler type checks the syntax tree and builds the rule tree.
+type exprCompiler[T any] struct {
+	name   string
+	source string
+	config *exprConfig[T]
+}
+
+// errorf returns an *ExprError at the byte offset.
+func (c *exprCompiler[T]) errorf(offset int, format string, args ...any) error {
+	return newExprError(c.source, offset, fmt.Sprintf(format, args...))
+}
+
+// text returns the source text of a node, used to name its rule.
+func (c *exprCompiler[T]) text(node exprNode) string {
+	pos, end := node.span()
+	return c.source[pos:end]
+}
+
+// compile builds the rule of a node. The root rule is named after the
+// expression; all others after their source text.
+func (c *exprCompiler[T]) compile(node exprNode, root bool) (Rule[T], error) {
+	if paren, ok := node.(*exprParen); ok {
+		return c.compile(paren.inner, root)
+	}
+
+	name := c.name
+	if !root {
+		name = c.text(node)
+	}
+
+	switch n := node.(type) {
+	case *exprLogical:
+		children := make([]Rule[T], len(n.operands))
+		for i, operand := range n.operands {
+			child, err := c.compile(operand, false)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = child
+		}
+		if n.op == tokAnd {
+			return And(name, children...), nil
+		}
+		return Or(name, children...), nil
+	case *exprNegation:
+		child, err := c.compile(n.operand, false)
+		if err != nil {
+			return nil, err
+		}
+		return Not(name, child), nil
+	case *exprComparison:
+		return c.compileComparison(name, n)
+	case *exprMembership:
+		return c.compileMembership(name, n)
+	case *exprCondition:
+		return c.compileCondition(name, n)
+	default:
+		return nil, fmt.Errorf("unexpected expression node %T", node)
+	}
+}
+
+// compileComparison builds a leaf rule comparing two operands.
+func (c *exprCompiler[T]) compileComparison(name string, n *exprComparison) (Rule[T], error) {
+	left, err := c.value(n.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.value(n.right)
+	if err != nil {
+		return nil, err
+	}
+	if left.typ != right.typ {
+		return nil, c.errorf(n.op.pos, "cannot compare %s with %s", left.typ, right.typ)
+	}
+	if left.typ == exprBool && n.op.kind != tokEq && n.op.kind != tokNe {
+		return nil, c.errorf(n.op.pos, "operator %s is not defined for booleans", n.op.text)
+	}
+
+	op := n.op.kind
+	return New(name, func(input T) (bool, error) {
+		a, err := left.get(input)
+		if err != nil {
+			return false, err
+		}
+		b, err := right.get(input)
+		if err != nil {
+			return false, err
+		}
+		return compareExprValues(op, a, b), nil
+	}), nil
+}
+
+// compileMembership builds a leaf rule testing whether an operand is one
+// of a list of literals.
+func (c *exprCompiler[T]) compileMembership(name string, n *exprMembership) (Rule[T], error) {
+	value, err := c.value(n.operand)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]any, len(n.list))
+	for i, element := range n.list {
+		if element.path != nil {
+			return nil, c.errorf(element.pos, "list elements must be literals")
+		}
+		if typ := exprTypeOf(element.literal); typ != value.typ {
+			return nil, c.errorf(element.pos, "list element is a %s, expected %s", typ, value.typ)
+		}
+		list[i] = element.literal
+	}
+
+	return New(name, func(input T) (bool, error) {
+		v, err := value.get(input)
+		if err != nil {
+			return false, err
+		}
+		for _, element := range list {
+			if v == element {
+				return true, nil
+			}
+		}
+		return false, nil
+	}), nil
+}
+
+// compileCondition builds a rule for an operand used as a condition: a
+// boolean field, or a constant rule for true and false.
+func (c *exprCompiler[T]) compileCondition(name string, n *exprCondition) (Rule[T], error) {
+	if literal, ok := n.operand.literal.(bool); ok {
+		if literal {
+			return Always[T](name), nil
+		}
+		return Never[T](name), nil
+	}
+
+	value, err := c.value(n.operand)
+	if err != nil {
+		return nil, err
+	}
+	if value.typ != exprBool {
+		return nil, c.errorf(n.operand.pos, "%s is a %s, not a condition", c.source[n.operand.pos:n.operand.end], value.typ)
+	}
+
+	return New(name, func(input T) (bool, error) {
+		v, err := value.get(input)
+		if err != nil {
+			return false, err
+		}
+		return v.(bool), nil
+	}), nil
+}
+
+// value type checks an operand.
+func (c *exprCompiler[T]) value(operand exprOperand) (exprValue[T], error) {
+	if operand.path == nil {
+		literal := operand.literal
+		return exprValue[T]{
+			typ: exprTypeOf(literal),
+			get: func(T) (any, error) { return literal, nil },
+		}, nil
+	}
+
+	path := strings.Join(operand.path, ".")
+	if accessor, ok := c.config.accessors[path]; ok {
+		typ, ok := exprTypeOfReflect(accessor.typ)
+		if !ok {
+			return exprValue[T]{}, c.errorf(operand.pos, "field %q has unsupported type %s", path, accessor.typ)
+		}
+		return exprValue[T]{
+			typ: typ,
+			get: func(input T) (any, error) {
+				return exprReflectValue(accessor.get(input), typ, path)
+			},
+		}, nil
+	}
+
+	index, fieldType, err := c.resolveField(operand)
+	if err != nil {
+		return exprValue[T]{}, err
+	}
+	typ, ok := exprTypeOfReflect(fieldType)
+	if !ok {
+		return exprValue[T]{}, c.errorf(operand.pos, "field %q has unsupported type %s", path, fieldType)
+	}
+
+	return exprValue[T]{
+		typ: typ,
+		get: func(input T) (any, error) {
+			v := reflect.ValueOf(&input).Elem()
+			for _, i := range index {
+				for v.Kind() == reflect.Pointer {
+					if v.IsNil() {
+						return nil, fmt.Errorf("field %q is nil: %w", path, ErrUnknown)
+					}
+					v = v.Elem()
+				}
+				v = v.Field(i)
+			}
+			return exprReflectValue(v, typ, path)
+		},
+	}, nil
+}
+
+// resolveField finds the struct field of a field path and returns its
+// index path and type.
+func (c *exprCompiler[T]) resolveField(operand exprOperand) ([]int, reflect.Type, error) {
+	typ := reflect.TypeFor[T]()
+	var index []int
+
+	for i, name := range operand.path {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil, nil, c.errorf(operand.pos, "%s has no field %q",
+				strings.Join(append([]string{"input"}, operand.path[:i]...), "."), name)
+		}
+
+		field, ok := findExprField(typ, name)
+		if !ok {
+			return nil, nil, c.errorf(operand.pos, "unknown field %q", strings.Join(operand.path[:i+1], "."))
+		}
+		index = append(index, field.Index...)
+		typ = field.Type
+	}
+
+	return index, typ, nil
+}
+
+// findExprField finds an exported field by its JSON name or, case
+// insensitively, by its Go name.
+func findExprField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	fields := reflect.VisibleFields(typ)
+	for _, field := range fields {
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && !field.Anonymous && tag == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if field.IsExported() && !field.Anonymous && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// exprTypeOf returns the type of a literal.
+func exprTypeOf(literal any) exprType {
+	switch literal.(type) {
+	case float64:
+		return exprNumber
+	case string:
+		return exprString
+	default:
+		return exprBool
+	}
+}
+
+// exprTypeOfReflect returns the expression type of a Go type, following
+// pointers.
+func exprTypeOfReflect(typ reflect.Type) (exprType, bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return exprNumber, true
+	case reflect.String:
+		return exprString, true
+	case reflect.Bool:
+		return exprBool, true
+	default:
+		return 0, false
+	}
+}
+
+// exprReflectValue converts a field value to a float64, string or bool.
+func exprReflectValue(v reflect.Value, typ exprType, path string) (any, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("field %q is nil: %w", path, ErrUnknown)
+		}
+		v = v.Elem()
+	}
+
+	switch typ {
+	case exprString:
+		return v.String(), nil
+	case exprBool:
+		return v.Bool(), nil
+	}
+
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), nil
+	case v.CanUint():
+		return float64(v.Uint()), nil
+	default:
+		return v.Float(), nil
+	}
+}
+
+// compareExprValues applies a comparison operator to two values of the
+// same type.
+func compareExprValues(op exprTokenKind, a, b any) bool {
+	switch op {
+	case tokEq:
+		return a == b
+	case tokNe:
+		return a != b
+	}
+
+	var order int
+	switch a := a.(type) {
+	case float64:
+		order = cmp.Compare(a, b.(float64))
+	case string:
+		order = cmp.Compare(a, b.(string))
+	}
+
+	switch op {
+	case tokLt:
+		return order < 0
+	case tokLe:
+		return order <= 0
+	case tokGt:
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
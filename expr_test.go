@@ -0,0 +1,221 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// exprOrder is the input of the compiled expressions.
+type exprOrder struct {
+	Amount   float64
+	Country  string `json:"country_code"`
+	Express  bool
+	Items    int
+	Customer *exprCustomer
+}
+
+type exprCustomer struct {
+	Tier string
+}
+
+func TestCompileExpr(t *testing.T) {
+	t.Parallel()
+
+	order := exprOrder{
+		Amount:   150,
+		Country:  "US",
+		Items:    3,
+		Customer: &exprCustomer{Tier: "gold"},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{name: "comparison", source: "amount >= 100", want: true},
+		{name: "json name", source: `country_code == "US"`, want: true},
+		{name: "membership", source: `country_code in ["SE", "CA"]`, want: false},
+		{name: "and", source: `amount >= 100 && country_code in ["US", "CA"]`, want: true},
+		{name: "or", source: "amount < 100 || items > 2", want: true},
+		{name: "not", source: "!express", want: true},
+		{name: "precedence", source: "amount < 100 && items > 2 || express", want: false},
+		{name: "parentheses", source: "amount < 100 && (items > 2 || !express)", want: false},
+		{name: "nested field", source: `customer.tier == "gold"`, want: true},
+		{name: "negative number", source: "amount > -1.5", want: true},
+		{name: "field to field", source: "items < amount", want: true},
+		{name: "literal", source: "true && !false", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := CompileExpr[exprOrder]("rule", tt.source)
+			if err != nil {
+				t.Fatalf("CompileExpr() error = %v", err)
+			}
+			got, err := rule.Evaluate(order)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileExpr_RuleTree(t *testing.T) {
+	t.Parallel()
+
+	rule, err := CompileExpr[exprOrder]("eligible", `amount >= 100 && !(express || country_code in ["SE"])`)
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+
+	result := NewEvaluator(rule).EvaluateDetailed(exprOrder{Amount: 150, Country: "US"})
+	want := "eligible\n  amount >= 100\n  !(express || country_code in [\"SE\"])\n    express || country_code in [\"SE\"]\n      express\n      country_code in [\"SE\"]\n"
+
+	var sb strings.Builder
+	var walk func(r Result, depth int)
+	walk = func(r Result, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth) + r.RuleName + "\n")
+		for _, child := range r.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(result, 0)
+
+	if sb.String() != want {
+		t.Errorf("Unexpected rule tree:\n%s\nwant:\n%s", sb.String(), want)
+	}
+	if !result.Satisfied {
+		t.Errorf("Expected the rule to be satisfied: %v", result)
+	}
+	if getRuleType(rule) != RuleTypeAnd {
+		t.Errorf("Expected an AND rule, got %v", getRuleType(rule))
+	}
+}
+
+func TestCompileExpr_Fields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("registered field", func(t *testing.T) {
+		t.Parallel()
+
+		rule, err := CompileExpr("large order", "average > 40 && items >= 2",
+			WithExprField("average", func(o exprOrder) float64 { return o.Amount / float64(o.Items) }))
+		if err != nil {
+			t.Fatalf("CompileExpr() error = %v", err)
+		}
+		if got, err := rule.Evaluate(exprOrder{Amount: 150, Items: 3}); err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		t.Parallel()
+
+		rule, err := CompileExpr[exprOrder]("gold", `customer.tier == "gold"`)
+		if err != nil {
+			t.Fatalf("CompileExpr() error = %v", err)
+		}
+		if _, err := rule.Evaluate(exprOrder{}); err == nil || !strings.Contains(err.Error(), "customer.tier") {
+			t.Errorf("Expected a nil pointer error, got %v", err)
+		}
+	})
+
+	t.Run("nil pointer is unknown", func(t *testing.T) {
+		t.Parallel()
+
+		rule, err := CompileExpr[exprOrder]("gold", `customer.tier == "gold"`)
+		if err != nil {
+			t.Fatalf("CompileExpr() error = %v", err)
+		}
+		if _, err := rule.Evaluate(exprOrder{}); !errors.Is(err, ErrUnknown) {
+			t.Errorf("Evaluate() error = %v, want ErrUnknown", err)
+		}
+		if truth, err := EvaluateTruth(context.Background(), rule, exprOrder{}); err != nil || truth != TruthUnknown {
+			t.Errorf("EvaluateTruth() = %v, %v; want unknown, nil", truth, err)
+		}
+	})
+}
+
+func TestCompileExpr_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		source      string
+		wantColumn  int
+		wantMessage string
+	}{
+		{name: "unknown field", source: "amount >= 100 && amout < 5", wantColumn: 18, wantMessage: `unknown field "amout"`},
+		{name: "type mismatch", source: `amount == "100"`, wantColumn: 8, wantMessage: "cannot compare number with string"},
+		{name: "boolean ordering", source: "express > true", wantColumn: 9, wantMessage: "operator > is not defined for booleans"},
+		{name: "list element type", source: `country_code in ["US", 1]`, wantColumn: 24, wantMessage: "list element is a number, expected string"},
+		{name: "not a condition", source: "amount && express", wantColumn: 1, wantMessage: "amount is a number, not a condition"},
+		{name: "missing parenthesis", source: "(amount > 1", wantColumn: 12, wantMessage: `expected ")", found end of expression`},
+		{name: "trailing token", source: "amount > 1 1", wantColumn: 12, wantMessage: `unexpected "1"`},
+		{name: "unterminated string", source: `country_code == "US`, wantColumn: 17, wantMessage: "unterminated string"},
+		{name: "unexpected character", source: "amount % 2", wantColumn: 8, wantMessage: "unexpected character '%'"},
+		{name: "no field of scalar", source: "amount.value > 1", wantColumn: 1, wantMessage: `input.amount has no field "value"`},
+		{name: "unsupported type", source: "customer > 1", wantColumn: 1, wantMessage: `field "customer" has unsupported type`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileExpr[exprOrder]("rule", tt.source)
+			if !errors.Is(err, ErrInvalidExpression) {
+				t.Fatalf("Expected ErrInvalidExpression, got %v", err)
+			}
+
+			var exprErr *ExprError
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("Expected an *ExprError, got %T", err)
+			}
+			if exprErr.Line != 1 || exprErr.Column != tt.wantColumn || !strings.HasPrefix(exprErr.Message, tt.wantMessage) {
+				t.Errorf("Error = %v, want 1:%d: %s", exprErr, tt.wantColumn, tt.wantMessage)
+			}
+		})
+	}
+
+	t.Run("multiline position", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := CompileExpr[exprOrder]("rule", "amount >= 100 &&\n  items >")
+		var exprErr *ExprError
+		if !errors.As(err, &exprErr) || exprErr.Line != 2 || exprErr.Column != 10 {
+			t.Errorf("Expected an error at 2:10, got %v", err)
+		}
+		if !strings.Contains(err.Error(), `compiling expression "rule": 2:10:`) {
+			t.Errorf("Unexpected error message %q", err)
+		}
+	})
+}
+
+func TestCompileExprDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	rule, err := CompileExpr[exprOrder]("eligible", `amount >= 100 && country_code in ["US", "CA"]`)
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+	_ = Register(rule, WithDomain(TestOrderDomain))
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{"### eligible (AND)", "amount >= 100", `country_code in ["US", "CA"]`} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+}
@@ -1,17 +1,55 @@
 package rules
 
+import (
+	"context"
+	"fmt"
+)
+
 // Always creates a rule that is always satisfied.
 func Always[T any](name string) Rule[T] {
-	return New(name, func(input T) (bool, error) {
-		return true, nil
-	})
+	return &constantRule[T]{name: name, value: true}
 }
 
 // Never creates a rule that is never satisfied.
 func Never[T any](name string) Rule[T] {
-	return New(name, func(input T) (bool, error) {
-		return false, nil
-	})
+	return &constantRule[T]{name: name, value: false}
+}
+
+// constantRule is a rule with a fixed outcome. Unlike a simple rule, its
+// outcome is known without evaluating it (see Analyze).
+type constantRule[T any] struct {
+	name  string
+	value bool
+}
+
+func (r *constantRule[T]) Evaluate(input T) (bool, error) {
+	return r.value, nil
+}
+
+func (r *constantRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf(
+			"evaluating rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	return r.value, nil
+}
+
+func (r *constantRule[T]) Name() string {
+	return r.name
+}
+
+// Constant returns the fixed outcome of the rule.
+func (r *constantRule[T]) Constant() bool {
+	return r.value
+}
+
+// Kind reports that the rule is documented as a simple rule.
+func (r *constantRule[T]) Kind() RuleType {
+	return RuleTypeSimple
 }
 
 // AllOf is an alias for And that creates a rule satisfied only if all
@@ -27,9 +65,9 @@ func AnyOf[T any](name string, rules ...Rule[T]) Rule[T] {
 }
 
 // NoneOf creates a rule that is satisfied only if none of the provided
-// rules are satisfied.
+// rules are satisfied. Automatically inherits domains from child rules.
 func NoneOf[T any](name string, rules ...Rule[T]) Rule[T] {
-	return Not(name, Or(name+" (internal)", rules...))
+	return newQuantifierRule(name, RuleTypeNoneOf, 0, rules)
 }
 
 // AtLeast creates a rule that is satisfied if at least n of the provided
@@ -39,34 +77,7 @@ func AtLeast[T any](
 	n int,
 	rules ...Rule[T],
 ) Rule[T] {
-	predicate := func(input T) (bool, error) {
-		satisfied := 0
-		for _, rule := range rules {
-			if rule == nil {
-				continue
-			}
-			result, err := rule.Evaluate(input)
-			if err != nil {
-				return false, err
-			}
-			if result {
-				satisfied++
-				if satisfied >= n {
-					return true, nil
-				}
-			}
-		}
-		return satisfied >= n, nil
-	}
-	rule := New(name, predicate)
-
-	// Collect and deduplicate domains from children
-	domains := collectDomainsFromRules(rules)
-	if len(domains) > 0 {
-		_ = Register(rule, WithDomains(domains...))
-	}
-
-	return rule
+	return newQuantifierRule(name, RuleTypeAtLeast, n, rules)
 }
 
 // Exactly creates a rule that is satisfied if exactly n of the provided
@@ -76,31 +87,7 @@ func Exactly[T any](
 	n int,
 	rules ...Rule[T],
 ) Rule[T] {
-	predicate := func(input T) (bool, error) {
-		satisfied := 0
-		for _, rule := range rules {
-			if rule == nil {
-				continue
-			}
-			result, err := rule.Evaluate(input)
-			if err != nil {
-				return false, err
-			}
-			if result {
-				satisfied++
-			}
-		}
-		return satisfied == n, nil
-	}
-	rule := New(name, predicate)
-
-	// Collect and deduplicate domains from children
-	domains := collectDomainsFromRules(rules)
-	if len(domains) > 0 {
-		_ = Register(rule, WithDomains(domains...))
-	}
-
-	return rule
+	return newQuantifierRule(name, RuleTypeExactly, n, rules)
 }
 
 // AtMost creates a rule that is satisfied if at most n of the provided
@@ -110,32 +97,5 @@ func AtMost[T any](
 	n int,
 	rules ...Rule[T],
 ) Rule[T] {
-	predicate := func(input T) (bool, error) {
-		satisfied := 0
-		for _, rule := range rules {
-			if rule == nil {
-				continue
-			}
-			result, err := rule.Evaluate(input)
-			if err != nil {
-				return false, err
-			}
-			if result {
-				satisfied++
-				if satisfied > n {
-					return false, nil
-				}
-			}
-		}
-		return true, nil
-	}
-	rule := New(name, predicate)
-
-	// Collect and deduplicate domains from children
-	domains := collectDomainsFromRules(rules)
-	if len(domains) > 0 {
-		_ = Register(rule, WithDomains(domains...))
-	}
-
-	return rule
+	return newQuantifierRule(name, RuleTypeAtMost, n, rules)
 }
@@ -1,6 +1,8 @@
 package rules
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -478,3 +480,156 @@ func TestHelpersWithNilRules(t *testing.T) {
 		}
 	})
 }
+
+func TestQuantifierDetailedEvaluation(t *testing.T) {
+	t.Parallel()
+
+	gt10 := New("value > 10", func(input testInput) (bool, error) {
+		return input.value > 10, nil
+	})
+	gt20 := New("value > 20", func(input testInput) (bool, error) {
+		return input.value > 20, nil
+	})
+	valid := New("valid", func(input testInput) (bool, error) {
+		return input.valid, nil
+	})
+
+	tests := []struct {
+		name             string
+		rule             Rule[testInput]
+		input            testInput
+		want             bool
+		wantChildren     int
+		wantShortCircuit int
+	}{
+		{
+			name:             "at least settles on threshold",
+			rule:             AtLeast("at least 2", 2, gt10, gt20, valid),
+			input:            testInput{value: 50, valid: true},
+			want:             true,
+			wantChildren:     3,
+			wantShortCircuit: 2,
+		},
+		{
+			name:             "exactly needs every child",
+			rule:             Exactly("exactly 1", 1, gt10, gt20, valid),
+			input:            testInput{value: 15},
+			want:             true,
+			wantChildren:     3,
+			wantShortCircuit: 3,
+		},
+		{
+			name:             "at most settles when exceeded",
+			rule:             AtMost("at most 1", 1, gt10, gt20, valid),
+			input:            testInput{value: 50, valid: true},
+			want:             false,
+			wantChildren:     3,
+			wantShortCircuit: 2,
+		},
+		{
+			name:             "none of settles on first satisfied",
+			rule:             NoneOf("none", gt10, gt20, valid),
+			input:            testInput{value: 15},
+			want:             false,
+			wantChildren:     3,
+			wantShortCircuit: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluator := NewEvaluator(tt.rule)
+
+			result := evaluator.EvaluateDetailed(tt.input)
+			if result.Satisfied != tt.want || result.Error != nil {
+				t.Errorf("EvaluateDetailed() = %v, %v; want %v, nil", result.Satisfied, result.Error, tt.want)
+			}
+			if len(result.Children) != tt.wantChildren {
+				t.Errorf("Expected %d children, got %d", tt.wantChildren, len(result.Children))
+			}
+
+			result = evaluator.EvaluateDetailedShortCircuit(tt.input)
+			if result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailedShortCircuit() = %v, want %v", result.Satisfied, tt.want)
+			}
+			if len(result.Children) != tt.wantShortCircuit {
+				t.Errorf("Expected %d short-circuit children, got %d", tt.wantShortCircuit, len(result.Children))
+			}
+		})
+	}
+}
+
+func TestQuantifierIntrospection(t *testing.T) {
+	t.Parallel()
+
+	rule := AtLeast("premium", 2, Always[testInput]("a"), Never[testInput]("b"), nil)
+
+	quantifier, ok := rule.(*quantifierRule[testInput])
+	if !ok {
+		t.Fatalf("Expected a quantifier rule, got %T", rule)
+	}
+	if quantifier.Threshold() != 2 {
+		t.Errorf("Threshold() = %d, want 2", quantifier.Threshold())
+	}
+	if len(quantifier.Children()) != 3 {
+		t.Errorf("Expected 3 children, got %d", len(quantifier.Children()))
+	}
+	if got := getRuleType(rule); got != RuleTypeAtLeast {
+		t.Errorf("getRuleType() = %v, want %v", got, RuleTypeAtLeast)
+	}
+	if got := getChildren(rule); len(got) != 3 {
+		t.Errorf("getChildren() returned %d children, want 3", len(got))
+	}
+}
+
+func TestQuantifierNilChildren(t *testing.T) {
+	t.Parallel()
+
+	always, never := Always[testInput]("always"), Never[testInput]("never")
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+		want bool
+	}{
+		{name: "at least", rule: AtLeast("at least", 1, nil, never, always), want: true},
+		{name: "exactly", rule: Exactly("exactly", 2, always, nil, always), want: true},
+		{name: "at most", rule: AtMost("at most", 0, nil, always), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Every evaluation skips nil children
+			if got, err := tt.rule.Evaluate(testInput{}); err != nil || got != tt.want {
+				t.Errorf("Evaluate() = %v, %v; want %v", got, err, tt.want)
+			}
+			if result := NewEvaluator(tt.rule).EvaluateDetailed(testInput{}); result.Error != nil || result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailed() = %v", result)
+			}
+			if got, err := Compile(tt.rule).Evaluate(testInput{}); err != nil || got != tt.want {
+				t.Errorf("Compile().Evaluate() = %v, %v; want %v", got, err, tt.want)
+			}
+			if truth, err := EvaluateTruth(context.Background(), tt.rule, testInput{}); err != nil || truth != truthOf(tt.want) {
+				t.Errorf("EvaluateTruth() = %v, %v; want %v", truth, err, tt.want)
+			}
+			if _, err := NewTruthTable(tt.rule, TruthTableOptions{}); err != nil {
+				t.Errorf("NewTruthTable() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestNoneOfWithEmptyAndNilRules(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NoneOf[testInput]("empty").Evaluate(testInput{}); !errors.Is(err, ErrEmptyRules) {
+		t.Errorf("Expected ErrEmptyRules, got %v", err)
+	}
+
+	if _, err := NoneOf("nil", Never[testInput]("never"), nil).Evaluate(testInput{}); !errors.Is(err, ErrNilRule) {
+		t.Errorf("Expected ErrNilRule, got %v", err)
+	}
+}
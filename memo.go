@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ruleIdentity identifies a rule instance. Rules are identified by their
+// pointer, qualified by their type so that a struct and its first field
+// cannot collide.
+type ruleIdentity struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// identify returns the identity of a rule. Only rules implemented by
+// pointers have an identity.
+func identify(rule any) (ruleIdentity, bool) {
+	v := reflect.ValueOf(rule)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ruleIdentity{}, false
+	}
+
+	return ruleIdentity{typ: v.Type(), ptr: v.Pointer()}, true
+}
+
+// memoKey identifies a rule within one scope of an evaluation.
+type memoKey struct {
+	rule  ruleIdentity
+	scope int
+}
+
+// memo caches the results of rules during a single detailed evaluation.
+// It is safe for concurrent use by parallel composites.
+type memo struct {
+	mu      sync.Mutex
+	results map[memoKey]Result
+	scopes  int
+}
+
+func newMemo() *memo {
+	return &memo{results: make(map[memoKey]Result)}
+}
+
+// key returns the memoization key of a rule in the given scope. Only rules
+// implemented by pointers have an identity and can be memoized.
+func (m *memo) key(rule any, scope int) (memoKey, bool) {
+	id, ok := identify(rule)
+	if !ok {
+		return memoKey{}, false
+	}
+
+	return memoKey{rule: id, scope: scope}, true
+}
+
+func (m *memo) load(key memoKey) (Result, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.results[key]
+	return result, ok
+}
+
+func (m *memo) store(key memoKey, result Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results[key] = result
+}
+
+// newScope allocates a new scope. Rules evaluated on a different input,
+// such as the rules below Map or ForAll, are memoized in their own scope.
+func (m *memo) newScope() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scopes++
+	return m.scopes
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingRule returns a rule that counts how often it is evaluated.
+func countingRule(name string, calls *atomic.Int32, result bool) Rule[testInput] {
+	return New(name, func(input testInput) (bool, error) {
+		calls.Add(1)
+		return result, nil
+	})
+}
+
+func TestWithMemoization(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shared rule is evaluated once", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		verified := countingRule("customer is verified", &calls, true)
+		rule := Or("eligible",
+			And("premium", verified, Never[testInput]("premium member")),
+			And("regular", verified, Always[testInput]("regular member")),
+		)
+
+		result := NewEvaluator(rule, WithMemoization()).EvaluateDetailed(testInput{})
+		if !result.Satisfied {
+			t.Fatal("Expected rule to be satisfied")
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("Expected 1 evaluation, got %d", got)
+		}
+
+		first := result.Children[0].Children[0]
+		second := result.Children[1].Children[0]
+		if first.Cached || !second.Cached {
+			t.Errorf("Expected only the second occurrence to be cached, got %v and %v", first.Cached, second.Cached)
+		}
+		if !second.Satisfied || second.RuleName != "customer is verified" {
+			t.Errorf("Unexpected cached result: %+v", second)
+		}
+		if !strings.Contains(result.String(), "customer is verified (took 0s) [cached]") {
+			t.Errorf("Expected cached marker in output, got:\n%s", result.String())
+		}
+	})
+
+	t.Run("short-circuit mode", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		verified := countingRule("customer is verified", &calls, false)
+		rule := Or("eligible",
+			And("premium", verified, Always[testInput]("premium member")),
+			And("regular", verified, Always[testInput]("regular member")),
+		)
+
+		result := NewEvaluator(rule, WithMemoization()).EvaluateDetailedShortCircuit(testInput{})
+		if result.Satisfied {
+			t.Fatal("Expected rule to not be satisfied")
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("Expected 1 evaluation, got %d", got)
+		}
+		if len(result.Children[1].Children) != 1 || !result.Children[1].Children[0].Cached {
+			t.Errorf("Expected the second branch to stop at the cached result, got %v", result.Children[1])
+		}
+	})
+
+	t.Run("each evaluation starts empty", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		verified := countingRule("customer is verified", &calls, true)
+		evaluator := NewEvaluator(And("both", verified, verified), WithMemoization())
+
+		evaluator.EvaluateDetailed(testInput{})
+		evaluator.EvaluateDetailed(testInput{})
+		if got := calls.Load(); got != 2 {
+			t.Errorf("Expected 2 evaluations, got %d", got)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		verified := countingRule("customer is verified", &calls, true)
+
+		result := NewEvaluator(And("both", verified, verified)).EvaluateDetailed(testInput{})
+		if got := calls.Load(); got != 2 {
+			t.Errorf("Expected 2 evaluations, got %d", got)
+		}
+		if result.Children[1].Cached {
+			t.Error("Expected no cached results")
+		}
+	})
+
+	t.Run("rules on different inputs are not shared", func(t *testing.T) {
+		t.Parallel()
+
+		positive := New("positive", func(value int) (bool, error) {
+			return value > 0, nil
+		})
+		rule := And("both",
+			Map("value", positive, func(input testInput) int { return input.value }),
+			Map("negated value", positive, func(input testInput) int { return -input.value }),
+		)
+
+		result := NewEvaluator(rule, WithMemoization()).EvaluateDetailed(testInput{value: 1})
+		if result.Satisfied {
+			t.Error("Expected rule to not be satisfied")
+		}
+		if result.Children[1].Children[0].Cached {
+			t.Error("Expected the mapped rules not to share results")
+		}
+	})
+
+	t.Run("parallel composites", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		verified := countingRule("customer is verified", &calls, true)
+		shared := And("shared", verified)
+		rule := ParallelAnd("all", 0, shared, shared, shared, shared)
+
+		result := NewEvaluator(rule, WithMemoization()).EvaluateDetailed(testInput{})
+		if !result.Satisfied {
+			t.Fatal("Expected rule to be satisfied")
+		}
+		// Siblings may race to evaluate the shared rule, but it is never
+		// evaluated more often than it appears
+		if got := calls.Load(); got < 1 || got > 4 {
+			t.Errorf("Unexpected number of evaluations: %d", got)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package rules
+
+// Override forces the outcome of selected rules in the evaluations of an
+// Evaluator (see WithOverrides), so that the logic of a composite can be
+// tested without building inputs that drive every leaf into the right
+// state. The rule tree itself is not modified.
+type Override struct {
+	name      string
+	path      string
+	rule      ruleIdentity
+	byRule    bool
+	satisfied bool
+	err       error
+}
+
+// OverrideName forces every rule with the given name to the given outcome.
+func OverrideName(name string, satisfied bool) Override {
+	return Override{name: name, satisfied: satisfied}
+}
+
+// OverridePath forces the rule at the given path to the given outcome. A
+// path lists the names of the rules from the root of the evaluated tree
+// down to the rule, separated by " > ", e.g. "approval > large loan >
+// good credit", as in the tree of the detailed result.
+func OverridePath(path string, satisfied bool) Override {
+	return Override{path: path, satisfied: satisfied}
+}
+
+// OverrideRule forces every occurrence of the given rule instance to the
+// given outcome. Decorating rules such as WithSeverity select the rule they
+// wrap. References report the rule they refer to, so override the
+// referenced rule instead.
+func OverrideRule[T any](rule Rule[T], satisfied bool) Override {
+	id, ok := identify(unwrapRule(rule))
+	return Override{rule: id, byRule: ok, satisfied: satisfied}
+}
+
+// WithError makes the override force the rule to fail with err instead,
+// e.g. ErrUnknown to simulate a missing fact.
+func (o Override) WithError(err error) Override {
+	o.satisfied, o.err = false, err
+	return o
+}
+
+// WithOverrides forces the outcome of the selected rules in every
+// evaluation of an Evaluator. An overridden rule is not evaluated; its
+// result carries the forced outcome and the rule's name, and its children
+// are not evaluated either. Rules that decorate an overridden rule, such as
+// WithSeverity, still apply, while rules that are not in effect (see
+// Effective) are not overridden. When several overrides select a rule, the
+// first one applies. Overrides by path turn off memoization (see
+// WithMemoization), as a shared rule may be forced at one path only.
+//
+// Overrides are applied in detailed evaluations, so with overrides set
+// Evaluate, EvaluateContext and EvaluateFast evaluate like
+// EvaluateDetailedShortCircuit.
+//
+// Example:
+//
+//	evaluator := rules.NewEvaluator(approval, rules.WithOverrides(
+//	    rules.OverrideName("good credit", true),
+//	    rules.OverridePath("approval > small loan > employed", false),
+//	    rules.OverrideRule(fraudCheck, false).WithError(rules.ErrUnknown),
+//	))
+func WithOverrides(overrides ...Override) EvaluatorOption {
+	return func(c *evaluatorConfig) {
+		c.overrides = append(c.overrides, overrides...)
+	}
+}
+
+// hasPathOverride reports whether any of the overrides selects a rule by
+// path.
+func hasPathOverride(overrides []Override) bool {
+	for _, o := range overrides {
+		if o.path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// transparentRule reports whether a rule reports the result of another
+// rule as its own. Such rules are not overridden and do not add to the
+// path of the rules below them.
+func transparentRule(rule any) bool {
+	if _, ok := rule.(wrappingRule); ok {
+		return true
+	}
+	_, ok := rule.(reference)
+	return ok
+}
+
+// enter returns the state for evaluating a rule and its children and, if
+// an override selects the rule, its forced result.
+func (ev *evaluation) enter(rule interface{ Name() string }) (*evaluation, Result, bool) {
+	if ev.overrides == nil || transparentRule(rule) {
+		return ev, Result{}, false
+	}
+
+	path := childPath(ev.path, rule.Name())
+	if result, ok := override(ev.overrides, rule, path); ok {
+		return ev, result, true
+	}
+	entered := *ev
+	entered.path = path
+	return &entered, Result{}, false
+}
+
+// override returns the forced result of the rule at path, if an override
+// selects it.
+func override(overrides []Override, rule interface{ Name() string }, path string) (Result, bool) {
+	name := rule.Name()
+	id, hasIdentity := identify(rule)
+	for _, o := range overrides {
+		switch {
+		case o.byRule && (!hasIdentity || o.rule != id):
+			continue
+		case o.path != "" && o.path != path:
+			continue
+		case o.name != "" && o.name != name:
+			continue
+		case !o.byRule && o.path == "" && o.name == "":
+			continue
+		}
+		return Result{Satisfied: o.satisfied, RuleName: name, Error: o.err}, true
+	}
+	return Result{}, false
+}
+
+// childPath returns the path of a rule below the rule at parent.
+func childPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + " > " + name
+}
@@ -0,0 +1,201 @@
+package rules
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// overrideLeaves are the leaves of the tree returned by overrideTree.
+type overrideLeaves struct {
+	a, b, c, d Rule[testInput]
+}
+
+// overrideTree returns root = a AND (b OR c) AND d, where d is a warning.
+// Without overrides a and b are satisfied, c and d are not, and the root is
+// satisfied.
+func overrideTree(calls *atomic.Int32) (Rule[testInput], overrideLeaves) {
+	leaves := overrideLeaves{
+		a: countingRule("a", calls, true),
+		b: countingRule("b", calls, true),
+		c: countingRule("c", calls, false),
+		d: countingRule("d", calls, false),
+	}
+	root := And("root",
+		leaves.a,
+		Or("either", leaves.b, leaves.c),
+		WithSeverity(leaves.d, SeverityWarning),
+	)
+	return root, leaves
+}
+
+func TestWithOverrides(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		overrides func(leaves overrideLeaves) []Override
+		want      bool
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name:      "none",
+			overrides: func(overrideLeaves) []Override { return nil },
+			want:      true,
+			wantCalls: 4,
+		},
+		{
+			name:      "by name",
+			overrides: func(overrideLeaves) []Override { return []Override{OverrideName("a", false)} },
+			want:      false,
+			wantCalls: 3,
+		},
+		{
+			name:      "by path",
+			overrides: func(overrideLeaves) []Override { return []Override{OverridePath("root > either > b", false)} },
+			want:      false,
+			wantCalls: 3,
+		},
+		{
+			name:      "by path not matching",
+			overrides: func(overrideLeaves) []Override { return []Override{OverridePath("root > b", false)} },
+			want:      true,
+			wantCalls: 4,
+		},
+		{
+			name:      "by rule",
+			overrides: func(leaves overrideLeaves) []Override { return []Override{OverrideRule(leaves.b, false)} },
+			want:      false,
+			wantCalls: 3,
+		},
+		{
+			name:      "composite",
+			overrides: func(overrideLeaves) []Override { return []Override{OverrideName("either", false)} },
+			want:      false,
+			wantCalls: 2,
+		},
+		{
+			name: "error",
+			overrides: func(overrideLeaves) []Override {
+				return []Override{OverrideName("a", true).WithError(errBoom)}
+			},
+			wantErr:   errBoom,
+			wantCalls: 0,
+		},
+		{
+			name: "first override applies",
+			overrides: func(overrideLeaves) []Override {
+				return []Override{OverrideName("a", false), OverrideName("a", true)}
+			},
+			want:      false,
+			wantCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			root, leaves := overrideTree(&calls)
+			result := NewEvaluator(root, WithOverrides(tt.overrides(leaves)...)).EvaluateDetailed(testInput{})
+
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, result.Error)
+				}
+			} else if result.Error != nil || result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailed() = %v, %v; want %v", result.Satisfied, result.Error, tt.want)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Leaves evaluated %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithOverrides_Decorators(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	root, leaves := overrideTree(&calls)
+
+	// The severity of d still applies to its forced outcome
+	evaluator := NewEvaluator(root, WithOverrides(OverrideRule(leaves.d, false), OverrideName("a", true)))
+	result := evaluator.EvaluateDetailed(testInput{})
+	if !result.Satisfied || len(result.Children) != 3 {
+		t.Fatalf("EvaluateDetailed() = %v", result)
+	}
+	if d := result.Children[2]; d.RuleName != "d" || d.Satisfied || d.Severity != SeverityWarning {
+		t.Errorf("Unexpected result of d: %+v", d)
+	}
+
+	// Rules that are not in effect are not overridden
+	rule := And("root", leaves.a, Effective(leaves.c, effectiveJan1, effectiveJul1))
+	evaluator = NewEvaluator(rule, WithOverrides(OverrideName("c", false)), WithClock(func() time.Time {
+		return effectiveJul1
+	}))
+	if satisfied, err := evaluator.EvaluateFast(testInput{}); !satisfied || err != nil {
+		t.Errorf("EvaluateFast() = %v, %v; want true", satisfied, err)
+	}
+}
+
+func TestWithOverrides_Memoization(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	x := countingRule("x", &calls, true)
+	shared := And("shared", x)
+	root := And("root", Or("a", shared), Or("b", shared))
+
+	tests := []struct {
+		name      string
+		overrides []Override
+		want      []bool // outcomes of a and b
+	}{
+		{name: "by path", overrides: []Override{OverridePath("root > a > shared > x", false)}, want: []bool{false, true}},
+		{name: "by name", overrides: []Override{OverrideName("x", false)}, want: []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := NewEvaluator(root, WithMemoization(), WithOverrides(tt.overrides...)).EvaluateDetailed(testInput{})
+			if len(result.Children) != 2 {
+				t.Fatalf("EvaluateDetailed() = %v", result)
+			}
+			for i, want := range tt.want {
+				if got := result.Children[i]; got.Satisfied != want {
+					t.Errorf("%s = %v (cached %v), want %v", got.RuleName, got.Satisfied, got.Cached, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithOverrides_AllMethods(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	root, _ := overrideTree(&calls)
+	evaluator := NewEvaluator(root, WithOverrides(OverridePath("root > a", false)))
+
+	if result := evaluator.Evaluate(testInput{}); result.Satisfied || result.Children != nil {
+		t.Errorf("Evaluate() = %+v, want unsatisfied without children", result)
+	}
+	if satisfied, err := evaluator.EvaluateFast(testInput{}); satisfied || err != nil {
+		t.Errorf("EvaluateFast() = %v, %v; want false", satisfied, err)
+	}
+	if result := evaluator.EvaluateDetailedShortCircuit(testInput{}); result.Satisfied || len(result.Children) != 1 {
+		t.Errorf("EvaluateDetailedShortCircuit() = %v", result)
+	}
+	// Short-circuiting stops at the forced leaf
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Leaves evaluated %d times, want 0", got)
+	}
+}
@@ -0,0 +1,275 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// parallelRule represents a logical AND or OR whose children are evaluated
+// concurrently by a bounded number of workers.
+type parallelRule[T any] struct {
+	name    string
+	op      RuleType
+	workers int
+	rules   []Rule[T]
+}
+
+// ParallelAnd creates a rule that is satisfied only if all provided rules
+// are satisfied, evaluating the children concurrently with at most workers
+// goroutines (workers <= 0 means one goroutine per child).
+//
+// As soon as a blocking child is not satisfied or a child fails, all other
+// children are cancelled through their context. The first child to finish
+// with such an outcome decides: ParallelAnd reports its error, or false.
+// Unlike And, an error of a slower child that comes earlier is therefore not
+// reported. Automatically inherits domains from child rules.
+func ParallelAnd[T any](name string, workers int, rules ...Rule[T]) Rule[T] {
+	return newParallelRule(name, RuleTypeAnd, workers, rules)
+}
+
+// ParallelOr creates a rule that is satisfied if at least one of the
+// provided rules is satisfied, evaluating the children concurrently with at
+// most workers goroutines (workers <= 0 means one goroutine per child).
+//
+// As soon as a child is satisfied or fails, all other children are cancelled
+// through their context. The first child to finish with such an outcome
+// decides: ParallelOr reports its error, or true. Unlike Or, an error of a
+// slower child that comes earlier is therefore not reported. Automatically
+// inherits domains from child rules.
+func ParallelOr[T any](name string, workers int, rules ...Rule[T]) Rule[T] {
+	return newParallelRule(name, RuleTypeOr, workers, rules)
+}
+
+func newParallelRule[T any](name string, op RuleType, workers int, rules []Rule[T]) Rule[T] {
+	rule := &parallelRule[T]{
+		name:    name,
+		op:      op,
+		workers: workers,
+		rules:   rules,
+	}
+
+	// Collect and deduplicate domains from children
+	domains := collectDomainsFromRules(rules)
+	if len(domains) > 0 {
+		_ = Register(rule, WithDomains(domains...))
+	}
+
+	return rule
+}
+
+func (r *parallelRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *parallelRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if len(r.rules) == 0 {
+		return false, fmt.Errorf(
+			"evaluating %s rule %q: %w",
+			r.op,
+			r.name,
+			ErrEmptyRules,
+		)
+	}
+
+	outcomes := make([]bool, len(r.rules))
+	errs := make([]error, len(r.rules))
+
+	decided := runParallel(ctx, len(r.rules), r.workers, false, func(ctx context.Context, i int) bool {
+		if r.rules[i] == nil {
+			errs[i] = ErrNilRule
+			return true
+		}
+		if !inEffect(ctx, r.rules[i]) {
+			return false
+		}
+		outcomes[i], errs[i] = EvaluateContext(ctx, r.rules[i], input)
+		return errs[i] != nil || r.decides(r.rules[i], outcomes[i])
+	})
+
+	if decided == len(r.rules) {
+		// No child decided the outcome early
+		return r.op == RuleTypeAnd, nil
+	}
+
+	if errs[decided] != nil {
+		return false, fmt.Errorf(
+			"evaluating %s rule %q: %w",
+			r.op,
+			r.name,
+			errs[decided],
+		)
+	}
+
+	return outcomes[decided], nil
+}
+
+func (r *parallelRule[T]) Name() string {
+	return r.name
+}
+
+// Children returns the child rules (for documentation purposes).
+func (r *parallelRule[T]) Children() []Rule[T] {
+	return r.rules
+}
+
+// Kind reports whether the rule combines its children as AND or OR.
+func (r *parallelRule[T]) Kind() RuleType {
+	return r.op
+}
+
+// Combine combines the child results like And or Or.
+func (r *parallelRule[T]) Combine(results []Result, total int) (bool, bool, error) {
+	if r.op == RuleTypeAnd {
+		return combineAnd(results, total)
+	}
+	return combineOr(results, total)
+}
+
+// decides reports whether a child outcome decides the composite: the first
+// blocking false for AND, the first true for OR.
+func (r *parallelRule[T]) decides(child Rule[T], satisfied bool) bool {
+	if r.op == RuleTypeAnd {
+		return !satisfied && severityOf(child).isBlocking()
+	}
+	return satisfied
+}
+
+// evaluateParallelDetailed evaluates the children of a parallel rule
+// concurrently and returns their results in child order.
+//
+// Only children up to and including the first deciding child in child order
+// are reported, which makes the result identical to a sequential evaluation
+// regardless of scheduling. Children before the deciding one are therefore
+// not cancelled. In full mode only errors decide; in short-circuit mode the
+// first false (AND) or true (OR) does as well.
+func evaluateParallelDetailed[T any](
+	ev *evaluation,
+	r *parallelRule[T],
+	input T,
+) (bool, []Result, error) {
+	if len(r.rules) == 0 {
+		return false, nil, ErrEmptyRules
+	}
+
+	results := make([]Result, len(r.rules))
+
+	decided := runParallel(ev.ctx, len(r.rules), r.workers, true, func(ctx context.Context, i int) bool {
+		if r.rules[i] == nil {
+			return true
+		}
+		childEv := *ev
+		childEv.ctx = ctx
+		results[i] = evaluateRuleDetailed(&childEv, r.rules[i], input)
+		if results[i].Error != nil {
+			return true
+		}
+		if results[i].Inactive {
+			return false
+		}
+		return ev.shortCircuit && r.decides(r.rules[i], results[i].Satisfied)
+	})
+
+	if decided < len(r.rules) {
+		if r.rules[decided] == nil {
+			return false, results[:decided], ErrNilRule
+		}
+		results = results[:decided+1]
+	}
+
+	satisfied := r.op == RuleTypeAnd
+	for i, result := range results {
+		if result.Error != nil {
+			return false, results, result.Error
+		}
+		if !result.Inactive && r.decides(r.rules[i], result.Satisfied) {
+			satisfied = result.Satisfied
+		}
+	}
+
+	return satisfied, results, nil
+}
+
+// runParallel calls eval for the indices 0..n-1 using at most workers
+// goroutines and returns the index for which eval first reported that the
+// outcome was decided, or n if none did.
+//
+// Each call receives its own context derived from ctx. When index i decides
+// first, the contexts of all other indices are cancelled and indices that
+// have not started yet are skipped.
+//
+// If ordered is set, the lowest deciding index is returned instead: when
+// index i decides, only the contexts of higher indices are cancelled and
+// lower indices run to completion, so every index below the returned one
+// has been evaluated.
+func runParallel(
+	ctx context.Context,
+	n int,
+	workers int,
+	ordered bool,
+	eval func(ctx context.Context, i int) bool,
+) int {
+	if workers <= 0 || workers > n {
+		workers = n
+	}
+
+	contexts := make([]context.Context, n)
+	cancels := make([]context.CancelFunc, n)
+	for i := range contexts {
+		contexts[i], cancels[i] = context.WithCancel(ctx)
+	}
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	var mu sync.Mutex
+	decided := n
+
+	indices := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				mu.Lock()
+				skip := i > decided || !ordered && decided < n
+				mu.Unlock()
+				if skip {
+					continue
+				}
+
+				if !eval(contexts[i], i) {
+					continue
+				}
+
+				mu.Lock()
+				switch {
+				case ordered && i < decided:
+					decided = i
+					for j := i + 1; j < n; j++ {
+						cancels[j]()
+					}
+				case !ordered && decided == n:
+					decided = i
+					for j := range cancels {
+						if j != i {
+							cancels[j]()
+						}
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return decided
+}
@@ -0,0 +1,231 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingRule returns a context-aware rule that blocks until its context is
+// done and then reports the context's error.
+func blockingRule(name string) Rule[testInput] {
+	return NewContext(name, func(ctx context.Context, input testInput) (bool, error) {
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+}
+
+func TestParallelAnd(t *testing.T) {
+	t.Parallel()
+
+	isPositive := New("positive", func(input testInput) (bool, error) {
+		return input.value > 0, nil
+	})
+	isValid := New("valid", func(input testInput) (bool, error) {
+		return input.valid, nil
+	})
+	fails := New("fails", func(input testInput) (bool, error) {
+		return false, ErrEvaluationFailed
+	})
+
+	tests := []struct {
+		name    string
+		rule    Rule[testInput]
+		input   testInput
+		want    bool
+		wantErr error
+	}{
+		{
+			name:  "all satisfied",
+			rule:  ParallelAnd("all", 2, isPositive, isValid),
+			input: testInput{value: 1, valid: true},
+			want:  true,
+		},
+		{
+			name:  "one not satisfied",
+			rule:  ParallelAnd("all", 2, isPositive, isValid),
+			input: testInput{value: 1, valid: false},
+			want:  false,
+		},
+		{
+			name:  "false cancels later siblings",
+			rule:  ParallelAnd("all", 0, isValid, blockingRule("blocks")),
+			input: testInput{valid: false},
+			want:  false,
+		},
+		{
+			name:  "false cancels earlier siblings",
+			rule:  ParallelAnd("all", 0, blockingRule("blocks"), isValid),
+			input: testInput{valid: false},
+			want:  false,
+		},
+		{
+			name:    "error cancels siblings",
+			rule:    ParallelAnd("all", 0, blockingRule("blocks"), fails),
+			wantErr: ErrEvaluationFailed,
+		},
+		{
+			name:    "empty rules",
+			rule:    ParallelAnd[testInput]("empty", 2),
+			wantErr: ErrEmptyRules,
+		},
+		{
+			name:    "nil rule",
+			rule:    ParallelAnd("nil", 2, isPositive, nil),
+			input:   testInput{value: 1},
+			wantErr: ErrNilRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.rule.Evaluate(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Evaluate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParallelOr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("true cancels later siblings", func(t *testing.T) {
+		t.Parallel()
+
+		rule := ParallelOr("any", 0, Always[testInput]("always"), blockingRule("blocks"))
+		got, err := rule.Evaluate(testInput{})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if !got {
+			t.Error("Evaluate() = false, want true")
+		}
+	})
+
+	t.Run("none satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		rule := ParallelOr("any", 2, Never[testInput]("a"), Never[testInput]("b"))
+		got, err := rule.Evaluate(testInput{})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if got {
+			t.Error("Evaluate() = true, want false")
+		}
+	})
+
+	t.Run("true cancels earlier siblings", func(t *testing.T) {
+		t.Parallel()
+
+		rule := ParallelOr("any", 0, blockingRule("blocks"), Always[testInput]("always"))
+		got, err := rule.Evaluate(testInput{})
+		if err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+	})
+
+	t.Run("first to decide wins over a slower earlier error", func(t *testing.T) {
+		t.Parallel()
+
+		errBoom := errors.New("boom")
+		failing := New("failing", func(input testInput) (bool, error) {
+			time.Sleep(10 * time.Millisecond)
+			return false, errBoom
+		})
+
+		rule := ParallelOr("any", 0, failing, Always[testInput]("always"))
+		got, err := rule.Evaluate(testInput{})
+		if err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+	})
+}
+
+func TestParallelWorkerLimit(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+	child := New("slow", func(input testInput) (bool, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return true, nil
+	})
+
+	rule := ParallelAnd("bounded", 2, child, child, child, child, child, child)
+	if _, err := rule.Evaluate(testInput{}); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if got := maxRunning.Load(); got > 2 {
+		t.Errorf("Expected at most 2 concurrent children, got %d", got)
+	}
+}
+
+func TestEvaluatorDetailedParallel(t *testing.T) {
+	t.Parallel()
+
+	slowTrue := New("slow true", func(input testInput) (bool, error) {
+		time.Sleep(10 * time.Millisecond)
+		return true, nil
+	})
+	fastFalse := New("fast false", func(input testInput) (bool, error) {
+		return false, nil
+	})
+
+	rule := ParallelAnd("checks", 0, slowTrue, fastFalse, blockingRule("blocks"))
+
+	t.Run("short circuit keeps child order", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailedShortCircuit(testInput{})
+		if result.Satisfied || result.Error != nil {
+			t.Fatalf("Expected unsatisfied result without error, got %+v", result)
+		}
+
+		names := make([]string, len(result.Children))
+		for i, child := range result.Children {
+			names[i] = child.RuleName
+		}
+		if len(names) != 2 || names[0] != "slow true" || names[1] != "fast false" {
+			t.Errorf("Expected children [slow true, fast false], got %v", names)
+		}
+	})
+
+	t.Run("full mode evaluates all children", func(t *testing.T) {
+		t.Parallel()
+
+		full := ParallelOr("any", 2, fastFalse, slowTrue, fastFalse)
+		result := NewEvaluator(full).EvaluateDetailed(testInput{})
+		if !result.Satisfied {
+			t.Error("Expected rule to be satisfied")
+		}
+		if len(result.Children) != 3 {
+			t.Fatalf("Expected 3 children, got %d", len(result.Children))
+		}
+		if !result.Children[1].Satisfied || result.Children[0].Satisfied {
+			t.Errorf("Expected children in declaration order, got %v", result.Children)
+		}
+	})
+}
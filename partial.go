@@ -0,0 +1,300 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknown is returned by predicates whose input lacks a fact they depend
+// on (e.g. a credit score that has not been fetched yet). EvaluateTruth and
+// EvaluatePartial treat such rules as unknown; other evaluations report it
+// as an error.
+var ErrUnknown = errors.New("unknown fact")
+
+// Truth is the outcome of a rule in three-valued (Kleene) logic.
+type Truth int
+
+const (
+	// TruthUnknown means the outcome depends on facts that are not known
+	// yet.
+	TruthUnknown Truth = iota
+	// TruthFalse means the rule is not satisfied, whatever the unknown
+	// facts turn out to be.
+	TruthFalse
+	// TruthTrue means the rule is satisfied, whatever the unknown facts turn
+	// out to be.
+	TruthTrue
+)
+
+// truthOf converts a two-valued outcome to a Truth.
+func truthOf(satisfied bool) Truth {
+	if satisfied {
+		return TruthTrue
+	}
+	return TruthFalse
+}
+
+// String returns the string representation of a Truth.
+func (t Truth) String() string {
+	switch t {
+	case TruthUnknown:
+		return "unknown"
+	case TruthFalse:
+		return "false"
+	case TruthTrue:
+		return "true"
+	default:
+		return fmt.Sprintf("Truth(%d)", int(t))
+	}
+}
+
+// Known reports whether the outcome is decided.
+func (t Truth) Known() bool {
+	return t == TruthTrue || t == TruthFalse
+}
+
+// PartialEvaluation is the outcome of evaluating a rule against partially
+// known input (see EvaluatePartial).
+type PartialEvaluation[T any] struct {
+	// Truth is the outcome of the rule.
+	Truth Truth
+
+	// Residual is the part of the rule still to be decided when Truth is
+	// TruthUnknown, and nil otherwise. Evaluating it once the missing facts
+	// are known gives the outcome of the whole rule.
+	Residual Rule[T]
+}
+
+// EvaluateTruth evaluates a rule in three-valued (Kleene) logic. Rules
+// returning an error that wraps ErrUnknown are unknown, and composites are
+// unknown only if their outcome depends on such rules:
+//
+//   - AND is false if a blocking child is false, and unknown if a blocking
+//     child is unknown and none is false
+//   - OR is true if a child is true, and unknown if a child is unknown and
+//     none is true
+//   - NOT of unknown is unknown
+//   - AtLeast, Exactly, AtMost and NoneOf are unknown if their outcome
+//     depends on how many of the unknown children are satisfied
+//
+// Children that are not in effect are skipped as in Evaluate. Parallel
+// rules are evaluated sequentially. Other errors are returned as in
+// Evaluate.
+func EvaluateTruth[T any](ctx context.Context, rule Rule[T], input T) (Truth, error) {
+	partial, err := EvaluatePartial(ctx, rule, input)
+	return partial.Truth, err
+}
+
+// EvaluatePartial is like EvaluateTruth but also returns the residual rule:
+// the rule with every decided child removed and the thresholds of
+// quantifiers lowered by the number of satisfied children. Composites in
+// the residual keep their names; its leaves are the unknown leaves of the
+// rule. References, reloadable and compiled rules are replaced by the
+// residual of the rule they evaluate. Residual rules are not registered.
+//
+// Example:
+//
+//	partial, err := rules.EvaluatePartial(ctx, approval, Application{Amount: 500})
+//	if err != nil {
+//	    return err
+//	}
+//	if partial.Truth == rules.TruthUnknown {
+//	    // Fetch the credit score, then evaluate partial.Residual
+//	}
+func EvaluatePartial[T any](ctx context.Context, rule Rule[T], input T) (PartialEvaluation[T], error) {
+	truth, residual, err := evaluatePartial(ctx, rule, input)
+	if err != nil {
+		return PartialEvaluation[T]{}, err
+	}
+	return PartialEvaluation[T]{Truth: truth, Residual: residual}, nil
+}
+
+// evaluatePartial evaluates a rule in three-valued logic and returns its
+// residual if it is unknown.
+func evaluatePartial[T any](ctx context.Context, rule Rule[T], input T) (Truth, Rule[T], error) {
+	if err := ctx.Err(); err != nil {
+		return TruthUnknown, nil, err
+	}
+
+	switch r := rule.(type) {
+	case *andRule[T]:
+		return evaluatePartialJunction(ctx, RuleTypeAnd, r.name, r.rules, input, func(children []Rule[T]) Rule[T] {
+			return newAndRule(r.name, children)
+		})
+	case *orRule[T]:
+		return evaluatePartialJunction(ctx, RuleTypeOr, r.name, r.rules, input, func(children []Rule[T]) Rule[T] {
+			return newOrRule(r.name, children)
+		})
+	case *parallelRule[T]:
+		return evaluatePartialJunction(ctx, r.op, r.name, r.rules, input, func(children []Rule[T]) Rule[T] {
+			return &parallelRule[T]{name: r.name, op: r.op, workers: r.workers, rules: children}
+		})
+	case *notRule[T]:
+		if r.rule == nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating NOT rule %q: %w", r.name, ErrNilRule)
+		}
+		if !inEffect(ctx, r.rule) {
+			return TruthTrue, nil, nil
+		}
+		truth, residual, err := evaluatePartial(ctx, r.rule, input)
+		if err != nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating NOT rule %q: %w", r.name, err)
+		}
+		switch truth {
+		case TruthTrue:
+			return TruthFalse, nil, nil
+		case TruthFalse:
+			return TruthTrue, nil, nil
+		default:
+			return TruthUnknown, newNotRule(r.name, residual), nil
+		}
+	case *quantifierRule[T]:
+		return evaluatePartialQuantifier(ctx, r.evaluated(), input)
+	case *severityRule[T]:
+		if r.rule == nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule)
+		}
+		truth, residual, err := evaluatePartial(ctx, r.rule, input)
+		if err != nil || truth.Known() {
+			return truth, nil, err
+		}
+		return TruthUnknown, &severityRule[T]{rule: residual, severity: r.severity}, nil
+	case *effectiveRule[T]:
+		if r.rule == nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule)
+		}
+		if !withinWindow(evaluationTime(ctx), r.from, r.until) {
+			return TruthTrue, nil, nil
+		}
+		truth, residual, err := evaluatePartial(ctx, r.rule, input)
+		if err != nil || truth.Known() {
+			return truth, nil, err
+		}
+		return TruthUnknown, &effectiveRule[T]{rule: residual, from: r.from, until: r.until}, nil
+	case *refRule[T]:
+		target, err := r.target()
+		if err != nil {
+			return TruthUnknown, nil, err
+		}
+		truth, residual, err := evaluatePartial(ctx, target, input)
+		if err != nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating reference %q: %w", r.refName, err)
+		}
+		return truth, residual, nil
+	case *Reloadable[T]:
+		return evaluatePartial(ctx, r.Current(), input)
+	case *CompiledRule[T]:
+		return evaluatePartial(ctx, r.rule, input)
+	default:
+		satisfied, err := EvaluateContext(ctx, rule, input)
+		if errors.Is(err, ErrUnknown) {
+			return TruthUnknown, rule, nil
+		}
+		if err != nil {
+			return TruthUnknown, nil, err
+		}
+		return truthOf(satisfied), nil, nil
+	}
+}
+
+// evaluatePartialJunction evaluates an AND or OR rule in three-valued
+// logic. A blocking false child decides an AND and a true child decides an
+// OR, whatever the unknown children turn out to be. The residual is built
+// from the unknown children with rebuild.
+func evaluatePartialJunction[T any](
+	ctx context.Context,
+	kind RuleType,
+	name string,
+	rules []Rule[T],
+	input T,
+	rebuild func(children []Rule[T]) Rule[T],
+) (Truth, Rule[T], error) {
+	if len(rules) == 0 {
+		return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", kind, name, ErrEmptyRules)
+	}
+
+	conjunctive := kind == RuleTypeAnd
+	unknown := false
+	var undecided []Rule[T]
+	for _, child := range rules {
+		if child == nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", kind, name, ErrNilRule)
+		}
+		if !inEffect(ctx, child) {
+			continue
+		}
+
+		truth, residual, err := evaluatePartial(ctx, child, input)
+		if err != nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", kind, name, err)
+		}
+
+		blocking := !conjunctive || severityOf(child).isBlocking()
+		switch {
+		case truth == TruthUnknown:
+			// Unknown non-blocking children cannot fail an AND but are kept
+			// in the residual to be reported
+			undecided = append(undecided, residual)
+			unknown = unknown || blocking
+		case conjunctive && truth == TruthFalse && blocking:
+			return TruthFalse, nil, nil
+		case !conjunctive && truth == TruthTrue:
+			return TruthTrue, nil, nil
+		}
+	}
+
+	if !unknown {
+		return truthOf(conjunctive), nil, nil
+	}
+	return TruthUnknown, rebuild(undecided), nil
+}
+
+// evaluatePartialQuantifier evaluates a quantifier rule in three-valued
+// logic. The outcome is decided if it is the same however many of the
+// unknown children are satisfied.
+func evaluatePartialQuantifier[T any](ctx context.Context, r *quantifierRule[T], input T) (Truth, Rule[T], error) {
+	if r.kind == RuleTypeNoneOf && len(r.rules) == 0 {
+		return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, ErrEmptyRules)
+	}
+
+	satisfied := 0
+	var undecided []Rule[T]
+	for _, child := range r.rules {
+		if child == nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, ErrNilRule)
+		}
+		if !inEffect(ctx, child) {
+			continue
+		}
+
+		truth, residual, err := evaluatePartial(ctx, child, input)
+		if err != nil {
+			return TruthUnknown, nil, fmt.Errorf("evaluating %s rule %q: %w", r.kind, r.name, err)
+		}
+		switch truth {
+		case TruthTrue:
+			satisfied++
+		case TruthUnknown:
+			undecided = append(undecided, residual)
+		}
+
+		if r.settled(satisfied) {
+			return truthOf(r.outcome(satisfied)), nil, nil
+		}
+	}
+
+	outcome := r.outcome(satisfied)
+	for count := satisfied + 1; count <= satisfied+len(undecided); count++ {
+		if r.outcome(count) != outcome {
+			residual := &quantifierRule[T]{
+				name:  r.name,
+				kind:  r.kind,
+				n:     r.n - satisfied,
+				rules: undecided,
+			}
+			return TruthUnknown, residual, nil
+		}
+	}
+	return truthOf(outcome), nil, nil
+}
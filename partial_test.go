@@ -0,0 +1,180 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// creditApplication is an application whose credit score may not have been
+// fetched yet.
+type creditApplication struct {
+	Amount      int
+	CreditScore *int
+	Employed    bool
+}
+
+// truthRule returns a leaf rule with a fixed three-valued outcome.
+func truthRule(name string, truth Truth) Rule[testInput] {
+	return New(name, func(testInput) (bool, error) {
+		if truth == TruthUnknown {
+			return false, ErrUnknown
+		}
+		return truth == TruthTrue, nil
+	})
+}
+
+func TestEvaluateTruth(t *testing.T) {
+	t.Parallel()
+
+	var (
+		yes     = truthRule("yes", TruthTrue)
+		no      = truthRule("no", TruthFalse)
+		unknown = truthRule("unknown", TruthUnknown)
+	)
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+		want Truth
+	}{
+		{name: "leaf", rule: unknown, want: TruthUnknown},
+		{name: "and false", rule: And("and", unknown, no), want: TruthFalse},
+		{name: "and unknown", rule: And("and", yes, unknown), want: TruthUnknown},
+		{name: "and true", rule: And("and", yes, yes), want: TruthTrue},
+		{name: "and non-blocking unknown", rule: And("and", yes, WithSeverity(unknown, SeverityWarning)), want: TruthTrue},
+		{name: "and non-blocking false", rule: And("and", unknown, WithSeverity(no, SeverityInfo)), want: TruthUnknown},
+		{name: "or true", rule: Or("or", unknown, yes), want: TruthTrue},
+		{name: "or unknown", rule: Or("or", no, unknown), want: TruthUnknown},
+		{name: "or false", rule: Or("or", no, no), want: TruthFalse},
+		{name: "not unknown", rule: Not("not", unknown), want: TruthUnknown},
+		{name: "not false", rule: Not("not", Or("or", no, no)), want: TruthTrue},
+		{name: "at least settled", rule: AtLeast("at least", 2, yes, unknown, yes), want: TruthTrue},
+		{name: "at least unknown", rule: AtLeast("at least", 2, yes, unknown, unknown), want: TruthUnknown},
+		{name: "at least unreachable", rule: AtLeast("at least", 2, no, unknown, no), want: TruthFalse},
+		{name: "exactly unknown", rule: Exactly("exactly", 1, yes, unknown), want: TruthUnknown},
+		{name: "exactly exceeded", rule: Exactly("exactly", 1, yes, yes, unknown), want: TruthFalse},
+		{name: "at most within", rule: AtMost("at most", 1, no, unknown), want: TruthTrue},
+		{name: "at most unknown", rule: AtMost("at most", 1, yes, unknown), want: TruthUnknown},
+		{name: "none of violated", rule: NoneOf("none", unknown, yes), want: TruthFalse},
+		{name: "none of unknown", rule: NoneOf("none", no, unknown), want: TruthUnknown},
+		{name: "parallel", rule: ParallelOr("parallel", 2, unknown, yes), want: TruthTrue},
+		{name: "compiled", rule: Compile(And("and", yes, unknown)), want: TruthUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EvaluateTruth(context.Background(), tt.rule, testInput{})
+			if err != nil {
+				t.Fatalf("EvaluateTruth() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateTruth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTruth_Errors(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	unknown := truthRule("unknown", TruthUnknown)
+	failing := New("failing", func(testInput) (bool, error) { return false, errBoom })
+
+	if _, err := EvaluateTruth(context.Background(), Or("or", unknown, failing), testInput{}); !errors.Is(err, errBoom) {
+		t.Errorf("Expected errBoom, got %v", err)
+	}
+	if _, err := EvaluateTruth(context.Background(), Or[testInput]("empty"), testInput{}); !errors.Is(err, ErrEmptyRules) {
+		t.Errorf("Expected ErrEmptyRules, got %v", err)
+	}
+
+	// Two-valued evaluation reports unknown facts as errors
+	if _, err := And("and", unknown).Evaluate(testInput{}); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Expected ErrUnknown, got %v", err)
+	}
+}
+
+func TestEvaluatePartial(t *testing.T) {
+	t.Parallel()
+
+	smallAmount := New("small amount", func(a creditApplication) (bool, error) {
+		return a.Amount <= 1000, nil
+	})
+	goodCredit := New("good credit", func(a creditApplication) (bool, error) {
+		if a.CreditScore == nil {
+			return false, ErrUnknown
+		}
+		return *a.CreditScore >= 700, nil
+	})
+	employed := New("employed", func(a creditApplication) (bool, error) {
+		return a.Employed, nil
+	})
+	approval := Or("approval",
+		And("small loan", smallAmount, employed),
+		AtLeast("large loan", 2, employed, goodCredit, Not("first loan", smallAmount)),
+	)
+
+	t.Run("decided", func(t *testing.T) {
+		t.Parallel()
+
+		partial, err := EvaluatePartial(context.Background(), approval, creditApplication{Amount: 500, Employed: true})
+		if err != nil {
+			t.Fatalf("EvaluatePartial() error = %v", err)
+		}
+		if partial.Truth != TruthTrue || partial.Residual != nil {
+			t.Errorf("EvaluatePartial() = %v, %v; want true without residual", partial.Truth, partial.Residual)
+		}
+	})
+
+	t.Run("residual", func(t *testing.T) {
+		t.Parallel()
+
+		application := creditApplication{Amount: 5000}
+		partial, err := EvaluatePartial(context.Background(), approval, application)
+		if err != nil {
+			t.Fatalf("EvaluatePartial() error = %v", err)
+		}
+		if partial.Truth != TruthUnknown {
+			t.Fatalf("EvaluatePartial() = %v, want unknown", partial.Truth)
+		}
+
+		// Only the credit check of the large loan is left to decide
+		residual, ok := partial.Residual.(*orRule[creditApplication])
+		if !ok || residual.Name() != "approval" || len(residual.rules) != 1 {
+			t.Fatalf("Unexpected residual: %#v", partial.Residual)
+		}
+		quantifier, ok := residual.rules[0].(*quantifierRule[creditApplication])
+		if !ok || quantifier.Name() != "large loan" || quantifier.n != 1 ||
+			len(quantifier.rules) != 1 || quantifier.rules[0] != goodCredit {
+			t.Fatalf("Unexpected residual quantifier: %#v", residual.rules[0])
+		}
+
+		for _, score := range []int{650, 750} {
+			application.CreditScore = &score
+			want, _ := approval.Evaluate(application)
+			if got, err := partial.Residual.Evaluate(application); err != nil || got != want {
+				t.Errorf("Residual.Evaluate() with score %d = %v, %v; want %v", score, got, err, want)
+			}
+		}
+	})
+}
+
+func TestEvaluatePartial_Expression(t *testing.T) {
+	t.Parallel()
+
+	rule, err := CompileExpr[exprOrder]("gold or large", `customer.tier == "gold" || amount > 1000`)
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+
+	partial, err := EvaluatePartial(context.Background(), rule, exprOrder{Amount: 50})
+	if err != nil {
+		t.Fatalf("EvaluatePartial() error = %v", err)
+	}
+	if partial.Truth != TruthUnknown || partial.Residual == nil || partial.Residual.Name() != "gold or large" {
+		t.Errorf("EvaluatePartial() = %v, %v; want an unknown residual", partial.Truth, partial.Residual)
+	}
+}
@@ -0,0 +1,135 @@
+package rules
+
+import "context"
+
+// quantifierRule is satisfied depending on how many of its child rules are
+// satisfied. It backs AtLeast, Exactly, AtMost and NoneOf.
+type quantifierRule[T any] struct {
+	name  string
+	kind  RuleType
+	n     int
+	rules []Rule[T]
+}
+
+// newQuantifierRule creates a quantifier rule and registers it with the
+// domains of its children.
+func newQuantifierRule[T any](name string, kind RuleType, n int, rules []Rule[T]) Rule[T] {
+	rule := &quantifierRule[T]{
+		name:  name,
+		kind:  kind,
+		n:     n,
+		rules: rules,
+	}
+
+	// Collect and deduplicate domains from children
+	domains := collectDomainsFromRules(rules)
+	if len(domains) > 0 {
+		_ = Register(rule, WithDomains(domains...))
+	}
+
+	return rule
+}
+
+// evaluated returns the rule to evaluate in place of r. AtLeast, Exactly and
+// AtMost skip nil children, while NoneOf reports them as an error.
+func (r *quantifierRule[T]) evaluated() *quantifierRule[T] {
+	if r.kind == RuleTypeNoneOf {
+		return r
+	}
+
+	for i, rule := range r.rules {
+		if rule != nil {
+			continue
+		}
+		rules := append(make([]Rule[T], 0, len(r.rules)-1), r.rules[:i]...)
+		for _, rule := range r.rules[i+1:] {
+			if rule != nil {
+				rules = append(rules, rule)
+			}
+		}
+		return &quantifierRule[T]{name: r.name, kind: r.kind, n: r.n, rules: rules}
+	}
+	return r
+}
+
+func (r *quantifierRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *quantifierRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	return EvaluateComposite(ctx, r.evaluated(), input)
+}
+
+func (r *quantifierRule[T]) Name() string {
+	return r.name
+}
+
+// Children returns the child rules (for documentation purposes).
+func (r *quantifierRule[T]) Children() []Rule[T] {
+	return r.rules
+}
+
+// Threshold returns the number of satisfied children the rule compares
+// against (0 for NoneOf).
+func (r *quantifierRule[T]) Threshold() int {
+	return r.n
+}
+
+// Kind reports which quantifier the rule implements.
+func (r *quantifierRule[T]) Kind() RuleType {
+	return r.kind
+}
+
+// Combine counts the satisfied children that are in effect. The outcome is
+// decided once it is settled or every child has been evaluated.
+func (r *quantifierRule[T]) Combine(results []Result, total int) (bool, bool, error) {
+	if r.kind == RuleTypeNoneOf && total == 0 {
+		return false, true, ErrEmptyRules
+	}
+
+	satisfied := 0
+	for _, result := range results {
+		if result.Satisfied && !result.Inactive {
+			satisfied++
+		}
+	}
+
+	return r.outcome(satisfied), r.settled(satisfied) || len(results) == total, nil
+}
+
+// settled reports whether the outcome can no longer change once count
+// children have been satisfied, so the remaining children can be skipped.
+func (r *quantifierRule[T]) settled(count int) bool {
+	switch r.kind {
+	case RuleTypeAtLeast:
+		return count >= r.n
+	case RuleTypeAtMost:
+		return count > r.n
+	case RuleTypeNoneOf:
+		return count > 0
+	default:
+		// Exactly needs to see every child
+		return false
+	}
+}
+
+// outcome returns whether the rule is satisfied when count children are
+// satisfied.
+func (r *quantifierRule[T]) outcome(count int) bool {
+	return quantifierOutcome(r.kind, r.n, count)
+}
+
+// quantifierOutcome returns whether a quantifier of the given kind and
+// threshold is satisfied when count children are satisfied.
+func quantifierOutcome(kind RuleType, threshold, count int) bool {
+	switch kind {
+	case RuleTypeAtLeast:
+		return count >= threshold
+	case RuleTypeExactly:
+		return count == threshold
+	case RuleTypeAtMost:
+		return count <= threshold
+	default:
+		return count == 0
+	}
+}
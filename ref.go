@@ -0,0 +1,212 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnresolvedReference is returned when a referenced rule is not
+	// registered.
+	ErrUnresolvedReference = errors.New("unresolved rule reference")
+	// ErrCircularReference is returned when a referenced rule refers back to
+	// itself.
+	ErrCircularReference = errors.New("circular rule reference")
+	// ErrReferenceType is returned when a referenced rule has a different
+	// input type than the reference.
+	ErrReferenceType = errors.New("referenced rule has a different input type")
+	// ErrDuplicateRefName is returned when a ref name is already taken by
+	// another rule.
+	ErrDuplicateRefName = errors.New("duplicate ref name")
+)
+
+// refRule is a rule that resolves lazily to the rule registered under a ref
+// name.
+type refRule[T any] struct {
+	refName  string
+	registry Registry
+
+	mu       sync.Mutex
+	resolved Rule[T]
+}
+
+// Ref creates a rule that refers to the rule registered under refName in
+// the default registry (see WithRefName). The reference is resolved when it
+// is first evaluated, so the referenced rule may be constructed and
+// registered later, e.g. in another package's init function.
+//
+// Resolution fails with ErrUnresolvedReference if no rule is registered
+// under the name, with ErrReferenceType if the rule has a different input
+// type, and with ErrCircularReference if the rule refers back to itself.
+// Failed resolutions are retried on the next evaluation.
+//
+// Example:
+//
+//	// Declare the dependency before the rule exists
+//	var eligible = rules.And("eligible",
+//	    rules.Ref[Customer]("customer-verified"),
+//	    hasOrderHistory,
+//	)
+//
+//	// Elsewhere
+//	verified := rules.New("customer is verified", isVerified)
+//	_ = rules.Register(verified, rules.WithRefName("customer-verified"))
+func Ref[T any](refName string) Rule[T] {
+	return RefIn[T](DefaultRegistry, refName)
+}
+
+// RefIn is like Ref but resolves the reference in the given registry, which
+// must implement Lookuper.
+func RefIn[T any](registry Registry, refName string) Rule[T] {
+	return &refRule[T]{
+		refName:  refName,
+		registry: registry,
+	}
+}
+
+func (r *refRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *refRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	target, err := r.target()
+	if err != nil {
+		return false, err
+	}
+
+	satisfied, err := EvaluateContext(ctx, target, input)
+	if err != nil {
+		return false, fmt.Errorf(
+			"evaluating reference %q: %w",
+			r.refName,
+			err,
+		)
+	}
+
+	return satisfied, nil
+}
+
+// Name returns the ref name of the referenced rule.
+func (r *refRule[T]) Name() string {
+	return r.refName
+}
+
+// RefName returns the ref name of the referenced rule.
+func (r *refRule[T]) RefName() string {
+	return r.refName
+}
+
+// Kind reports that the rule is a reference.
+func (r *refRule[T]) Kind() RuleType {
+	return RuleTypeReference
+}
+
+// target returns the referenced rule, resolving it on first use.
+func (r *refRule[T]) target() (Rule[T], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.resolved != nil {
+		return r.resolved, nil
+	}
+
+	target, err := r.lookup()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReferenceCycles(target, []string{r.refName}); err != nil {
+		return nil, fmt.Errorf("resolving reference %q: %w", r.refName, err)
+	}
+
+	r.resolved = target.(Rule[T])
+	return r.resolved, nil
+}
+
+// lookup finds the referenced rule in the registry without caching it.
+func (r *refRule[T]) lookup() (any, error) {
+	registered, ok := lookup(r.registry, r.refName)
+	if !ok {
+		return nil, fmt.Errorf("resolving reference %q: %w", r.refName, ErrUnresolvedReference)
+	}
+
+	target, ok := registered.Rule.(Rule[T])
+	if !ok {
+		return nil, fmt.Errorf(
+			"resolving reference %q: %w: %T",
+			r.refName,
+			ErrReferenceType,
+			registered.Rule,
+		)
+	}
+
+	return target, nil
+}
+
+// reference is implemented by rules that refer to a registered rule.
+type reference interface {
+	RefName() string
+	lookup() (any, error)
+}
+
+// resolveReference returns the rule a reference refers to, or nil if it
+// cannot be resolved.
+func resolveReference(rule any) any {
+	ref, ok := rule.(reference)
+	if !ok {
+		return nil
+	}
+
+	target, err := ref.lookup()
+	if err != nil {
+		return nil
+	}
+
+	return target
+}
+
+// checkReferenceCycles walks a rule tree, following references, and reports
+// a reference whose name is already on path.
+func checkReferenceCycles(rule any, path []string) error {
+	rule = unwrapRule(rule)
+
+	if ref, ok := rule.(reference); ok {
+		name := ref.RefName()
+		for _, seen := range path {
+			if seen == name {
+				return fmt.Errorf(
+					"%w: %s",
+					ErrCircularReference,
+					strings.Join(append(path, name), " -> "),
+				)
+			}
+		}
+
+		target, err := ref.lookup()
+		if err != nil {
+			return err
+		}
+
+		return checkReferenceCycles(target, append(path, name))
+	}
+
+	for _, child := range getChildren(rule) {
+		if child == nil {
+			continue
+		}
+		if err := checkReferenceCycles(child, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// referenceAnchor returns the documentation anchor of the rule registered
+// under a ref name.
+func referenceAnchor(refName string) string {
+	return "ref-" + strings.ToLower(strings.ReplaceAll(refName, " ", "-"))
+}
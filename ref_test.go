@@ -0,0 +1,182 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRef(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves lazily", func(t *testing.T) {
+		t.Parallel()
+
+		registry := NewRegistry()
+		rule := And("eligible", RefIn[testInput](registry, "positive"), Always[testInput]("always"))
+
+		// The referenced rule is registered after the reference is created
+		positive := New("value is positive", func(input testInput) (bool, error) {
+			return input.value > 0, nil
+		})
+		if err := registry.Register(positive, WithRefName("positive")); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+
+		got, err := rule.Evaluate(testInput{value: 1})
+		if err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+		got, err = rule.Evaluate(testInput{value: -1})
+		if err != nil || got {
+			t.Errorf("Evaluate() = %v, %v; want false, nil", got, err)
+		}
+	})
+
+	t.Run("missing reference", func(t *testing.T) {
+		t.Parallel()
+
+		registry := NewRegistry()
+		ref := RefIn[testInput](registry, "missing")
+
+		_, err := ref.Evaluate(testInput{})
+		if !errors.Is(err, ErrUnresolvedReference) {
+			t.Fatalf("Expected ErrUnresolvedReference, got %v", err)
+		}
+		if !strings.Contains(err.Error(), `"missing"`) {
+			t.Errorf("Expected error to name the reference, got %v", err)
+		}
+
+		// Failed resolutions are retried
+		_ = registry.Register(Always[testInput]("always"), WithRefName("missing"))
+		if got, err := ref.Evaluate(testInput{}); err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+	})
+
+	t.Run("registry without lookups", func(t *testing.T) {
+		t.Parallel()
+
+		// Embedding the interface hides the Lookup method
+		registry := struct{ Registry }{NewRegistry()}
+		_ = registry.Register(Always[testInput]("always"), WithRefName("always"))
+
+		_, err := RefIn[testInput](registry, "always").Evaluate(testInput{})
+		if !errors.Is(err, ErrUnresolvedReference) {
+			t.Errorf("Expected ErrUnresolvedReference, got %v", err)
+		}
+	})
+
+	t.Run("different input type", func(t *testing.T) {
+		t.Parallel()
+
+		registry := NewRegistry()
+		_ = registry.Register(Always[int]("always"), WithRefName("int rule"))
+
+		_, err := RefIn[testInput](registry, "int rule").Evaluate(testInput{})
+		if !errors.Is(err, ErrReferenceType) {
+			t.Errorf("Expected ErrReferenceType, got %v", err)
+		}
+	})
+
+	t.Run("circular reference", func(t *testing.T) {
+		t.Parallel()
+
+		registry := NewRegistry()
+		a := And("a", Always[testInput]("always"), RefIn[testInput](registry, "b"))
+		b := Or("b", Never[testInput]("never"), Not("not a", RefIn[testInput](registry, "a")))
+		_ = registry.Register(a, WithRefName("a"))
+		_ = registry.Register(b, WithRefName("b"))
+
+		_, err := RefIn[testInput](registry, "a").Evaluate(testInput{})
+		if !errors.Is(err, ErrCircularReference) {
+			t.Fatalf("Expected ErrCircularReference, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "a -> b -> a") {
+			t.Errorf("Expected error to show the cycle, got %v", err)
+		}
+	})
+
+	t.Run("transparent in detailed evaluation", func(t *testing.T) {
+		t.Parallel()
+
+		registry := NewRegistry()
+		target := And("target", Always[testInput]("always"), Never[testInput]("never"))
+		_ = registry.Register(target, WithRefName("target"))
+
+		result := NewEvaluator(Or("root", RefIn[testInput](registry, "target"))).EvaluateDetailed(testInput{})
+		if result.Satisfied {
+			t.Fatal("Expected rule to not be satisfied")
+		}
+		child := result.Children[0]
+		if child.RuleName != "target" || len(child.Children) != 2 {
+			t.Errorf("Expected the referenced tree, got %v", child)
+		}
+
+		missing := NewEvaluator(RefIn[testInput](registry, "missing")).EvaluateDetailed(testInput{})
+		if !errors.Is(missing.Error, ErrUnresolvedReference) {
+			t.Errorf("Expected ErrUnresolvedReference, got %v", missing.Error)
+		}
+	})
+}
+
+func TestRefDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	verified := NewWithDomain("customer is verified", TestUserDomain, func(u TestUser) (bool, error) {
+		return u.Status == "verified", nil
+	})
+	if err := Register(verified, WithRefName("customer-verified")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	active := NewWithDomain("customer is active", TestUserDomain, func(u TestUser) (bool, error) {
+		return true, nil
+	})
+	eligible := And("eligible customer", Ref[TestUser]("customer-verified"), active)
+	_ = Register(eligible, WithDomain(TestUserDomain))
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		`<a id="ref-customer-verified"></a>`,
+		"#### [customer-verified](#ref-customer-verified) (REF)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		`<div class="rule-card" id="ref-customer-verified">`,
+		`<a href="#ref-customer-verified">customer-verified</a>`,
+	} {
+		if !strings.Contains(htmlDoc, want) {
+			t.Errorf("HTML should contain %q", want)
+		}
+	}
+
+	jsonDoc, err := GenerateJSON(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonDoc, `"ref": "customer-verified"`) ||
+		!strings.Contains(jsonDoc, `"refName": "customer-verified"`) {
+		t.Errorf("JSON should contain the reference and the ref name:\n%s", jsonDoc)
+	}
+
+	mermaid, err := GenerateMermaid(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMermaid() error = %v", err)
+	}
+	edge := getMermaidNodeID(eligible) + " --> " + getMermaidNodeID(verified)
+	if !strings.Contains(mermaid, edge) {
+		t.Errorf("Mermaid should link the reference to its target (%q):\n%s", edge, mermaid)
+	}
+}
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
 	"time"
@@ -25,6 +26,12 @@ type RegisteredRule struct {
 	// Group is the human-readable group name (optional)
 	Group string
 
+	// RefName is the stable name the rule can be referenced by (optional).
+	// Unlike the rule's display name it must be unique within a registry.
+	//
+	// Set via: WithRefName(); referenced via: Ref()
+	RefName string
+
 	// Description is the technical description of what the rule does.
 	// This is the single source of truth for rule descriptions.
 	// Intended for developers and technical documentation.
@@ -125,11 +132,51 @@ type Registry interface {
 	Clear()
 }
 
+// Lookuper is implemented by registries that can look up rules by ref name
+// (see WithRefName). References (see RefIn) and definitions (see LoadIn)
+// resolve rules only in registries that implement it, such as the ones
+// created by NewRegistry.
+type Lookuper interface {
+	// Lookup returns the rule registered under a ref name
+	Lookup(refName string) (RegisteredRule, bool)
+}
+
+// lookup returns the rule registered under a ref name in the registry, or
+// false if the registry does not implement Lookuper.
+func lookup(registry Registry, refName string) (RegisteredRule, bool) {
+	lookuper, ok := registry.(Lookuper)
+	if !ok {
+		return RegisteredRule{}, false
+	}
+	return lookuper.Lookup(refName)
+}
+
+// Unregisterer is implemented by registries that can remove rules, such as
+// the ones created by NewRegistry. Reloadable rules (see NewReloadable)
+// remove the registrations of replaced versions only from registries that
+// implement it.
+type Unregisterer interface {
+	// Unregister removes a rule and frees its ref name. It reports whether
+	// the rule was registered.
+	Unregister(rule any) bool
+}
+
+// unregister removes a rule from the registry. It reports false if the rule
+// was not registered or the registry does not implement Unregisterer.
+func unregister(registry Registry, rule any) bool {
+	unregisterer, ok := registry.(Unregisterer)
+	if !ok {
+		return false
+	}
+	return unregisterer.Unregister(rule)
+}
+
 // registrationConfig holds configuration for rule registration.
 type registrationConfig struct {
 	domains     []Domain
 	group       string
 	description string
+	refName     string
 	metadata    *RuleMetadata
 }
 
@@ -166,6 +213,15 @@ func WithRegistrationMetadata(metadata RuleMetadata) RegistrationOption {
 	}
 }
 
+// WithRefName registers the rule under a stable name that other rules can
+// reference before it is constructed (see Ref). Registering a different rule
+// under a name that is already taken fails with ErrDuplicateRefName.
+func WithRefName(name string) RegistrationOption {
+	return func(c *registrationConfig) {
+		c.refName = name
+	}
+}
+
 // WithRegistrationDescription sets the description during registration.
 func WithRegistrationDescription(description string) RegistrationOption {
 	return func(c *registrationConfig) {
@@ -177,12 +233,14 @@ func WithRegistrationDescription(description string) RegistrationOption {
 type defaultRegistry struct {
 	mu    sync.RWMutex
 	rules map[uintptr]*RegisteredRule // pointer address as key
+	names map[string]uintptr          // ref name to pointer address
 }
 
 // NewRegistry creates a new registry instance.
 func NewRegistry() Registry {
 	return &defaultRegistry{
 		rules: make(map[uintptr]*RegisteredRule),
+		names: make(map[string]uintptr),
 	}
 }
 
@@ -202,6 +260,14 @@ func (r *defaultRegistry) Register(rule any, opts ...RegistrationOption) error {
 	// Get pointer address for lookup
 	ptr := getRulePointer(rule)
 
+	// Ref names must be unique
+	if config.refName != "" {
+		if owner, ok := r.names[config.refName]; ok && owner != ptr {
+			return fmt.Errorf("registering rule %q: %w", config.refName, ErrDuplicateRefName)
+		}
+		r.names[config.refName] = ptr
+	}
+
 	// Check if already registered
 	if existing, ok := r.rules[ptr]; ok {
 		// Update existing registration
@@ -217,6 +283,12 @@ func (r *defaultRegistry) Register(rule any, opts ...RegistrationOption) error {
 		if config.metadata != nil {
 			existing.Metadata = config.metadata
 		}
+		if config.refName != "" {
+			if existing.RefName != "" && existing.RefName != config.refName {
+				delete(r.names, existing.RefName)
+			}
+			existing.RefName = config.refName
+		}
 		return nil
 	}
 
@@ -225,6 +297,7 @@ func (r *defaultRegistry) Register(rule any, opts ...RegistrationOption) error {
 		Rule:         rule,
 		Domains:      domains,
 		Group:        config.group,
+		RefName:      config.refName,
 		Description:  config.description,
 		Metadata:     config.metadata,
 		RegisteredAt: time.Now(),
@@ -378,12 +451,49 @@ func (r *defaultRegistry) GetDescription(rule any) string {
 	return ""
 }
 
+// Lookup returns the rule registered under a ref name.
+func (r *defaultRegistry) Lookup(refName string) (RegisteredRule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ptr, ok := r.names[refName]
+	if !ok {
+		return RegisteredRule{}, false
+	}
+
+	registered, ok := r.rules[ptr]
+	if !ok {
+		return RegisteredRule{}, false
+	}
+
+	return *registered, true
+}
+
+// Unregister removes a rule from the registry.
+func (r *defaultRegistry) Unregister(rule any) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ptr := getRulePointer(rule)
+	registered, ok := r.rules[ptr]
+	if !ok {
+		return false
+	}
+
+	if registered.RefName != "" && r.names[registered.RefName] == ptr {
+		delete(r.names, registered.RefName)
+	}
+	delete(r.rules, ptr)
+	return true
+}
+
 // Clear removes all registered rules.
 func (r *defaultRegistry) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	r.rules = make(map[uintptr]*RegisteredRule)
+	r.names = make(map[string]uintptr)
 }
 
 // DefaultRegistry is the global registry instance.
@@ -429,6 +539,16 @@ func GetDescription(rule any) string {
 	return DefaultRegistry.GetDescription(rule)
 }
 
+// Lookup returns the rule registered under a ref name in the default registry.
+func Lookup(refName string) (RegisteredRule, bool) {
+	return lookup(DefaultRegistry, refName)
+}
+
+// Unregister removes a rule from the default registry.
+func Unregister(rule any) bool {
+	return unregister(DefaultRegistry, rule)
+}
+
 // Helper functions
 
 // getRulePointer gets the pointer address of a rule for lookup.
@@ -443,6 +563,34 @@ func getRulePointer(rule any) uintptr {
 	return v.Pointer()
 }
 
+// lookupRegisteredRule finds the registration of a rule in the default
+// registry. When the rule itself is not registered, the rules it wraps
+// (see WithViolation) are tried in turn.
+func lookupRegisteredRule(rule any) *RegisteredRule {
+	return lookupRegisteredRuleIn(DefaultRegistry, rule)
+}
+
+// lookupRegisteredRuleIn is like lookupRegisteredRule but looks in the
+// given registry.
+func lookupRegisteredRuleIn(registry Registry, rule any) *RegisteredRule {
+	allRules := registry.AllRules()
+
+	for {
+		ptr := getRulePointer(rule)
+		for i := range allRules {
+			if getRulePointer(allRules[i].Rule) == ptr {
+				return &allRules[i]
+			}
+		}
+
+		wrapper, ok := rule.(wrappingRule)
+		if !ok || wrapper.unwrapRule() == nil {
+			return nil
+		}
+		rule = wrapper.unwrapRule()
+	}
+}
+
 // deduplicateDomains removes duplicate domains from a slice.
 func deduplicateDomains(domains []Domain) []Domain {
 	if len(domains) == 0 {
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -484,6 +485,87 @@ func TestRegistry_DuplicateRegistration(t *testing.T) {
 	}
 }
 
+func TestRegistry_Lookup(t *testing.T) {
+	registry := NewRegistry()
+	lookuper, ok := registry.(Lookuper)
+	if !ok {
+		t.Fatal("Expected NewRegistry() to implement Lookuper")
+	}
+
+	rule := New("test rule", func(o Order) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	other := New("other rule", func(o Order) (bool, error) {
+		return true, nil
+	})
+
+	if err := registry.Register(rule, WithRefName("min-amount")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	registered, ok := lookuper.Lookup("min-amount")
+	if !ok || registered.Rule != rule || registered.RefName != "min-amount" {
+		t.Errorf("Lookup() = %+v, %v; want the registered rule", registered, ok)
+	}
+
+	if _, ok := lookuper.Lookup("missing"); ok {
+		t.Error("Lookup() of an unknown name should fail")
+	}
+
+	// Re-registering the same rule under the same name is allowed
+	if err := registry.Register(rule, WithRefName("min-amount")); err != nil {
+		t.Errorf("Register() error = %v", err)
+	}
+
+	err := registry.Register(other, WithRefName("min-amount"))
+	if !errors.Is(err, ErrDuplicateRefName) {
+		t.Errorf("Register() error = %v, want %v", err, ErrDuplicateRefName)
+	}
+
+	registry.Clear()
+	if _, ok := lookuper.Lookup("min-amount"); ok {
+		t.Error("Lookup() should fail after Clear()")
+	}
+}
+
+func TestRegistry_Unregister(t *testing.T) {
+	registry := NewRegistry()
+	unregisterer, ok := registry.(Unregisterer)
+	if !ok {
+		t.Fatal("Expected NewRegistry() to implement Unregisterer")
+	}
+
+	rule := New("test rule", func(o Order) (bool, error) {
+		return true, nil
+	})
+	other := New("other rule", func(o Order) (bool, error) {
+		return true, nil
+	})
+
+	_ = registry.Register(rule, WithDomain(TestOrderDomain), WithRefName("min-amount"))
+	_ = registry.Register(other, WithDomain(TestOrderDomain))
+
+	if !unregisterer.Unregister(rule) {
+		t.Error("Unregister() = false, want true")
+	}
+	if unregisterer.Unregister(rule) {
+		t.Error("Unregister() of an unregistered rule = true, want false")
+	}
+
+	rules := registry.AllRules()
+	if len(rules) != 1 || rules[0].Rule != other {
+		t.Errorf("AllRules() after Unregister() = %v, want only the other rule", rules)
+	}
+	if _, ok := lookup(registry, "min-amount"); ok {
+		t.Error("Lookup() should fail after Unregister()")
+	}
+
+	// The ref name is free again
+	if err := registry.Register(other, WithRefName("min-amount")); err != nil {
+		t.Errorf("Register() error = %v", err)
+	}
+}
+
 func TestDeduplicateDomains(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,370 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefinitionSource provides the current content of a rule definition, e.g.
+// a file or a configuration service.
+type DefinitionSource func() (io.Reader, error)
+
+// FileSource reads the rule definition from a file.
+func FileSource(path string) DefinitionSource {
+	return func() (io.Reader, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(data), nil
+	}
+}
+
+// RuleVersion describes a version of a reloadable rule.
+type RuleVersion struct {
+	// Number counts the successful loads, starting at 1.
+	Number int
+	// Checksum is the SHA-256 checksum of the definition, in hex.
+	Checksum string
+	// Label is the version in the metadata of the definition, if any.
+	Label string
+	// LoadedAt is when the version was loaded.
+	LoadedAt time.Time
+}
+
+// ReloadEvent reports the outcome of a reload that found a changed
+// definition.
+type ReloadEvent struct {
+	// Version is the active version after the reload. On failure it is the
+	// previous version, which keeps being served.
+	Version RuleVersion
+	// Checksum is the checksum of the definition that was read.
+	Checksum string
+	// Err is the reason the definition was rejected, or nil.
+	Err error
+}
+
+// ReloadOption configures a Reloadable.
+type ReloadOption[T any] func(*reloadConfig[T])
+
+// reloadConfig holds the configuration of a Reloadable.
+type reloadConfig[T any] struct {
+	registry  Registry
+	decode    func(data []byte, v any) error
+	validate  func(Rule[T]) error
+	listeners []func(ReloadEvent)
+}
+
+// WithReloadRegistry resolves predicates in and registers the loaded rules
+// with the given registry instead of the default registry. The registry
+// should implement Lookuper and Unregisterer, as the ones created by
+// NewRegistry do.
+func WithReloadRegistry[T any](registry Registry) ReloadOption[T] {
+	return func(c *reloadConfig[T]) {
+		c.registry = registry
+	}
+}
+
+// WithDefinitionDecoder decodes definitions with the given function instead
+// of as JSON, e.g. yaml.Unmarshal for YAML files.
+func WithDefinitionDecoder[T any](decode func(data []byte, v any) error) ReloadOption[T] {
+	return func(c *reloadConfig[T]) {
+		c.decode = decode
+	}
+}
+
+// WithReloadValidation rejects loaded rules for which validate returns an
+// error, e.g. rules with findings from Analyze.
+func WithReloadValidation[T any](validate func(Rule[T]) error) ReloadOption[T] {
+	return func(c *reloadConfig[T]) {
+		c.validate = validate
+	}
+}
+
+// WithReloadListener calls listener after every reload that found a changed
+// definition, whether it was swapped in or rejected.
+func WithReloadListener[T any](listener func(ReloadEvent)) ReloadOption[T] {
+	return func(c *reloadConfig[T]) {
+		c.listeners = append(c.listeners, listener)
+	}
+}
+
+// reloadState is a loaded version of a reloadable rule.
+type reloadState[T any] struct {
+	rule          Rule[T]
+	version       RuleVersion
+	built         []any
+	registrations []definedRegistration
+}
+
+// builtRoot reports whether the root of the version is a composite built
+// from the definition rather than a predicate. Composites are built and
+// registered children first, so the root comes last.
+func (s *reloadState[T]) builtRoot() bool {
+	return len(s.built) > 0 && s.built[len(s.built)-1] == any(s.rule)
+}
+
+// Reloadable is a rule whose structure is loaded from a definition source
+// (see RuleDefinition) and can be reloaded without a restart.
+//
+// A new definition is swapped in atomically only when it decodes, loads
+// and passes validation; otherwise the previous version keeps being
+// served. Each evaluation uses a single version.
+//
+// The Reloadable itself takes the registration of the root definition
+// (domains, group, ref name, ...), so references to its ref name (see Ref)
+// always reach the active version. Registrations of nested definitions are
+// replaced on every reload.
+//
+// Example:
+//
+//	freeShipping, err := rules.NewReloadable[Order](rules.FileSource("free_shipping.json"))
+//	if err != nil {
+//	    return err
+//	}
+//	go freeShipping.Watch(ctx, 30*time.Second)
+type Reloadable[T any] struct {
+	source DefinitionSource
+	config reloadConfig[T]
+
+	mu     sync.Mutex // serializes reloads
+	active atomic.Pointer[reloadState[T]]
+}
+
+// NewReloadable loads the definition from the source and returns a
+// reloadable rule serving it. It fails if the initial definition cannot be
+// loaded.
+func NewReloadable[T any](source DefinitionSource, opts ...ReloadOption[T]) (*Reloadable[T], error) {
+	r := &Reloadable[T]{
+		source: source,
+		config: reloadConfig[T]{
+			registry: DefaultRegistry,
+			decode:   decodeJSONDefinition,
+		},
+	}
+	for _, opt := range opts {
+		opt(&r.config)
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Name returns the name of the active version.
+func (r *Reloadable[T]) Name() string {
+	return r.Current().Name()
+}
+
+// Evaluate evaluates the active version.
+func (r *Reloadable[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+// EvaluateContext is like Evaluate but honors the context.
+func (r *Reloadable[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	return EvaluateContext(ctx, r.Current(), input)
+}
+
+// Current returns the rule of the active version.
+func (r *Reloadable[T]) Current() Rule[T] {
+	return r.active.Load().rule
+}
+
+// Version returns the active version.
+func (r *Reloadable[T]) Version() RuleVersion {
+	return r.active.Load().version
+}
+
+// unwrapRule returns the active version for introspection.
+func (r *Reloadable[T]) unwrapRule() any {
+	return r.Current()
+}
+
+// Reload reads the source and swaps in its definition if it changed. If
+// the definition is rejected, the previous version stays active and the
+// error is returned.
+func (r *Reloadable[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reader, err := r.source()
+	if err != nil {
+		return r.reject("", fmt.Errorf("reading rule definition: %w", err))
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return r.reject("", fmt.Errorf("reading rule definition: %w", err))
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	prev := r.active.Load()
+	if prev != nil && prev.version.Checksum == checksum {
+		return nil
+	}
+
+	next, err := r.load(data, checksum)
+	if err != nil {
+		return r.reject(checksum, err)
+	}
+	if err := r.swap(prev, next); err != nil {
+		return r.reject(checksum, err)
+	}
+
+	r.notify(ReloadEvent{Version: next.version, Checksum: checksum})
+	return nil
+}
+
+// Watch reloads the definition every interval until the context is done,
+// and returns the context's error. Failed reloads are reported to the
+// listeners (see WithReloadListener).
+func (r *Reloadable[T]) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_ = r.Reload()
+		}
+	}
+}
+
+// load decodes, loads and validates a definition. The rules built for a
+// rejected definition are unregistered again.
+func (r *Reloadable[T]) load(data []byte, checksum string) (*reloadState[T], error) {
+	var def RuleDefinition
+	if err := r.config.decode(data, &def); err != nil {
+		return nil, fmt.Errorf("decoding rule definition: %w", err)
+	}
+
+	l := &loader[T]{registry: r.config.registry}
+	rule, err := l.load(def, []string{def.label("rule")}, true)
+	if err != nil {
+		unregisterBuilt(r.config.registry, l.built)
+		return nil, err
+	}
+	if r.config.validate != nil {
+		if err := r.config.validate(rule); err != nil {
+			unregisterBuilt(r.config.registry, l.built)
+			return nil, fmt.Errorf("validating rule definition %q: %w", rule.Name(), err)
+		}
+	}
+
+	next := &reloadState[T]{
+		rule:          rule,
+		built:         l.built,
+		registrations: l.registrations,
+		version: RuleVersion{
+			Number:   1,
+			Checksum: checksum,
+			LoadedAt: time.Now(),
+		},
+	}
+	if prev := r.active.Load(); prev != nil {
+		next.version.Number = prev.version.Number + 1
+	}
+	if def.Metadata != nil {
+		next.version.Label = def.Metadata.Version
+	}
+
+	// The Reloadable takes the registration of the root
+	if next.builtRoot() {
+		next.registrations[len(next.registrations)-1].rule = r
+	}
+	return next, nil
+}
+
+// swap replaces the registrations of the previous version with those of
+// the next one and activates it. If the next version cannot be registered,
+// its rules are unregistered and the registrations of the previous version
+// are restored.
+func (r *Reloadable[T]) swap(prev, next *reloadState[T]) error {
+	registry := r.config.registry
+
+	// Ref names must be free or owned by the previous version
+	owned := map[uintptr]bool{getRulePointer(r): true}
+	if prev != nil {
+		for _, registration := range prev.registrations {
+			owned[getRulePointer(registration.rule)] = true
+		}
+	}
+	// and not be taken twice by the next one
+	taken := make(map[string]bool)
+	for _, registration := range next.registrations {
+		config := &registrationConfig{}
+		for _, opt := range registration.opts {
+			opt(config)
+		}
+		if config.refName == "" {
+			continue
+		}
+		existing, ok := lookup(registry, config.refName)
+		if taken[config.refName] || ok && !owned[getRulePointer(existing.Rule)] {
+			unregisterBuilt(registry, next.built)
+			return definitionError(registration.path, "%w", fmt.Errorf("registering rule %q: %w", config.refName, ErrDuplicateRefName))
+		}
+		taken[config.refName] = true
+	}
+
+	// Free the ref names of the previous version. Its other registrations
+	// are kept until the next version is registered.
+	if prev != nil {
+		for _, registration := range prev.registrations {
+			unregister(registry, registration.rule)
+		}
+	}
+	if next.builtRoot() {
+		unregisterBuilt(registry, []any{next.rule})
+	}
+	unregister(registry, r)
+
+	for _, registration := range next.registrations {
+		if err := registry.Register(registration.rule, registration.opts...); err != nil {
+			// Restore the registrations of the previous version, which
+			// keeps being served
+			unregisterBuilt(registry, next.built)
+			if prev != nil {
+				for _, registration := range prev.registrations {
+					_ = registry.Register(registration.rule, registration.opts...)
+				}
+			}
+			return definitionError(registration.path, "%w", err)
+		}
+	}
+
+	if prev != nil {
+		unregisterBuilt(registry, prev.built)
+	}
+	r.active.Store(next)
+	return nil
+}
+
+// reject reports a rejected definition and returns the error.
+func (r *Reloadable[T]) reject(checksum string, err error) error {
+	event := ReloadEvent{Checksum: checksum, Err: err}
+	if active := r.active.Load(); active != nil {
+		event.Version = active.version
+	}
+	r.notify(event)
+	return err
+}
+
+// notify calls the listeners with an event.
+func (r *Reloadable[T]) notify(event ReloadEvent) {
+	for _, listener := range r.config.listeners {
+		listener(event)
+	}
+}
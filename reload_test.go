@@ -0,0 +1,409 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// definitionVar is a definition source backed by a variable.
+type definitionVar struct {
+	mu   sync.Mutex
+	data string
+}
+
+func (v *definitionVar) set(data string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.data = data
+}
+
+func (v *definitionVar) source() (io.Reader, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return strings.NewReader(v.data), nil
+}
+
+const (
+	// Free shipping for large or domestic orders
+	freeShippingV1 = `{
+		"name": "free shipping",
+		"type": "OR",
+		"domains": ["order"],
+		"refName": "order.free-shipping",
+		"metadata": {"version": "v1"},
+		"rules": [
+			{"predicate": "order.amount-over-100"},
+			{"predicate": "order.domestic"}
+		]
+	}`
+	// Free shipping for large domestic orders only
+	freeShippingV2 = `{
+		"name": "free shipping",
+		"type": "AND",
+		"domains": ["order"],
+		"refName": "order.free-shipping",
+		"metadata": {"version": "v2"},
+		"rules": [
+			{"predicate": "order.amount-over-100"},
+			{"predicate": "order.domestic"}
+		]
+	}`
+)
+
+func TestReloadable(t *testing.T) {
+	t.Parallel()
+
+	registry := newPredicateRegistry(t)
+	definition := &definitionVar{data: freeShippingV1}
+	var events []ReloadEvent
+
+	rule, err := NewReloadable(definition.source,
+		WithReloadRegistry[TestOrder](registry),
+		WithReloadListener[TestOrder](func(event ReloadEvent) { events = append(events, event) }),
+	)
+	if err != nil {
+		t.Fatalf("NewReloadable() error = %v", err)
+	}
+
+	ref := RefIn[TestOrder](registry, "order.free-shipping")
+	domestic := TestOrder{Amount: 50, Country: "SE"}
+
+	if got, err := ref.Evaluate(domestic); err != nil || !got {
+		t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+	}
+	if version := rule.Version(); version.Number != 1 || version.Label != "v1" || version.Checksum == "" {
+		t.Errorf("Unexpected version: %+v", version)
+	}
+
+	// The reloadable rule itself is registered under the root's ref name
+	registered, ok := lookup(registry, "order.free-shipping")
+	if !ok || registered.Rule != any(rule) || len(registered.Domains) != 1 {
+		t.Errorf("Unexpected registration: %+v", registered)
+	}
+
+	// Reloading an unchanged definition does nothing
+	if err := rule.Reload(); err != nil || rule.Version().Number != 1 || len(events) != 1 {
+		t.Errorf("Reload() = %v; version %d, %d events", err, rule.Version().Number, len(events))
+	}
+
+	definition.set(freeShippingV2)
+	if err := rule.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got, err := ref.Evaluate(domestic); err != nil || got {
+		t.Errorf("Evaluate() after reload = %v, %v; want false, nil", got, err)
+	}
+	if rule.Version().Number != 2 || rule.Version().Label != "v2" || getRuleType(unwrapRule(rule)) != RuleTypeAnd {
+		t.Errorf("Unexpected version after reload: %+v", rule.Version())
+	}
+	if len(events) != 2 || events[1].Err != nil || events[1].Version.Number != 2 {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+	if len(registry.RulesByDomain(TestOrderDomain)) != 1 {
+		t.Errorf("Expected only the reloadable rule in the domain, got %v", registry.RulesByDomain(TestOrderDomain))
+	}
+
+	// Detailed evaluation reports the active version's tree
+	result := NewEvaluator[TestOrder](rule).EvaluateDetailed(domestic)
+	if result.RuleName != "free shipping" || len(result.Children) != 2 {
+		t.Errorf("Unexpected detailed result: %v", result)
+	}
+}
+
+func TestReloadable_Rejected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		definition string
+		wantErr    error
+	}{
+		{
+			name:       "malformed JSON",
+			definition: `{"name": "free shipping",`,
+		},
+		{
+			name:       "unknown predicate",
+			definition: strings.Replace(freeShippingV2, "order.domestic", "order.unknown", 1),
+			wantErr:    ErrUnknownPredicate,
+		},
+		{
+			name: "duplicate ref names",
+			definition: `{
+				"name": "free shipping",
+				"type": "AND",
+				"refName": "order.free-shipping",
+				"rules": [
+					{"name": "a", "type": "OR", "refName": "dup", "rules": [{"predicate": "order.domestic"}]},
+					{"name": "b", "type": "OR", "refName": "dup", "rules": [{"predicate": "order.amount-over-100"}]}
+				]
+			}`,
+			wantErr: ErrDuplicateRefName,
+		},
+		{
+			name:       "failed validation",
+			definition: strings.Replace(freeShippingV2, `"AND"`, `"AT_LEAST"`, 1),
+			wantErr:    ErrInvalidDefinition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := newPredicateRegistry(t)
+			definition := &definitionVar{data: freeShippingV1}
+			var events []ReloadEvent
+
+			rule, err := NewReloadable(definition.source,
+				WithReloadRegistry[TestOrder](registry),
+				WithReloadListener[TestOrder](func(event ReloadEvent) { events = append(events, event) }),
+				WithReloadValidation(func(rule Rule[TestOrder]) error {
+					if findings := Analyze(rule); len(findings) > 0 {
+						return ErrInvalidDefinition
+					}
+					return nil
+				}),
+			)
+			if err != nil {
+				t.Fatalf("NewReloadable() error = %v", err)
+			}
+
+			definition.set(tt.definition)
+			err = rule.Reload()
+			if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reload() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// The previous version keeps being served
+			if rule.Version().Number != 1 || getRuleType(unwrapRule(rule)) != RuleTypeOr {
+				t.Errorf("Expected version 1 to stay active, got %+v", rule.Version())
+			}
+			if got, _ := rule.Evaluate(TestOrder{Country: "SE"}); !got {
+				t.Error("Expected the previous version to be evaluated")
+			}
+			if registered, ok := lookup(registry, "order.free-shipping"); !ok || registered.Rule != any(rule) {
+				t.Error("Expected the registration to be kept")
+			}
+			if _, ok := lookup(registry, "dup"); ok {
+				t.Error("Expected the rejected version not to be registered")
+			}
+			if len(events) != 2 || events[1].Err == nil || events[1].Version.Number != 1 {
+				t.Errorf("Unexpected events: %+v", events)
+			}
+		})
+	}
+
+	t.Run("initial definition", func(t *testing.T) {
+		t.Parallel()
+
+		source := func() (io.Reader, error) { return strings.NewReader(`{}`), nil }
+		if _, err := NewReloadable(source, WithReloadRegistry[TestOrder](newPredicateRegistry(t))); !errors.Is(err, ErrInvalidDefinition) {
+			t.Errorf("Expected ErrInvalidDefinition, got %v", err)
+		}
+	})
+}
+
+// failingRegistry fails to register the OR rule with the given name.
+type failingRegistry struct {
+	Registry
+	fail string
+}
+
+func (r *failingRegistry) Register(rule any, opts ...RegistrationOption) error {
+	if or, ok := rule.(*orRule[TestOrder]); ok && or.name == r.fail {
+		return errors.New("registration failed")
+	}
+	return r.Registry.Register(rule, opts...)
+}
+
+func (r *failingRegistry) Lookup(refName string) (RegisteredRule, bool) {
+	return lookup(r.Registry, refName)
+}
+
+func (r *failingRegistry) Unregister(rule any) bool {
+	return unregister(r.Registry, rule)
+}
+
+func TestReloadable_RegistrationRollback(t *testing.T) {
+	t.Parallel()
+
+	registry := &failingRegistry{Registry: newPredicateRegistry(t), fail: "b"}
+	definition := &definitionVar{data: freeShippingV1}
+	rule, err := NewReloadable(definition.source, WithReloadRegistry[TestOrder](registry))
+	if err != nil {
+		t.Fatalf("NewReloadable() error = %v", err)
+	}
+
+	definition.set(`{
+		"name": "free shipping",
+		"type": "AND",
+		"refName": "order.free-shipping",
+		"rules": [
+			{"name": "a", "type": "OR", "refName": "order.a", "rules": [{"predicate": "order.domestic"}]},
+			{"name": "b", "type": "OR", "refName": "order.b", "rules": [{"predicate": "order.amount-over-100"}]}
+		]
+	}`)
+	if err := rule.Reload(); err == nil {
+		t.Fatal("Reload() should fail")
+	}
+
+	if rule.Version().Number != 1 {
+		t.Errorf("Expected version 1 to stay active, got %+v", rule.Version())
+	}
+	if registered, ok := lookup(registry, "order.free-shipping"); !ok || registered.Rule != any(rule) {
+		t.Error("Expected the registration of the previous version to be restored")
+	}
+	if _, ok := lookup(registry, "order.a"); ok {
+		t.Error("Expected the rejected version not to be registered")
+	}
+}
+
+func TestReloadable_RejectedRegistrations(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	// Composites of predicates with domains register themselves when built
+	_ = Register(New("amount over 100", func(o TestOrder) (bool, error) {
+		return o.Amount > 100, nil
+	}), WithDomain(TestOrderDomain), WithRefName("order.amount-over-100"))
+	_ = Register(New("domestic", func(o TestOrder) (bool, error) {
+		return o.Country == "SE", nil
+	}), WithDomain(TestOrderDomain), WithRefName("order.domestic"))
+
+	registry := &failingRegistry{Registry: DefaultRegistry, fail: "b"}
+	definition := &definitionVar{data: `{
+		"name": "free shipping",
+		"type": "OR",
+		"refName": "order.free-shipping",
+		"rules": [
+			{"name": "large domestic", "type": "AND", "rules": [{"predicate": "order.amount-over-100"}, {"predicate": "order.domestic"}]},
+			{"predicate": "order.domestic"}
+		]
+	}`}
+	rule, err := NewReloadable(definition.source,
+		WithReloadRegistry[TestOrder](registry),
+		WithReloadValidation(func(rule Rule[TestOrder]) error {
+			if rule.Name() == "invalid" {
+				return ErrInvalidDefinition
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewReloadable() error = %v", err)
+	}
+	want := len(DefaultRegistry.AllRules())
+
+	tests := []struct {
+		name       string
+		definition string
+	}{
+		{
+			name: "failed validation",
+			definition: `{"name": "invalid", "type": "AND", "rules": [
+				{"name": "x", "type": "OR", "rules": [{"predicate": "order.domestic"}]}
+			]}`,
+		},
+		{
+			name: "unknown predicate",
+			definition: `{"name": "free shipping", "type": "AND", "rules": [
+				{"name": "x", "type": "OR", "rules": [{"predicate": "order.domestic"}]},
+				{"predicate": "order.unknown"}
+			]}`,
+		},
+		{
+			name: "duplicate ref names",
+			definition: `{"name": "free shipping", "type": "AND", "refName": "order.free-shipping", "rules": [
+				{"name": "a", "type": "OR", "refName": "dup", "rules": [{"predicate": "order.domestic"}]},
+				{"name": "b", "type": "OR", "refName": "dup", "rules": [{"predicate": "order.domestic"}]}
+			]}`,
+		},
+		{
+			name: "failed registration",
+			definition: `{"name": "free shipping", "type": "AND", "refName": "order.free-shipping", "rules": [
+				{"name": "a", "type": "OR", "refName": "order.a", "rules": [{"predicate": "order.domestic"}]},
+				{"name": "b", "type": "OR", "refName": "order.b", "rules": [{"predicate": "order.amount-over-100"}]}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition.set(tt.definition)
+			for range 5 {
+				if err := rule.Reload(); err == nil {
+					t.Fatal("Reload() should fail")
+				}
+			}
+
+			rules := DefaultRegistry.AllRules()
+			if len(rules) != want {
+				t.Errorf("Expected %d registered rules, got %d", want, len(rules))
+			}
+			found := false
+			for _, registered := range rules {
+				if named, ok := registered.Rule.(interface{ Name() string }); ok && named.Name() == "large domestic" {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("Expected the rules of the active version to stay registered")
+			}
+			if registered, ok := Lookup("order.free-shipping"); !ok || registered.Rule != any(rule) {
+				t.Error("Expected the registration to be kept")
+			}
+		})
+	}
+}
+
+func TestReloadable_Watch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "free_shipping.json")
+	if err := os.WriteFile(path, []byte(freeShippingV1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan ReloadEvent, 1)
+	rule, err := NewReloadable(FileSource(path),
+		WithReloadRegistry[TestOrder](newPredicateRegistry(t)),
+		WithReloadListener[TestOrder](func(event ReloadEvent) {
+			if event.Version.Number > 1 {
+				reloaded <- event
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewReloadable() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- rule.Watch(ctx, 5*time.Millisecond) }()
+
+	if err := os.WriteFile(path, []byte(freeShippingV2), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-reloaded:
+		if event.Err != nil || event.Version.Label != "v2" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the reload")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -3,8 +3,10 @@
 package rules
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -29,14 +31,31 @@ type Rule[T any] interface {
 	Name() string
 }
 
+// ContextRule is a Rule that can also be evaluated with a context.Context,
+// allowing predicates to honor cancellation and deadlines.
+// All composite rules in this package implement ContextRule and propagate
+// the context to their children.
+type ContextRule[T any] interface {
+	Rule[T]
+
+	// EvaluateContext checks if the rule is satisfied by the given input.
+	// It stops and returns the context's error as soon as the context is done.
+	EvaluateContext(ctx context.Context, input T) (bool, error)
+}
+
 // PredicateFunc is a function that evaluates a condition against an input.
 type PredicateFunc[T any] func(input T) (bool, error)
 
+// ContextPredicateFunc is a function that evaluates a condition against an
+// input and has access to the evaluation context.
+type ContextPredicateFunc[T any] func(ctx context.Context, input T) (bool, error)
+
 // simpleRule is a basic rule implementation that wraps a predicate function.
 type simpleRule[T any] struct {
-	name        string
-	description string
-	predicate   PredicateFunc[T]
+	name         string
+	description  string
+	predicate    PredicateFunc[T]
+	ctxPredicate ContextPredicateFunc[T]
 }
 
 // New creates a new simple rule with the given name and predicate function.
@@ -48,6 +67,31 @@ func New[T any](name string, predicate PredicateFunc[T]) Rule[T] {
 	}
 }
 
+// NewContext creates a new simple rule with the given name and a
+// context-aware predicate function.
+func NewContext[T any](name string, predicate ContextPredicateFunc[T]) ContextRule[T] {
+	return &simpleRule[T]{
+		name:         name,
+		description:  name,
+		ctxPredicate: predicate,
+	}
+}
+
+// EvaluateContext evaluates any rule with the given context. Rules that
+// implement ContextRule receive the context; other rules are evaluated with
+// Evaluate. In both cases the context is checked before evaluation starts.
+func EvaluateContext[T any](ctx context.Context, rule Rule[T], input T) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if cr, ok := rule.(ContextRule[T]); ok {
+		return cr.EvaluateContext(ctx, input)
+	}
+
+	return rule.Evaluate(input)
+}
+
 // NewWithDescription creates a new simple rule with a name, description,
 // and predicate function.
 func NewWithDescription[T any](
@@ -92,7 +136,40 @@ func WithDescription[T any](rule Rule[T], description string) Rule[T] {
 }
 
 func (r *simpleRule[T]) Evaluate(input T) (bool, error) {
-	result, err := r.predicate(input)
+	var result bool
+	var err error
+	if r.ctxPredicate != nil {
+		result, err = r.ctxPredicate(context.Background(), input)
+	} else {
+		result, err = r.predicate(input)
+	}
+	if err != nil {
+		return false, fmt.Errorf(
+			"evaluating rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	return result, nil
+}
+
+func (r *simpleRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, fmt.Errorf(
+			"evaluating rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	var result bool
+	var err error
+	if r.ctxPredicate != nil {
+		result, err = r.ctxPredicate(ctx, input)
+	} else {
+		result, err = r.predicate(input)
+	}
 	if err != nil {
 		return false, fmt.Errorf(
 			"evaluating rule %q: %w",
@@ -110,17 +187,26 @@ func (r *simpleRule[T]) Name() string {
 
 // andRule represents a logical AND of multiple rules.
 type andRule[T any] struct {
-	name  string
-	rules []Rule[T]
+	name     string
+	rules    []Rule[T]
+	children []ruleChild[T]
+}
+
+// newAndRule creates a logical AND of the rules without registering it.
+func newAndRule[T any](name string, rules []Rule[T]) *andRule[T] {
+	return &andRule[T]{
+		name:     name,
+		rules:    rules,
+		children: newRuleChildren(rules),
+	}
 }
 
 // And creates a rule that is satisfied only if all provided rules are
-// satisfied. Automatically inherits domains from child rules.
+// satisfied. Failed children wrapped with a severity below SeverityError
+// (see WithSeverity) do not block. Automatically inherits domains from child
+// rules.
 func And[T any](name string, rules ...Rule[T]) Rule[T] {
-	rule := &andRule[T]{
-		name:  name,
-		rules: rules,
-	}
+	rule := newAndRule(name, rules)
 
 	// Collect and deduplicate domains from children
 	domains := collectDomainsFromRules(rules)
@@ -132,7 +218,7 @@ func And[T any](name string, rules ...Rule[T]) Rule[T] {
 }
 
 func (r *andRule[T]) Evaluate(input T) (bool, error) {
-	if len(r.rules) == 0 {
+	if len(r.children) == 0 {
 		return false, fmt.Errorf(
 			"evaluating AND rule %q: %w",
 			r.name,
@@ -140,16 +226,58 @@ func (r *andRule[T]) Evaluate(input T) (bool, error) {
 		)
 	}
 
-	for _, rule := range r.rules {
-		if rule == nil {
+	for _, child := range r.children {
+		if child.rule == nil {
+			return false, fmt.Errorf(
+				"evaluating AND rule %q: %w",
+				r.name,
+				ErrNilRule,
+			)
+		}
+		if !child.inEffect(context.Background()) {
+			continue
+		}
+
+		satisfied, err := child.rule.Evaluate(input)
+		if err != nil {
+			return false, fmt.Errorf(
+				"evaluating AND rule %q: %w",
+				r.name,
+				err,
+			)
+		}
+
+		// Failed children below error severity do not block
+		if !satisfied && child.blocking() {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *andRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if len(r.children) == 0 {
+		return false, fmt.Errorf(
+			"evaluating AND rule %q: %w",
+			r.name,
+			ErrEmptyRules,
+		)
+	}
+
+	for _, child := range r.children {
+		if child.rule == nil {
 			return false, fmt.Errorf(
 				"evaluating AND rule %q: %w",
 				r.name,
 				ErrNilRule,
 			)
 		}
+		if !child.inEffect(ctx) {
+			continue
+		}
 
-		satisfied, err := rule.Evaluate(input)
+		satisfied, err := child.evaluateContext(ctx, input)
 		if err != nil {
 			return false, fmt.Errorf(
 				"evaluating AND rule %q: %w",
@@ -158,7 +286,8 @@ func (r *andRule[T]) Evaluate(input T) (bool, error) {
 			)
 		}
 
-		if !satisfied {
+		// Failed children below error severity do not block
+		if !satisfied && child.blocking() {
 			return false, nil
 		}
 	}
@@ -175,19 +304,36 @@ func (r *andRule[T]) Children() []Rule[T] {
 	return r.rules
 }
 
+// Kind reports that the rule is a logical AND.
+func (r *andRule[T]) Kind() RuleType {
+	return RuleTypeAnd
+}
+
+// Combine is satisfied unless a blocking child is not satisfied.
+func (r *andRule[T]) Combine(results []Result, total int) (bool, bool, error) {
+	return combineAnd(results, total)
+}
+
 // orRule represents a logical OR of multiple rules.
 type orRule[T any] struct {
-	name  string
-	rules []Rule[T]
+	name     string
+	rules    []Rule[T]
+	children []ruleChild[T]
+}
+
+// newOrRule creates a logical OR of the rules without registering it.
+func newOrRule[T any](name string, rules []Rule[T]) *orRule[T] {
+	return &orRule[T]{
+		name:     name,
+		rules:    rules,
+		children: newRuleChildren(rules),
+	}
 }
 
 // Or creates a rule that is satisfied if at least one of the provided rules
 // is satisfied. Automatically inherits domains from child rules.
 func Or[T any](name string, rules ...Rule[T]) Rule[T] {
-	rule := &orRule[T]{
-		name:  name,
-		rules: rules,
-	}
+	rule := newOrRule(name, rules)
 
 	// Collect and deduplicate domains from children
 	domains := collectDomainsFromRules(rules)
@@ -199,7 +345,7 @@ func Or[T any](name string, rules ...Rule[T]) Rule[T] {
 }
 
 func (r *orRule[T]) Evaluate(input T) (bool, error) {
-	if len(r.rules) == 0 {
+	if len(r.children) == 0 {
 		return false, fmt.Errorf(
 			"evaluating OR rule %q: %w",
 			r.name,
@@ -207,16 +353,57 @@ func (r *orRule[T]) Evaluate(input T) (bool, error) {
 		)
 	}
 
-	for _, rule := range r.rules {
-		if rule == nil {
+	for _, child := range r.children {
+		if child.rule == nil {
 			return false, fmt.Errorf(
 				"evaluating OR rule %q: %w",
 				r.name,
 				ErrNilRule,
 			)
 		}
+		if !child.inEffect(context.Background()) {
+			continue
+		}
 
-		satisfied, err := rule.Evaluate(input)
+		satisfied, err := child.rule.Evaluate(input)
+		if err != nil {
+			return false, fmt.Errorf(
+				"evaluating OR rule %q: %w",
+				r.name,
+				err,
+			)
+		}
+
+		if satisfied {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *orRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if len(r.children) == 0 {
+		return false, fmt.Errorf(
+			"evaluating OR rule %q: %w",
+			r.name,
+			ErrEmptyRules,
+		)
+	}
+
+	for _, child := range r.children {
+		if child.rule == nil {
+			return false, fmt.Errorf(
+				"evaluating OR rule %q: %w",
+				r.name,
+				ErrNilRule,
+			)
+		}
+		if !child.inEffect(ctx) {
+			continue
+		}
+
+		satisfied, err := child.evaluateContext(ctx, input)
 		if err != nil {
 			return false, fmt.Errorf(
 				"evaluating OR rule %q: %w",
@@ -242,19 +429,37 @@ func (r *orRule[T]) Children() []Rule[T] {
 	return r.rules
 }
 
+// Kind reports that the rule is a logical OR.
+func (r *orRule[T]) Kind() RuleType {
+	return RuleTypeOr
+}
+
+// Combine is satisfied as soon as a child is satisfied.
+func (r *orRule[T]) Combine(results []Result, total int) (bool, bool, error) {
+	return combineOr(results, total)
+}
+
 // notRule represents a logical NOT of a rule.
 type notRule[T any] struct {
-	name string
-	rule Rule[T]
+	name  string
+	rule  Rule[T]
+	child ruleChild[T]
+}
+
+// newNotRule creates a logical NOT of the rule without registering it.
+func newNotRule[T any](name string, rule Rule[T]) *notRule[T] {
+	return &notRule[T]{
+		name:  name,
+		rule:  rule,
+		child: newRuleChild(rule),
+	}
 }
 
 // Not creates a rule that is satisfied only if the provided rule is not
-// satisfied. Automatically inherits domains from the child rule.
+// satisfied. A rule that is not in effect (see Effective) is ignored, so
+// the NOT is satisfied. Automatically inherits domains from the child rule.
 func Not[T any](name string, rule Rule[T]) Rule[T] {
-	notRule := &notRule[T]{
-		name: name,
-		rule: rule,
-	}
+	notRule := newNotRule(name, rule)
 
 	// Collect domains from child
 	domains := collectDomainsFromRules([]Rule[T]{rule})
@@ -274,6 +479,11 @@ func (r *notRule[T]) Evaluate(input T) (bool, error) {
 		)
 	}
 
+	// A child that is not in effect is ignored
+	if !r.child.inEffect(context.Background()) {
+		return true, nil
+	}
+
 	satisfied, err := r.rule.Evaluate(input)
 	if err != nil {
 		return false, fmt.Errorf(
@@ -286,6 +496,32 @@ func (r *notRule[T]) Evaluate(input T) (bool, error) {
 	return !satisfied, nil
 }
 
+func (r *notRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if r.rule == nil {
+		return false, fmt.Errorf(
+			"evaluating NOT rule %q: %w",
+			r.name,
+			ErrNilRule,
+		)
+	}
+
+	// A child that is not in effect is ignored
+	if !r.child.inEffect(ctx) {
+		return true, nil
+	}
+
+	satisfied, err := r.child.evaluateContext(ctx, input)
+	if err != nil {
+		return false, fmt.Errorf(
+			"evaluating NOT rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	return !satisfied, nil
+}
+
 func (r *notRule[T]) Name() string {
 	return r.name
 }
@@ -295,8 +531,135 @@ func (r *notRule[T]) Child() Rule[T] {
 	return r.rule
 }
 
+// Children returns the child rule as a single-element slice.
+func (r *notRule[T]) Children() []Rule[T] {
+	return []Rule[T]{r.rule}
+}
+
+// Kind reports that the rule is a logical NOT.
+func (r *notRule[T]) Kind() RuleType {
+	return RuleTypeNot
+}
+
+// Combine negates the outcome of the child. A child that is not in effect
+// is ignored, so the NOT is satisfied.
+func (r *notRule[T]) Combine(results []Result, _ int) (bool, bool, error) {
+	if len(results) == 0 {
+		return false, false, nil
+	}
+	if results[0].Inactive {
+		return true, true, nil
+	}
+	return !results[0].Satisfied, true, nil
+}
+
+// ruleChild is a child of a logical rule with what its evaluation needs to
+// know about it resolved once, when the rule is built.
+type ruleChild[T any] struct {
+	rule    Rule[T]
+	ctxRule ContextRule[T]
+	// decorated reports whether the child may be effective-dated or carry
+	// a severity, which is then checked on every evaluation
+	decorated bool
+}
+
+// newRuleChild resolves a child of a logical rule.
+func newRuleChild[T any](rule Rule[T]) ruleChild[T] {
+	child := ruleChild[T]{rule: rule}
+	if rule == nil {
+		return child
+	}
+	child.ctxRule, _ = rule.(ContextRule[T])
+	child.decorated = isDecorated(rule)
+	return child
+}
+
+// newRuleChildren resolves the children of a logical rule.
+func newRuleChildren[T any](rules []Rule[T]) []ruleChild[T] {
+	children := make([]ruleChild[T], len(rules))
+	for i, rule := range rules {
+		children[i] = newRuleChild(rule)
+	}
+	return children
+}
+
+// isDecorated reports whether a rule may have a validity window or a
+// severity (see Effective and WithSeverity), either itself or through the
+// rules it wraps. Wrapped rules may change, e.g. on reloads, so wrappers
+// are always decorated.
+func isDecorated(rule any) bool {
+	switch rule.(type) {
+	case wrappingRule,
+		interface{ EffectiveWindow() (time.Time, time.Time) },
+		interface{ Severity() Severity }:
+		return true
+	default:
+		return false
+	}
+}
+
+// inEffect reports whether the child is in effect at the evaluation time
+// of the context.
+func (c ruleChild[T]) inEffect(ctx context.Context) bool {
+	return !c.decorated || inEffect(ctx, c.rule)
+}
+
+// blocking reports whether a failure of the child fails a logical AND.
+func (c ruleChild[T]) blocking() bool {
+	return !c.decorated || severityOf(c.rule).isBlocking()
+}
+
+// evaluateContext evaluates the child like EvaluateContext.
+func (c ruleChild[T]) evaluateContext(ctx context.Context, input T) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if c.ctxRule != nil {
+		return c.ctxRule.EvaluateContext(ctx, input)
+	}
+	return c.rule.Evaluate(input)
+}
+
+// combineAnd combines child results as a logical AND. Failed children below
+// error severity do not block. Children that are not in effect are satisfied.
+func combineAnd(results []Result, total int) (bool, bool, error) {
+	if total == 0 {
+		return false, true, ErrEmptyRules
+	}
+
+	for _, result := range results {
+		if !result.Satisfied && result.Severity.isBlocking() {
+			return false, true, nil
+		}
+	}
+
+	return true, len(results) == total, nil
+}
+
+// combineOr combines child results as a logical OR. Children that are not
+// in effect are ignored.
+func combineOr(results []Result, total int) (bool, bool, error) {
+	if total == 0 {
+		return false, true, ErrEmptyRules
+	}
+
+	for _, result := range results {
+		if result.Satisfied && !result.Inactive {
+			return true, true, nil
+		}
+	}
+
+	return false, len(results) == total, nil
+}
+
 // collectDomainsFromRules collects and deduplicates domains from child rules.
 func collectDomainsFromRules[T any](rules []Rule[T]) []Domain {
+	return collectDomainsFromRulesIn(DefaultRegistry, rules)
+}
+
+// collectDomainsFromRulesIn is like collectDomainsFromRules but looks the
+// rules up in the given registry.
+func collectDomainsFromRulesIn[T any](registry Registry, rules []Rule[T]) []Domain {
 	domainSet := make(map[Domain]bool)
 
 	for _, rule := range rules {
@@ -305,15 +668,9 @@ func collectDomainsFromRules[T any](rules []Rule[T]) []Domain {
 		}
 
 		// Look up the rule in the registry to get its domains
-		allRules := AllRules()
-		ptr := getRulePointer(rule)
-
-		for _, registered := range allRules {
-			if getRulePointer(registered.Rule) == ptr {
-				for _, domain := range registered.Domains {
-					domainSet[domain] = true
-				}
-				break
+		if registered := lookupRegisteredRuleIn(registry, rule); registered != nil {
+			for _, domain := range registered.Domains {
+				domainSet[domain] = true
 			}
 		}
 	}
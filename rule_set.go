@@ -0,0 +1,411 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrNoRuleMatched is returned when no rule of a rule set matches and the
+// rule set has no default outcome.
+var ErrNoRuleMatched = errors.New("no rule of the rule set matched")
+
+// MatchStrategy determines which matching rules of a rule set produce its
+// outcome.
+type MatchStrategy int
+
+const (
+	// MatchFirst selects the first matching rule in the order the rules
+	// were added. Evaluation stops at the first match.
+	MatchFirst MatchStrategy = iota
+	// MatchAll selects all matching rules in the order the rules were
+	// added.
+	MatchAll
+	// MatchHighestPriority selects the matching rule with the highest
+	// priority (see AddWithPriority). Rules are evaluated from the highest
+	// to the lowest priority and evaluation stops at the first match. Ties
+	// are resolved in the order the rules were added.
+	MatchHighestPriority
+)
+
+// String returns the string representation of a MatchStrategy.
+func (s MatchStrategy) String() string {
+	switch s {
+	case MatchFirst:
+		return "FIRST_MATCH"
+	case MatchAll:
+		return "ALL_MATCHES"
+	case MatchHighestPriority:
+		return "HIGHEST_PRIORITY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ruleSetEntry pairs a rule of a rule set with its outcome.
+type ruleSetEntry[T any, O any] struct {
+	rule     Rule[T]
+	outcome  O
+	priority int
+}
+
+// RuleSet maps inputs to outcomes of type O by pairing rules with
+// outcomes, replacing if/else ladders over several rules. Which matching
+// rules produce the outcome is determined by the match strategy.
+//
+// A RuleSet is also a Rule that is satisfied if one of its rules matches,
+// so it can be documented and combined with other rules. It registers
+// itself in the DefaultRegistry (see WithRuleSetRegistry) and inherits the
+// domains of its rules. Rules that are not in effect (see Effective) never
+// match.
+//
+// Example:
+//
+//	shipping := rules.NewRuleSet[Order, string]("shipping method", rules.MatchFirst).
+//	    Add(isOversized, "freight").
+//	    Add(isExpress, "courier").
+//	    Default("standard")
+//
+//	method, err := shipping.Resolve(order)
+type RuleSet[T any, O any] struct {
+	name       string
+	strategy   MatchStrategy
+	registry   Registry
+	entries    []ruleSetEntry[T, O]
+	order      []int
+	fallback   O
+	hasDefault bool
+}
+
+// RuleSetOption configures a RuleSet.
+type RuleSetOption func(*ruleSetConfig)
+
+// ruleSetConfig holds the configuration of a RuleSet.
+type ruleSetConfig struct {
+	registry Registry
+}
+
+// WithRuleSetRegistry registers the rule set with the given registry
+// instead of the default registry. The rule set inherits the domains its
+// rules have in that registry.
+func WithRuleSetRegistry(registry Registry) RuleSetOption {
+	return func(c *ruleSetConfig) {
+		c.registry = registry
+	}
+}
+
+// NewRuleSet creates an empty rule set with the given match strategy and
+// registers it in the DefaultRegistry, or in the registry set with
+// WithRuleSetRegistry.
+func NewRuleSet[T any, O any](name string, strategy MatchStrategy, opts ...RuleSetOption) *RuleSet[T, O] {
+	config := &ruleSetConfig{registry: DefaultRegistry}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	ruleSet := &RuleSet[T, O]{
+		name:     name,
+		strategy: strategy,
+		registry: config.registry,
+	}
+	_ = ruleSet.registry.Register(ruleSet)
+	return ruleSet
+}
+
+// Add produces outcome when the rule is satisfied.
+func (s *RuleSet[T, O]) Add(rule Rule[T], outcome O) *RuleSet[T, O] {
+	return s.AddWithPriority(rule, 0, outcome)
+}
+
+// AddWithPriority adds a rule with a priority. With MatchHighestPriority,
+// the matching rule with the highest priority produces the outcome. The
+// rule set inherits the domains of the rule.
+func (s *RuleSet[T, O]) AddWithPriority(rule Rule[T], priority int, outcome O) *RuleSet[T, O] {
+	s.entries = append(s.entries, ruleSetEntry[T, O]{
+		rule:     rule,
+		outcome:  outcome,
+		priority: priority,
+	})
+	s.order = s.evaluationOrder()
+
+	// Keep the domains the rule set already has
+	domains := collectDomainsFromRulesIn(s.registry, []Rule[T]{s, rule})
+	if len(domains) > 0 {
+		_ = s.registry.Register(s, WithDomains(domains...))
+	}
+	return s
+}
+
+// Default sets the outcome used when no rule matches.
+func (s *RuleSet[T, O]) Default(outcome O) *RuleSet[T, O] {
+	s.fallback = outcome
+	s.hasDefault = true
+	return s
+}
+
+// Name returns the name of the rule set.
+func (s *RuleSet[T, O]) Name() string {
+	return s.name
+}
+
+// Kind reports that the rule is a rule set.
+func (s *RuleSet[T, O]) Kind() RuleType {
+	return RuleTypeRuleSet
+}
+
+// Children returns the rules of the rule set in the order they were added
+// (for documentation purposes).
+func (s *RuleSet[T, O]) Children() []Rule[T] {
+	children := make([]Rule[T], len(s.entries))
+	for i, entry := range s.entries {
+		children[i] = entry.rule
+	}
+	return children
+}
+
+// Strategy returns the match strategy of the rule set.
+func (s *RuleSet[T, O]) Strategy() MatchStrategy {
+	return s.strategy
+}
+
+// Evaluate reports whether a rule of the rule set matches the input. The
+// default outcome does not count as a match.
+func (s *RuleSet[T, O]) Evaluate(input T) (bool, error) {
+	return s.EvaluateContext(context.Background(), input)
+}
+
+// EvaluateContext is like Evaluate but honors the context.
+func (s *RuleSet[T, O]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	matches, _, err := s.evaluateEntries(&evaluation{ctx: ctx}, input, false)
+	if err != nil {
+		return false, err
+	}
+	return len(matches) > 0, nil
+}
+
+// Resolve returns the outcome of the rule set for the input: the outcome of
+// the rule selected by the match strategy (the first matching rule with
+// MatchAll), or the default outcome if no rule matches. Without a default
+// it fails with ErrNoRuleMatched.
+func (s *RuleSet[T, O]) Resolve(input T) (O, error) {
+	return s.ResolveContext(context.Background(), input)
+}
+
+// ResolveContext is like Resolve but honors the context.
+func (s *RuleSet[T, O]) ResolveContext(ctx context.Context, input T) (O, error) {
+	resolution := s.resolve(&evaluation{ctx: ctx}, input, false)
+	return resolution.Outcome, resolution.Result.Error
+}
+
+// ResolveAll returns the outcomes of all matching rules selected by the
+// match strategy, or the default outcome if no rule matches. Without a
+// default it fails with ErrNoRuleMatched.
+func (s *RuleSet[T, O]) ResolveAll(input T) ([]O, error) {
+	return s.ResolveAllContext(context.Background(), input)
+}
+
+// ResolveAllContext is like ResolveAll but honors the context.
+func (s *RuleSet[T, O]) ResolveAllContext(ctx context.Context, input T) ([]O, error) {
+	resolution := s.resolve(&evaluation{ctx: ctx}, input, false)
+	return resolution.Outcomes, resolution.Result.Error
+}
+
+// Resolution is the detailed outcome of a rule set (see ResolveDetailed).
+type Resolution[O any] struct {
+	// Outcome is the outcome of the first selected rule, or the default
+	// outcome if no rule matched.
+	Outcome O
+	// Outcomes holds the outcomes of all selected rules. Only MatchAll
+	// selects more than one rule.
+	Outcomes []O
+	// Matched holds the names of the selected rules, in the order of
+	// Outcomes.
+	Matched []string
+	// Default reports whether the default outcome was used.
+	Default bool
+	// Result is the detailed evaluation of the rule set, with a child for
+	// each evaluated rule in evaluation order. Its Error is set if the
+	// resolution failed.
+	Result Result
+}
+
+// ResolveDetailed evaluates the rule set and reports the outcome together
+// with the detailed results of the evaluated rules.
+func (s *RuleSet[T, O]) ResolveDetailed(input T) Resolution[O] {
+	return s.ResolveDetailedContext(context.Background(), input)
+}
+
+// ResolveDetailedContext is like ResolveDetailed but honors the context.
+func (s *RuleSet[T, O]) ResolveDetailedContext(ctx context.Context, input T) Resolution[O] {
+	return s.resolve(&evaluation{ctx: ctx}, input, true)
+}
+
+// resolve evaluates the rule set and selects its outcome.
+func (s *RuleSet[T, O]) resolve(ev *evaluation, input T, detailed bool) Resolution[O] {
+	start := time.Now()
+	matches, results, err := s.evaluateEntries(ev, input, detailed)
+
+	var resolution Resolution[O]
+	switch {
+	case err != nil:
+	case len(matches) == 0 && s.hasDefault:
+		resolution.Outcome = s.fallback
+		resolution.Outcomes = []O{s.fallback}
+		resolution.Default = true
+	case len(matches) == 0:
+		err = fmt.Errorf("evaluating rule set %q: %w", s.name, ErrNoRuleMatched)
+	default:
+		for _, match := range matches {
+			entry := s.entries[match]
+			resolution.Outcomes = append(resolution.Outcomes, entry.outcome)
+			resolution.Matched = append(resolution.Matched, entry.rule.Name())
+		}
+		resolution.Outcome = resolution.Outcomes[0]
+	}
+
+	resolution.Result = Result{
+		Satisfied: err == nil && len(matches) > 0,
+		RuleName:  s.name,
+		Duration:  time.Since(start),
+		Error:     err,
+		Children:  results,
+	}
+	return resolution
+}
+
+// evaluateNested reports the evaluated rules as the children of the rule
+// set in detailed evaluation.
+func (s *RuleSet[T, O]) evaluateNested(ev *evaluation, input T) (bool, []Result, error) {
+	matches, results, err := s.evaluateEntries(ev, input, true)
+	return err == nil && len(matches) > 0, results, err
+}
+
+// evaluateEntries evaluates the rules the match strategy needs and returns
+// the indexes of the selected rules. In detailed mode it also returns a
+// result for every evaluated rule.
+func (s *RuleSet[T, O]) evaluateEntries(ev *evaluation, input T, detailed bool) ([]int, []Result, error) {
+	var (
+		matches []int
+		results []Result
+	)
+
+	for _, i := range s.order {
+		rule := s.entries[i].rule
+		if rule == nil {
+			return nil, results, fmt.Errorf("evaluating rule set %q: %w", s.name, ErrNilRule)
+		}
+
+		var (
+			matched bool
+			err     error
+		)
+		if detailed {
+			result := evaluateRuleDetailed(ev, rule, input)
+			results = append(results, result)
+			matched, err = result.Satisfied && !result.Inactive, result.Error
+		} else if inEffect(ev.ctx, rule) {
+			matched, err = EvaluateContext(ev.ctx, rule, input)
+		}
+		if err != nil {
+			return nil, results, fmt.Errorf("evaluating rule set %q: %w", s.name, err)
+		}
+
+		if matched {
+			matches = append(matches, i)
+			if s.strategy != MatchAll {
+				break
+			}
+		}
+	}
+
+	return matches, results, nil
+}
+
+// evaluationOrder returns the indexes of the rules in the order they are
+// evaluated: by descending priority with MatchHighestPriority, otherwise
+// in the order they were added. It is computed when a rule is added.
+func (s *RuleSet[T, O]) evaluationOrder() []int {
+	order := make([]int, len(s.entries))
+	for i := range order {
+		order[i] = i
+	}
+	if s.strategy == MatchHighestPriority {
+		sort.SliceStable(order, func(i, j int) bool {
+			return s.entries[order[i]].priority > s.entries[order[j]].priority
+		})
+	}
+	return order
+}
+
+// layout describes the rule set for documentation.
+func (s *RuleSet[T, O]) layout() *ruleSetLayout {
+	layout := &ruleSetLayout{
+		Strategy:   s.strategy,
+		HasDefault: s.hasDefault,
+	}
+	if s.hasDefault {
+		layout.Default = fmt.Sprint(s.fallback)
+	}
+
+	for _, entry := range s.entries {
+		name := "-"
+		if entry.rule != nil {
+			name = entry.rule.Name()
+		}
+		layout.Entries = append(layout.Entries, ruleSetEntryLayout{
+			Rule:     name,
+			Outcome:  fmt.Sprint(entry.outcome),
+			Priority: entry.priority,
+		})
+	}
+
+	return layout
+}
+
+// ruleSetLayout describes a rule set for documentation.
+type ruleSetLayout struct {
+	Strategy   MatchStrategy
+	Entries    []ruleSetEntryLayout
+	Default    string
+	HasDefault bool
+}
+
+// ruleSetEntryLayout describes a rule of a rule set for documentation.
+type ruleSetEntryLayout struct {
+	Rule     string
+	Outcome  string
+	Priority int
+}
+
+// header returns the column headers of the documented rule set.
+func (l *ruleSetLayout) header() []string {
+	if l.Strategy == MatchHighestPriority {
+		return []string{"#", "Rule", "Priority", "Outcome"}
+	}
+	return []string{"#", "Rule", "Outcome"}
+}
+
+// rows returns the cells of the documented rule set, including a final row
+// for the default outcome.
+func (l *ruleSetLayout) rows() [][]string {
+	var rows [][]string
+	for i, entry := range l.Entries {
+		cells := []string{fmt.Sprint(i + 1), entry.Rule}
+		if l.Strategy == MatchHighestPriority {
+			cells = append(cells, fmt.Sprint(entry.Priority))
+		}
+		rows = append(rows, append(cells, entry.Outcome))
+	}
+
+	if l.HasDefault {
+		cells := []string{"otherwise", "-"}
+		if l.Strategy == MatchHighestPriority {
+			cells = append(cells, "-")
+		}
+		rows = append(rows, append(cells, l.Default))
+	}
+
+	return rows
+}
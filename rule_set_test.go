@@ -0,0 +1,218 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parcel is the input of the shipping method rule sets.
+type parcel struct {
+	weight  float64
+	express bool
+}
+
+var (
+	isHeavy = New("is heavy", func(p parcel) (bool, error) {
+		return p.weight > 30, nil
+	})
+	isExpress = New("is express", func(p parcel) (bool, error) {
+		return p.express, nil
+	})
+)
+
+func TestRuleSet_Strategies(t *testing.T) {
+	t.Parallel()
+
+	newRuleSet := func(strategy MatchStrategy) *RuleSet[parcel, string] {
+		return NewRuleSet[parcel, string]("shipping method", strategy).
+			AddWithPriority(isHeavy, 1, "freight").
+			AddWithPriority(isExpress, 2, "courier")
+	}
+	heavyExpress := parcel{weight: 40, express: true}
+
+	tests := []struct {
+		name      string
+		strategy  MatchStrategy
+		want      []string
+		wantTrace []string
+	}{
+		{name: "first match", strategy: MatchFirst, want: []string{"freight"}, wantTrace: []string{"is heavy"}},
+		{name: "all matches", strategy: MatchAll, want: []string{"freight", "courier"}, wantTrace: []string{"is heavy", "is express"}},
+		{name: "highest priority", strategy: MatchHighestPriority, want: []string{"courier"}, wantTrace: []string{"is express"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolution := newRuleSet(tt.strategy).ResolveDetailed(heavyExpress)
+			if resolution.Result.Error != nil {
+				t.Fatalf("ResolveDetailed() error = %v", resolution.Result.Error)
+			}
+			if strings.Join(resolution.Outcomes, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Outcomes = %v, want %v", resolution.Outcomes, tt.want)
+			}
+			if resolution.Outcome != tt.want[0] || strings.Join(resolution.Matched, ",") != strings.Join(tt.wantTrace, ",") {
+				t.Errorf("Unexpected resolution: %+v", resolution)
+			}
+
+			// Evaluation stops at the first match unless all matches are needed
+			var trace []string
+			for _, child := range resolution.Result.Children {
+				trace = append(trace, child.RuleName)
+			}
+			if strings.Join(trace, ",") != strings.Join(tt.wantTrace, ",") {
+				t.Errorf("Evaluated rules = %v, want %v", trace, tt.wantTrace)
+			}
+		})
+	}
+}
+
+func TestRuleSet_Resolve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+
+		ruleSet := NewRuleSet[parcel, string]("shipping method", MatchFirst).
+			Add(isHeavy, "freight")
+
+		if _, err := ruleSet.Resolve(parcel{}); !errors.Is(err, ErrNoRuleMatched) {
+			t.Errorf("Expected ErrNoRuleMatched, got %v", err)
+		}
+		if satisfied, err := ruleSet.Evaluate(parcel{}); err != nil || satisfied {
+			t.Errorf("Evaluate() = %v, %v; want false, nil", satisfied, err)
+		}
+
+		ruleSet.Default("standard")
+		if got, err := ruleSet.ResolveAll(parcel{}); err != nil || len(got) != 1 || got[0] != "standard" {
+			t.Errorf("ResolveAll() = %v, %v; want [standard], nil", got, err)
+		}
+		if resolution := ruleSet.ResolveDetailed(parcel{}); !resolution.Default || resolution.Result.Satisfied {
+			t.Errorf("Unexpected resolution: %+v", resolution)
+		}
+	})
+
+	t.Run("rules not in effect never match", func(t *testing.T) {
+		t.Parallel()
+
+		ruleSet := NewRuleSet[parcel, string]("shipping method", MatchFirst).
+			Add(Effective(isHeavy, effectiveJul1, time.Time{}), "freight").
+			Default("standard")
+
+		ctx := AsOf(context.Background(), effectiveJan1)
+		if got, err := ruleSet.ResolveContext(ctx, parcel{weight: 40}); err != nil || got != "standard" {
+			t.Errorf("ResolveContext() = %q, %v; want standard, nil", got, err)
+		}
+		if resolution := ruleSet.ResolveDetailedContext(ctx, parcel{weight: 40}); !resolution.Default {
+			t.Errorf("Unexpected resolution: %+v", resolution)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		failing := New("failing", func(p parcel) (bool, error) {
+			return false, ErrEvaluationFailed
+		})
+		_, err := NewRuleSet[parcel, int]("set", MatchFirst).Add(failing, 1).Resolve(parcel{})
+		if !errors.Is(err, ErrEvaluationFailed) || !strings.Contains(err.Error(), `rule set "set"`) {
+			t.Errorf("Expected a wrapped evaluation error, got %v", err)
+		}
+
+		_, err = NewRuleSet[parcel, int]("set", MatchFirst).Add(nil, 1).Resolve(parcel{})
+		if !errors.Is(err, ErrNilRule) {
+			t.Errorf("Expected ErrNilRule, got %v", err)
+		}
+	})
+
+	t.Run("evaluator", func(t *testing.T) {
+		t.Parallel()
+
+		ruleSet := NewRuleSet[parcel, string]("shipping method", MatchAll).
+			Add(isHeavy, "freight").
+			Add(isExpress, "courier")
+
+		result := NewEvaluator[parcel](ruleSet).EvaluateDetailed(parcel{express: true})
+		if !result.Satisfied || len(result.Children) != 2 || result.Children[0].Satisfied {
+			t.Errorf("Unexpected evaluator result: %v", result)
+		}
+	})
+}
+
+func TestRuleSet_Registry(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	heavy := New("is heavy", func(p parcel) (bool, error) { return p.weight > 30, nil })
+	_ = registry.Register(heavy, WithDomain(TestOrderDomain))
+
+	ruleSet := NewRuleSet[parcel, string]("shipping method", MatchHighestPriority, WithRuleSetRegistry(registry)).
+		AddWithPriority(heavy, 1, "freight").
+		Default("standard")
+
+	registered := lookupRegisteredRuleIn(registry, ruleSet)
+	if registered == nil || len(registered.Domains) != 1 || registered.Domains[0] != TestOrderDomain {
+		t.Fatalf("Expected the rule set to be registered in %s, got %+v", TestOrderDomain, registered)
+	}
+	if lookupRegisteredRule(ruleSet) != nil {
+		t.Error("Expected the rule set not to be registered in the default registry")
+	}
+
+	// The evaluation order follows rules added after an evaluation
+	heavyExpress := parcel{weight: 40, express: true}
+	if got, err := ruleSet.Resolve(heavyExpress); err != nil || got != "freight" {
+		t.Errorf("Resolve() = %q, %v; want freight, nil", got, err)
+	}
+	ruleSet.AddWithPriority(isExpress, 2, "courier")
+	if got, err := ruleSet.Resolve(heavyExpress); err != nil || got != "courier" {
+		t.Errorf("Resolve() = %q, %v; want courier, nil", got, err)
+	}
+}
+
+func TestRuleSetDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	heavy := New("is heavy", func(p parcel) (bool, error) { return p.weight > 30, nil })
+	_ = Register(heavy, WithDomain(TestOrderDomain))
+
+	// The rule set registers itself and inherits the domains of its rules
+	ruleSet := NewRuleSet[parcel, string]("shipping method", MatchHighestPriority).
+		AddWithPriority(heavy, 1, "freight").
+		AddWithPriority(isExpress, 2, "courier").
+		Default("standard")
+	registered := lookupRegisteredRule(ruleSet)
+	if registered == nil || len(registered.Domains) != 1 || registered.Domains[0] != TestOrderDomain {
+		t.Fatalf("Expected the rule set to be registered in %s, got %+v", TestOrderDomain, registered)
+	}
+
+	md, err := GenerateMarkdown(DocumentOptions{IncludeDomains: []Domain{TestOrderDomain}})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"### shipping method (RULE_SET)",
+		"**Strategy**: HIGHEST_PRIORITY",
+		"| # | Rule | Priority | Outcome |",
+		"| 2 | is express | 2 | courier |",
+		"| otherwise | - | - | standard |",
+		"is express (SIMPLE)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{IncludeDomains: []Domain{TestOrderDomain}})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	if !strings.Contains(htmlDoc, "Strategy: HIGHEST_PRIORITY") ||
+		!strings.Contains(htmlDoc, "<td>is heavy</td><td>1</td><td>freight</td>") {
+		t.Error("HTML should contain the rules and outcomes")
+	}
+}
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -415,3 +416,97 @@ func TestHierarchicalRules(t *testing.T) {
 		})
 	}
 }
+
+func TestContextRule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("predicate receives context", func(t *testing.T) {
+		t.Parallel()
+
+		type ctxKey struct{}
+		rule := NewContext(
+			"has tenant",
+			func(ctx context.Context, input testInput) (bool, error) {
+				return ctx.Value(ctxKey{}) == "acme", nil
+			},
+		)
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "acme")
+		got, err := rule.EvaluateContext(ctx, testInput{})
+		if err != nil {
+			t.Fatalf("EvaluateContext() error = %v", err)
+		}
+		if !got {
+			t.Error("EvaluateContext() = false, want true")
+		}
+
+		got, err = rule.Evaluate(testInput{})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if got {
+			t.Error("Evaluate() = true, want false without context value")
+		}
+	})
+
+	t.Run("cancelled context stops composite evaluation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		first := New("first", func(input testInput) (bool, error) {
+			calls++
+			cancel()
+			return true, nil
+		})
+		second := New("second", func(input testInput) (bool, error) {
+			calls++
+			return true, nil
+		})
+
+		rule := And("all", first, Or("any", second), Not("not", Never[testInput]("never")))
+		_, err := EvaluateContext(ctx, rule, testInput{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("EvaluateContext() error = %v, want context.Canceled", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 predicate call, got %d", calls)
+		}
+	})
+
+	t.Run("deadline exceeded is reported", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		rule := AtLeast("at least one", 1, Always[testInput]("always"))
+		_, err := EvaluateContext(ctx, rule, testInput{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("EvaluateContext() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("context propagates through mapped rules", func(t *testing.T) {
+		t.Parallel()
+
+		var seen context.Context
+		inner := NewContext("inner", func(ctx context.Context, value int) (bool, error) {
+			seen = ctx
+			return value > 10, nil
+		})
+		rule := Map("mapped", inner, func(input testInput) int { return input.value })
+
+		ctx := context.WithValue(context.Background(), struct{}{}, "marker")
+		got, err := EvaluateContext(ctx, rule, testInput{value: 15})
+		if err != nil {
+			t.Fatalf("EvaluateContext() error = %v", err)
+		}
+		if !got {
+			t.Error("EvaluateContext() = false, want true")
+		}
+		if seen != ctx {
+			t.Error("Expected inner predicate to receive the evaluation context")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package rulestest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tobbstr/rules"
+)
+
+// UpdateEnv is the environment variable that makes ExpectGolden write the
+// golden files instead of comparing against them, e.g.
+//
+//	RULESTEST_UPDATE=1 go test ./...
+const UpdateEnv = "RULESTEST_UPDATE"
+
+// ExpectGolden reports a test failure unless the result tree, rendered with
+// Format, matches the contents of the golden file at path. Mismatches are
+// reported as a line diff. With UpdateEnv set to a non-empty value, the
+// golden file is written instead, creating its directory if needed. It
+// returns whether the expectation held.
+func ExpectGolden(t testing.TB, result rules.Result, path string) bool {
+	t.Helper()
+
+	got := Format(result)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist; run with %s=1 to create it, got:\n%s", path, UpdateEnv, got)
+		return false
+	}
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+
+	if string(want) == got {
+		return true
+	}
+	t.Errorf("result does not match golden file %s (-want +got):\n%s", path, Diff(string(want), got))
+	return false
+}
+
+// Diff returns a line diff of two texts: lines only in want are prefixed
+// with "- ", lines only in got with "+ " and common lines with "  ". It
+// returns an empty string if the texts are equal.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
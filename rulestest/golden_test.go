@@ -0,0 +1,95 @@
+package rulestest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpectGolden(t *testing.T) {
+	t.Parallel()
+
+	small := evaluate(order{Amount: 50, Country: "NO"})
+
+	t.Run("match", func(t *testing.T) {
+		t.Parallel()
+
+		ExpectGolden(t, small, filepath.Join("testdata", "small_order.golden"))
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recordingT{TB: t}
+		if ExpectGolden(recorder, evaluate(order{Amount: 50, Country: "SE"}), filepath.Join("testdata", "small_order.golden")) {
+			t.Fatal("ExpectGolden() should fail")
+		}
+		for _, want := range []string{
+			"(-want +got)",
+			"- " + `  ✗ valid country [warning]`,
+			"+ " + `  ✓ valid country [warning]`,
+			"  " + `  ✓ shipping`,
+		} {
+			if !strings.Contains(recorder.failures[0], want) {
+				t.Errorf("Failure should contain %q:\n%s", want, recorder.failures[0])
+			}
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &recordingT{TB: t}
+		if ExpectGolden(recorder, small, filepath.Join(t.TempDir(), "missing.golden")) {
+			t.Fatal("ExpectGolden() should fail")
+		}
+		if !strings.Contains(recorder.failures[0], "run with RULESTEST_UPDATE=1") {
+			t.Errorf("Unexpected failure: %s", recorder.failures[0])
+		}
+	})
+}
+
+func TestExpectGolden_Update(t *testing.T) {
+	t.Setenv(UpdateEnv, "1")
+
+	path := filepath.Join(t.TempDir(), "golden", "small_order.golden")
+	small := evaluate(order{Amount: 50, Country: "NO"})
+	if !ExpectGolden(t, small, path) {
+		t.Fatal("ExpectGolden() should succeed when updating")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != Format(small) {
+		t.Errorf("Golden file = %q, want %q", got, Format(small))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{name: "equal", want: "a\nb\n", got: "a\nb\n", diff: ""},
+		{name: "changed", want: "a\nb\nc\n", got: "a\nx\nc\n", diff: "  a\n- b\n+ x\n  c\n"},
+		{name: "added", want: "a\n", got: "a\nb\n", diff: "  a\n+ b\n"},
+		{name: "removed", want: "a\nb\nc\n", got: "a\nc\n", diff: "  a\n- b\n  c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Diff(tt.want, tt.got); got != tt.diff {
+				t.Errorf("Diff() =\n%s\nwant:\n%s", got, tt.diff)
+			}
+		})
+	}
+}
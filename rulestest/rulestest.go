@@ -0,0 +1,247 @@
+// Package rulestest provides assertions on the detailed results of rule
+// evaluations for use in tests.
+//
+// Rules in a result tree are addressed by paths of rule names separated by
+// " > ", starting at the root:
+//
+//	result := rules.NewEvaluator(validation).EvaluateDetailed(order)
+//
+//	rulestest.ExpectUnsatisfied(t, result)
+//	rulestest.ExpectPathUnsatisfied(t, result, "order validation > minimum amount")
+//	rulestest.ExpectUnsatisfiedLeaves(t, result, "minimum amount", "valid country")
+//	rulestest.ExpectGolden(t, result, "testdata/small_order.golden")
+//
+// Failed expectations report the result tree, rendered without durations
+// (see Format), and golden files report a line diff.
+package rulestest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tobbstr/rules"
+)
+
+// PathSeparator separates the rule names of a path.
+const PathSeparator = " > "
+
+// ErrNotFound is returned by Find when no rule in the result tree matches
+// a path.
+var ErrNotFound = errors.New("rule not found")
+
+// ExpectSatisfied reports a test failure unless the result is satisfied
+// without an error. It returns whether the expectation held.
+func ExpectSatisfied(t testing.TB, result rules.Result) bool {
+	t.Helper()
+
+	if result.IsSuccessful() {
+		return true
+	}
+	t.Errorf("expected %q to be satisfied, got:\n%s", result.RuleName, Format(result))
+	return false
+}
+
+// ExpectUnsatisfied reports a test failure unless the result is not
+// satisfied and has no error. It returns whether the expectation held.
+func ExpectUnsatisfied(t testing.TB, result rules.Result) bool {
+	t.Helper()
+
+	if !result.Satisfied && result.Error == nil {
+		return true
+	}
+	t.Errorf("expected %q to be unsatisfied, got:\n%s", result.RuleName, Format(result))
+	return false
+}
+
+// ExpectPathSatisfied reports a test failure unless the rule at path exists
+// and is satisfied without an error. It returns whether the expectation
+// held.
+func ExpectPathSatisfied(t testing.TB, result rules.Result, path string) bool {
+	t.Helper()
+	return expectPath(t, result, path, true)
+}
+
+// ExpectPathUnsatisfied reports a test failure unless the rule at path
+// exists and is not satisfied, without an error. It returns whether the
+// expectation held.
+func ExpectPathUnsatisfied(t testing.TB, result rules.Result, path string) bool {
+	t.Helper()
+	return expectPath(t, result, path, false)
+}
+
+// expectPath implements ExpectPathSatisfied and ExpectPathUnsatisfied.
+func expectPath(t testing.TB, result rules.Result, path string, satisfied bool) bool {
+	t.Helper()
+
+	found, err := Find(result, path)
+	if err != nil {
+		t.Errorf("%v in:\n%s", err, Format(result))
+		return false
+	}
+	if found.Satisfied == satisfied && found.Error == nil {
+		return true
+	}
+
+	want := "satisfied"
+	if !satisfied {
+		want = "unsatisfied"
+	}
+	t.Errorf("expected %q to be %s, got:\n%s", path, want, Format(result))
+	return false
+}
+
+// ExpectUnsatisfiedLeaves reports a test failure unless the names of the
+// unsatisfied leaves of the result are exactly the given names, in any
+// order (see UnsatisfiedLeaves). It returns whether the expectation held.
+func ExpectUnsatisfiedLeaves(t testing.TB, result rules.Result, names ...string) bool {
+	t.Helper()
+
+	missing, unexpected := compareNames(names, UnsatisfiedLeaves(result))
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return true
+	}
+
+	var sb strings.Builder
+	sb.WriteString("unexpected unsatisfied leaves:\n")
+	for _, name := range missing {
+		sb.WriteString(fmt.Sprintf("- %s (satisfied or not found)\n", name))
+	}
+	for _, name := range unexpected {
+		sb.WriteString(fmt.Sprintf("+ %s\n", name))
+	}
+	sb.WriteString("in:\n")
+	sb.WriteString(Format(result))
+	t.Error(sb.String())
+	return false
+}
+
+// compareNames returns the names wanted but not got and the names got but
+// not wanted, counting duplicates.
+func compareNames(want, got []string) (missing, unexpected []string) {
+	counts := make(map[string]int)
+	for _, name := range got {
+		counts[name]++
+	}
+	for _, name := range want {
+		if counts[name] > 0 {
+			counts[name]--
+			continue
+		}
+		missing = append(missing, name)
+	}
+	for _, name := range got {
+		if counts[name] > 0 {
+			counts[name]--
+			unexpected = append(unexpected, name)
+		}
+	}
+	return missing, unexpected
+}
+
+// Find returns the result of the rule at path, e.g. "order validation >
+// minimum amount". The first name must be the name of the root; each
+// following name selects the first child with that name.
+func Find(result rules.Result, path string) (rules.Result, error) {
+	names := strings.Split(path, PathSeparator)
+	if names[0] != result.RuleName {
+		return rules.Result{}, fmt.Errorf("path %q: root is %q: %w", path, result.RuleName, ErrNotFound)
+	}
+
+	for i, name := range names[1:] {
+		index := slices.IndexFunc(result.Children, func(child rules.Result) bool {
+			return child.RuleName == name
+		})
+		if index < 0 {
+			parent := strings.Join(names[:i+1], PathSeparator)
+			return rules.Result{}, fmt.Errorf("path %q: no child %q of %q: %w", path, name, parent, ErrNotFound)
+		}
+		result = result.Children[index]
+	}
+	return result, nil
+}
+
+// UnsatisfiedLeaves returns the names of the rules without child results
+// that are not satisfied, anywhere in the result tree, in evaluation order.
+// Unlike Result.UnsatisfiedRules, it leaves out the composites.
+func UnsatisfiedLeaves(result rules.Result) []string {
+	if len(result.Children) == 0 {
+		if result.Satisfied {
+			return nil
+		}
+		return []string{result.RuleName}
+	}
+
+	var leaves []string
+	for _, child := range result.Children {
+		leaves = append(leaves, UnsatisfiedLeaves(child)...)
+	}
+	return leaves
+}
+
+// StripDurations returns a copy of the result tree with every duration set
+// to zero, so that results can be compared with reflect.DeepEqual.
+func StripDurations(result rules.Result) rules.Result {
+	result.Duration = 0
+	if result.Children != nil {
+		children := make([]rules.Result, len(result.Children))
+		for i, child := range result.Children {
+			children[i] = StripDurations(child)
+		}
+		result.Children = children
+	}
+	return result
+}
+
+// Format renders a result tree like Result.String, without durations, so
+// that the rendering of an evaluation is the same every time it runs:
+//
+//	✗ order validation
+//	  ✓ minimum amount
+//	  ✗ valid country [warning]
+//	    ! country: [unsupported] country is not supported
+func Format(result rules.Result) string {
+	var sb strings.Builder
+	format(&sb, result, 0)
+	return sb.String()
+}
+
+// format writes a result and its children at the given indentation.
+func format(sb *strings.Builder, result rules.Result, indent int) {
+	prefix := strings.Repeat("  ", indent)
+
+	status := "✓"
+	if !result.Satisfied {
+		status = "✗"
+	}
+	if result.Error != nil {
+		status = "⚠"
+	}
+	sb.WriteString(fmt.Sprintf("%s%s %s", prefix, status, result.RuleName))
+
+	if result.Severity != rules.SeverityError {
+		sb.WriteString(fmt.Sprintf(" [%s]", result.Severity))
+	}
+	if result.Cached {
+		sb.WriteString(" [cached]")
+	}
+	if result.Inactive {
+		sb.WriteString(" [not in effect]")
+	}
+	if result.Points != 0 {
+		sb.WriteString(fmt.Sprintf(" [%+d points]", result.Points))
+	}
+	if result.Error != nil {
+		sb.WriteString(fmt.Sprintf(" - Error: %v", result.Error))
+	}
+	sb.WriteString("\n")
+
+	for _, violation := range result.RuleViolations {
+		sb.WriteString(fmt.Sprintf("%s  ! %s\n", prefix, violation))
+	}
+	for _, child := range result.Children {
+		format(sb, child, indent+1)
+	}
+}
@@ -0,0 +1,179 @@
+package rulestest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tobbstr/rules"
+)
+
+// order is the input of the rules under test.
+type order struct {
+	Amount  int
+	Country string
+	Express bool
+}
+
+// orderValidation returns a rule tree with nested composites, a warning and
+// a violation.
+func orderValidation() rules.Rule[order] {
+	return rules.And("order validation",
+		rules.New("minimum amount", func(o order) (bool, error) { return o.Amount >= 100, nil }),
+		rules.WithSeverity(rules.WithViolation(
+			rules.New("valid country", func(o order) (bool, error) { return o.Country == "SE", nil }),
+			rules.Violation{Field: "country", Code: "unsupported", Message: "country is not supported"},
+		), rules.SeverityWarning),
+		rules.Or("shipping",
+			rules.New("express", func(o order) (bool, error) { return o.Express, nil }),
+			rules.New("standard", func(o order) (bool, error) { return o.Amount < 1000, nil }),
+		),
+	)
+}
+
+// evaluate evaluates the order validation in full.
+func evaluate(o order) rules.Result {
+	return rules.NewEvaluator(orderValidation()).EvaluateDetailed(o)
+}
+
+// recordingT records the failures of expectations instead of failing the
+// test.
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Error(args ...any) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestExpectations(t *testing.T) {
+	t.Parallel()
+
+	small := evaluate(order{Amount: 50, Country: "NO"})
+	large := evaluate(order{Amount: 500, Country: "SE", Express: true})
+
+	tests := []struct {
+		name   string
+		expect func(t testing.TB) bool
+		want   string // substring of the failure, empty if none
+	}{
+		{
+			name:   "satisfied",
+			expect: func(t testing.TB) bool { return ExpectSatisfied(t, large) },
+		},
+		{
+			name:   "not satisfied",
+			expect: func(t testing.TB) bool { return ExpectSatisfied(t, small) },
+			want:   "expected \"order validation\" to be satisfied, got:\n✗ order validation\n  ✗ minimum amount\n",
+		},
+		{
+			name:   "unsatisfied",
+			expect: func(t testing.TB) bool { return ExpectUnsatisfied(t, small) },
+		},
+		{
+			name:   "path unsatisfied",
+			expect: func(t testing.TB) bool { return ExpectPathUnsatisfied(t, small, "order validation > minimum amount") },
+		},
+		{
+			name: "path satisfied",
+			expect: func(t testing.TB) bool {
+				return ExpectPathSatisfied(t, small, "order validation > shipping > standard")
+			},
+		},
+		{
+			name:   "path not found",
+			expect: func(t testing.TB) bool { return ExpectPathSatisfied(t, small, "order validation > shipping > pickup") },
+			want:   `no child "pickup" of "order validation > shipping"`,
+		},
+		{
+			name:   "path with other outcome",
+			expect: func(t testing.TB) bool { return ExpectPathUnsatisfied(t, large, "order validation > shipping") },
+			want:   `expected "order validation > shipping" to be unsatisfied`,
+		},
+		{
+			name: "unsatisfied leaves",
+			expect: func(t testing.TB) bool {
+				return ExpectUnsatisfiedLeaves(t, small, "valid country", "express", "minimum amount")
+			},
+		},
+		{
+			name:   "other unsatisfied leaves",
+			expect: func(t testing.TB) bool { return ExpectUnsatisfiedLeaves(t, small, "minimum amount", "standard") },
+			want:   "unexpected unsatisfied leaves:\n- standard (satisfied or not found)\n+ valid country\n+ express\nin:\n",
+		},
+		{
+			name:   "no unsatisfied leaves",
+			expect: func(t testing.TB) bool { return ExpectUnsatisfiedLeaves(t, large) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &recordingT{TB: t}
+			ok := tt.expect(recorder)
+			if tt.want == "" {
+				if !ok || len(recorder.failures) != 0 {
+					t.Errorf("Expectation failed: %q", recorder.failures)
+				}
+				return
+			}
+			if ok || len(recorder.failures) != 1 {
+				t.Fatalf("Expectation = %v with failures %q, want one failure", ok, recorder.failures)
+			}
+			if !strings.Contains(recorder.failures[0], tt.want) {
+				t.Errorf("Failure = %q, want it to contain %q", recorder.failures[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	result := evaluate(order{Amount: 50})
+
+	found, err := Find(result, "order validation > shipping > express")
+	if err != nil || found.RuleName != "express" || found.Satisfied {
+		t.Errorf("Find() = %+v, %v", found, err)
+	}
+	if _, err := Find(result, "shipping"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	want := `✗ order validation
+  ✗ minimum amount
+  ✗ valid country [warning]
+    ! country: [unsupported] country is not supported
+  ✓ shipping
+    ✗ express
+    ✓ standard
+`
+	if got := Format(evaluate(order{Amount: 50, Country: "NO"})); got != want {
+		t.Errorf("Format() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStripDurations(t *testing.T) {
+	t.Parallel()
+
+	first := StripDurations(evaluate(order{Amount: 50}))
+	second := StripDurations(evaluate(order{Amount: 50}))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Stripped results differ:\n%+v\n%+v", first, second)
+	}
+}
@@ -0,0 +1,349 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ScoreBand awards points when a value lies within [Min, Max). Use
+// math.Inf(-1) or math.Inf(1) to leave a band open on one side.
+type ScoreBand struct {
+	Min    float64
+	Max    float64
+	Points int
+}
+
+// contains reports whether the value lies within the band.
+func (b ScoreBand) contains(value float64) bool {
+	return value >= b.Min && value < b.Max
+}
+
+// String describes the band, e.g. "0.3 to 0.5" or "at least 0.5".
+func (b ScoreBand) String() string {
+	switch {
+	case math.IsInf(b.Min, -1) && math.IsInf(b.Max, 1):
+		return "any value"
+	case math.IsInf(b.Min, -1):
+		return fmt.Sprintf("below %g", b.Max)
+	case math.IsInf(b.Max, 1):
+		return fmt.Sprintf("at least %g", b.Min)
+	default:
+		return fmt.Sprintf("%g to %g", b.Min, b.Max)
+	}
+}
+
+// scoreItem is a criterion of a scorecard: either a rule that awards points
+// when satisfied, or a numeric value scored by bands.
+type scoreItem[T any] struct {
+	rule   Rule[T]
+	points int
+
+	name  string
+	value func(T) float64
+	bands []ScoreBand
+}
+
+// scoreThreshold maps scores of at least minimum to an outcome.
+type scoreThreshold struct {
+	minimum int
+	outcome string
+}
+
+// Scorecard sums the points awarded by rules and value bands and maps the
+// total score to an outcome using thresholds.
+//
+// A Scorecard is also a Rule that is satisfied if the score reaches a
+// threshold, so it can be registered, documented and combined with other
+// rules. Detailed evaluation reports the points each criterion contributed
+// (see Result.Points).
+//
+// Example:
+//
+//	credit := rules.NewScorecard[Applicant]("credit score").
+//	    Add(hasStableIncome, 50).
+//	    Add(hasDefaulted, -100).
+//	    AddBands("debt ratio", debtRatio,
+//	        rules.ScoreBand{Min: math.Inf(-1), Max: 0.3, Points: 40},
+//	        rules.ScoreBand{Min: 0.3, Max: 0.5, Points: 10},
+//	    ).
+//	    Threshold(80, "approve").
+//	    Threshold(50, "review").
+//	    Default("decline")
+//
+//	score, err := credit.Score(applicant)
+type Scorecard[T any] struct {
+	name       string
+	items      []scoreItem[T]
+	thresholds []scoreThreshold
+	fallback   string
+}
+
+// NewScorecard creates an empty scorecard.
+func NewScorecard[T any](name string) *Scorecard[T] {
+	return &Scorecard[T]{name: name}
+}
+
+// Add awards points (which may be negative) when the rule is satisfied.
+// Rules that are not in effect (see Effective) award no points.
+func (s *Scorecard[T]) Add(rule Rule[T], points int) *Scorecard[T] {
+	s.items = append(s.items, scoreItem[T]{rule: rule, points: points})
+	return s
+}
+
+// AddBands awards the points of the first band that contains the value
+// extracted from the input. Values outside all bands award no points.
+func (s *Scorecard[T]) AddBands(name string, value func(T) float64, bands ...ScoreBand) *Scorecard[T] {
+	s.items = append(s.items, scoreItem[T]{name: name, value: value, bands: bands})
+	return s
+}
+
+// Threshold maps scores of at least minimum to the outcome. The highest
+// threshold reached determines the outcome.
+func (s *Scorecard[T]) Threshold(minimum int, outcome string) *Scorecard[T] {
+	s.thresholds = append(s.thresholds, scoreThreshold{minimum: minimum, outcome: outcome})
+	sort.SliceStable(s.thresholds, func(i, j int) bool {
+		return s.thresholds[i].minimum > s.thresholds[j].minimum
+	})
+	return s
+}
+
+// Default sets the outcome of scores below all thresholds.
+func (s *Scorecard[T]) Default(outcome string) *Scorecard[T] {
+	s.fallback = outcome
+	return s
+}
+
+// Name returns the name of the scorecard.
+func (s *Scorecard[T]) Name() string {
+	return s.name
+}
+
+// Kind reports that the rule is a scorecard.
+func (s *Scorecard[T]) Kind() RuleType {
+	return RuleTypeScorecard
+}
+
+// Evaluate reports whether the score of the input reaches a threshold.
+func (s *Scorecard[T]) Evaluate(input T) (bool, error) {
+	return s.EvaluateContext(context.Background(), input)
+}
+
+// EvaluateContext is like Evaluate but honors the context.
+func (s *Scorecard[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	total, err := s.total(ctx, input)
+	if err != nil {
+		return false, err
+	}
+	_, reached := s.outcome(total)
+	return reached, nil
+}
+
+// Score is the outcome of a scorecard.
+type Score struct {
+	// Total is the sum of the points of all criteria.
+	Total int
+	// Outcome is the outcome of the highest threshold reached, or the
+	// default outcome.
+	Outcome string
+	// Reached reports whether a threshold was reached.
+	Reached bool
+	// Result is the detailed evaluation of the scorecard, with a child for
+	// each criterion. Points holds the total score of the scorecard and
+	// the contribution of each criterion.
+	Result Result
+}
+
+// Score evaluates every criterion and maps the total score to an outcome.
+func (s *Scorecard[T]) Score(input T) (Score, error) {
+	return s.ScoreContext(context.Background(), input)
+}
+
+// ScoreContext is like Score but honors the context.
+func (s *Scorecard[T]) ScoreContext(ctx context.Context, input T) (Score, error) {
+	score := s.score(&evaluation{ctx: ctx}, input)
+	return score, score.Result.Error
+}
+
+// score evaluates the criteria and builds the detailed result. Detailed
+// evaluation reports the result of the scorecard, with the criteria as its
+// children and the total score as its points.
+func (s *Scorecard[T]) score(ev *evaluation, input T) Score {
+	start := time.Now()
+	total, children, err := s.evaluateItems(ev, input)
+
+	score := Score{Total: total, Outcome: s.fallback}
+	if err == nil {
+		score.Outcome, score.Reached = s.outcome(total)
+	}
+
+	score.Result = Result{
+		Satisfied: score.Reached,
+		RuleName:  s.name,
+		Duration:  time.Since(start),
+		Error:     err,
+		Children:  children,
+		Points:    total,
+	}
+	return score
+}
+
+// outcome maps a total score to the outcome of the highest threshold
+// reached, or the default outcome.
+func (s *Scorecard[T]) outcome(total int) (string, bool) {
+	for _, threshold := range s.thresholds {
+		if total >= threshold.minimum {
+			return threshold.outcome, true
+		}
+	}
+	return s.fallback, false
+}
+
+// total evaluates every criterion and returns the total score, without
+// building results.
+func (s *Scorecard[T]) total(ctx context.Context, input T) (int, error) {
+	total := 0
+
+	for _, item := range s.items {
+		switch {
+		case item.rule != nil:
+			if !inEffect(ctx, item.rule) {
+				continue
+			}
+			satisfied, err := EvaluateContext(ctx, item.rule, input)
+			if err != nil {
+				return 0, fmt.Errorf("evaluating scorecard %q: %w", s.name, err)
+			}
+			if satisfied {
+				total += item.points
+			}
+		case item.value != nil:
+			if band, ok := item.band(item.value(input)); ok {
+				total += band.Points
+			}
+		default:
+			return 0, fmt.Errorf("evaluating scorecard %q: %w", s.name, ErrNilRule)
+		}
+	}
+
+	return total, nil
+}
+
+// evaluateItems evaluates every criterion and returns the total score and
+// a result per criterion.
+func (s *Scorecard[T]) evaluateItems(ev *evaluation, input T) (int, []Result, error) {
+	total := 0
+	results := make([]Result, 0, len(s.items))
+
+	for _, item := range s.items {
+		var result Result
+		if item.rule != nil {
+			result = evaluateRuleDetailed(ev, item.rule, input)
+			if result.Satisfied && !result.Inactive && result.Error == nil {
+				result.Points = item.points
+			}
+		} else if item.value != nil {
+			result = item.evaluateBands(input)
+		} else {
+			result = Result{RuleName: "unnamed", Error: ErrNilRule}
+		}
+		results = append(results, result)
+
+		if result.Error != nil {
+			return 0, results, fmt.Errorf("evaluating scorecard %q: %w", s.name, result.Error)
+		}
+		total += result.Points
+	}
+
+	return total, results, nil
+}
+
+// evaluateBands scores the value of a band criterion.
+func (item scoreItem[T]) evaluateBands(input T) Result {
+	start := time.Now()
+	value := item.value(input)
+
+	result := Result{RuleName: fmt.Sprintf("%s = %g", item.name, value)}
+	if band, ok := item.band(value); ok {
+		result.Satisfied = true
+		result.Points = band.Points
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// band returns the first band that contains the value.
+func (item scoreItem[T]) band(value float64) (ScoreBand, bool) {
+	for _, band := range item.bands {
+		if band.contains(value) {
+			return band, true
+		}
+	}
+	return ScoreBand{}, false
+}
+
+// layout describes the scorecard for documentation.
+func (s *Scorecard[T]) layout() *scorecardLayout {
+	layout := &scorecardLayout{}
+
+	for _, item := range s.items {
+		switch {
+		case item.rule != nil:
+			layout.Criteria = append(layout.Criteria, scoreCriterionLayout{
+				Name:   item.rule.Name(),
+				Points: item.points,
+			})
+		case item.value != nil:
+			for _, band := range item.bands {
+				layout.Criteria = append(layout.Criteria, scoreCriterionLayout{
+					Name:   fmt.Sprintf("%s: %s", item.name, band),
+					Points: band.Points,
+				})
+			}
+		}
+	}
+
+	for _, threshold := range s.thresholds {
+		layout.Thresholds = append(layout.Thresholds, scoreThresholdLayout{
+			Score:   fmt.Sprintf("at least %d", threshold.minimum),
+			Outcome: threshold.outcome,
+		})
+	}
+	if s.fallback != "" {
+		below := "any score"
+		if len(s.thresholds) > 0 {
+			below = fmt.Sprintf("below %d", s.thresholds[len(s.thresholds)-1].minimum)
+		}
+		layout.Thresholds = append(layout.Thresholds, scoreThresholdLayout{
+			Score:   below,
+			Outcome: s.fallback,
+		})
+	}
+
+	return layout
+}
+
+// scorecardLayout describes a scorecard for documentation.
+type scorecardLayout struct {
+	Criteria   []scoreCriterionLayout
+	Thresholds []scoreThresholdLayout
+}
+
+// scoreCriterionLayout describes the points of a scorecard criterion.
+type scoreCriterionLayout struct {
+	Name   string
+	Points int
+}
+
+// scoreThresholdLayout describes a threshold of a scorecard.
+type scoreThresholdLayout struct {
+	Score   string
+	Outcome string
+}
+
+// formatPoints formats points with a sign, e.g. "+50" or "-100".
+func formatPoints(points int) string {
+	return fmt.Sprintf("%+d", points)
+}
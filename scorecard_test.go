@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// applicant is the input of the credit scorecards.
+type applicant struct {
+	stableIncome bool
+	defaulted    bool
+	debtRatio    float64
+}
+
+var (
+	hasStableIncome = New("has stable income", func(a applicant) (bool, error) {
+		return a.stableIncome, nil
+	})
+	hasDefaulted = New("has defaulted", func(a applicant) (bool, error) {
+		return a.defaulted, nil
+	})
+)
+
+func newCreditScorecard() *Scorecard[applicant] {
+	return NewScorecard[applicant]("credit score").
+		Add(hasStableIncome, 50).
+		Add(hasDefaulted, -100).
+		AddBands("debt ratio", func(a applicant) float64 { return a.debtRatio },
+			ScoreBand{Min: math.Inf(-1), Max: 0.3, Points: 40},
+			ScoreBand{Min: 0.3, Max: 0.5, Points: 10},
+		).
+		Threshold(50, "review").
+		Threshold(80, "approve").
+		Default("decline")
+}
+
+func TestScorecard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       applicant
+		wantTotal   int
+		wantOutcome string
+	}{
+		{name: "approve", input: applicant{stableIncome: true, debtRatio: 0.2}, wantTotal: 90, wantOutcome: "approve"},
+		{name: "review", input: applicant{stableIncome: true, debtRatio: 0.4}, wantTotal: 60, wantOutcome: "review"},
+		{name: "decline", input: applicant{stableIncome: true, defaulted: true, debtRatio: 0.2}, wantTotal: -10, wantOutcome: "decline"},
+		{name: "outside all bands", input: applicant{stableIncome: true, debtRatio: 0.9}, wantTotal: 50, wantOutcome: "review"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scorecard := newCreditScorecard()
+			score, err := scorecard.Score(tt.input)
+			if err != nil {
+				t.Fatalf("Score() error = %v", err)
+			}
+			if score.Total != tt.wantTotal || score.Outcome != tt.wantOutcome {
+				t.Errorf("Score() = %d %q, want %d %q", score.Total, score.Outcome, tt.wantTotal, tt.wantOutcome)
+			}
+
+			// The scorecard is satisfied when a threshold is reached
+			satisfied, err := scorecard.Evaluate(tt.input)
+			if err != nil || satisfied != (tt.wantOutcome != "decline") {
+				t.Errorf("Evaluate() = %v, %v", satisfied, err)
+			}
+		})
+	}
+}
+
+func TestScorecard_Allocations(t *testing.T) {
+	// Evaluate only needs the total score, not the results of the criteria
+	scorecard := newCreditScorecard()
+	input := applicant{stableIncome: true, debtRatio: 0.2}
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = scorecard.Evaluate(input) }); allocs != 0 {
+		t.Errorf("Evaluate() allocations = %v, want 0", allocs)
+	}
+}
+
+func TestScorecard_Contributions(t *testing.T) {
+	t.Parallel()
+
+	score, err := newCreditScorecard().Score(applicant{stableIncome: true, debtRatio: 0.4})
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+
+	children := score.Result.Children
+	if len(children) != 3 {
+		t.Fatalf("Expected 3 criteria, got %v", score.Result)
+	}
+	for i, want := range []int{50, 0, 10} {
+		if children[i].Points != want {
+			t.Errorf("Criterion %q points = %d, want %d", children[i].RuleName, children[i].Points, want)
+		}
+	}
+	if children[2].RuleName != "debt ratio = 0.4" {
+		t.Errorf("Unexpected band criterion name %q", children[2].RuleName)
+	}
+	if score.Result.Points != 60 || !strings.Contains(score.Result.String(), "has stable income (took") {
+		t.Errorf("Unexpected result:\n%s", score.Result)
+	}
+	if !strings.Contains(score.Result.String(), "[+50 points]") {
+		t.Errorf("Expected contributions in output, got:\n%s", score.Result)
+	}
+
+	// Evaluators report the criteria as children and the total as well
+	result := NewEvaluator[applicant](newCreditScorecard()).EvaluateDetailed(applicant{stableIncome: true})
+	if len(result.Children) != 3 || result.Children[0].Points != 50 || result.Points != 90 {
+		t.Errorf("Unexpected evaluator result: %v", result)
+	}
+	nested := NewEvaluator(And("approval", Rule[applicant](newCreditScorecard()))).EvaluateDetailed(applicant{stableIncome: true})
+	if len(nested.Children) != 1 || nested.Children[0].Points != 90 {
+		t.Errorf("Unexpected nested evaluator result: %v", nested)
+	}
+}
+
+func TestScorecard_EffectiveAndErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rules not in effect award no points", func(t *testing.T) {
+		t.Parallel()
+
+		scorecard := NewScorecard[applicant]("score").
+			Add(Effective(hasStableIncome, effectiveJul1, time.Time{}), 50)
+
+		ctx := AsOf(context.Background(), effectiveJan1)
+		score, err := scorecard.ScoreContext(ctx, applicant{stableIncome: true})
+		if err != nil || score.Total != 0 {
+			t.Errorf("ScoreContext() = %d, %v; want 0, nil", score.Total, err)
+		}
+		scorecard.Threshold(50, "approve")
+		if satisfied, err := scorecard.EvaluateContext(ctx, applicant{stableIncome: true}); err != nil || satisfied {
+			t.Errorf("EvaluateContext() = %v, %v; want false, nil", satisfied, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		failing := New("failing", func(a applicant) (bool, error) {
+			return false, ErrEvaluationFailed
+		})
+		_, err := NewScorecard[applicant]("score").Add(failing, 10).Score(applicant{})
+		if !errors.Is(err, ErrEvaluationFailed) || !strings.Contains(err.Error(), `scorecard "score"`) {
+			t.Errorf("Expected a wrapped evaluation error, got %v", err)
+		}
+
+		_, err = NewScorecard[applicant]("score").Add(failing, 10).Evaluate(applicant{})
+		if !errors.Is(err, ErrEvaluationFailed) || !strings.Contains(err.Error(), `scorecard "score"`) {
+			t.Errorf("Expected a wrapped evaluation error, got %v", err)
+		}
+
+		_, err = NewScorecard[applicant]("score").Add(nil, 10).Score(applicant{})
+		if !errors.Is(err, ErrNilRule) {
+			t.Errorf("Expected ErrNilRule, got %v", err)
+		}
+		_, err = NewScorecard[applicant]("score").Add(nil, 10).Evaluate(applicant{})
+		if !errors.Is(err, ErrNilRule) {
+			t.Errorf("Expected ErrNilRule, got %v", err)
+		}
+	})
+}
+
+func TestScorecardDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	_ = Register(newCreditScorecard(), WithDomain(TestUserDomain))
+
+	md, err := GenerateMarkdown(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"### credit score (SCORECARD)",
+		"| has defaulted | -100 |",
+		"| debt ratio: below 0.3 | +40 |",
+		"| debt ratio: 0.3 to 0.5 | +10 |",
+		"| at least 80 | approve |",
+		"| below 50 | decline |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(DocumentOptions{})
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	if !strings.Contains(htmlDoc, "<td>has stable income</td><td>+50</td>") ||
+		!strings.Contains(htmlDoc, "<td>at least 50</td><td>review</td>") {
+		t.Error("HTML should contain the criteria and thresholds")
+	}
+}
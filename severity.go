@@ -0,0 +1,127 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+)
+
+// Severity indicates how a failed rule affects the outcome of its parent.
+type Severity int
+
+const (
+	// SeverityError marks a rule whose failure fails its parent. This is the
+	// default for all rules.
+	SeverityError Severity = iota
+	// SeverityWarning marks a rule whose failure is reported but does not
+	// fail an enclosing AND rule.
+	SeverityWarning
+	// SeverityInfo marks a purely informational rule whose failure does not
+	// fail an enclosing AND rule.
+	SeverityInfo
+)
+
+// String returns the string representation of a Severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// isBlocking reports whether a failure at this severity fails an enclosing
+// AND rule.
+func (s Severity) isBlocking() bool {
+	return s == SeverityError
+}
+
+// severityRule decorates a rule with a severity level.
+type severityRule[T any] struct {
+	rule     Rule[T]
+	severity Severity
+}
+
+// WithSeverity wraps a rule with a severity level. And, AllOf and ParallelAnd
+// treat failed children below SeverityError as non-blocking: the failure is
+// reported in detailed results (see Result.Warnings) but the parent can
+// still be satisfied. The wrapped rule keeps its name and structure.
+//
+// Example:
+//
+//	unusualCountry := rules.WithSeverity(
+//	    rules.New("usual shipping country", func(o Order) (bool, error) {
+//	        return o.Country == "US" || o.Country == "CA", nil
+//	    }),
+//	    rules.SeverityWarning,
+//	)
+//
+//	rule := rules.And("order validation", minimumAmount, unusualCountry)
+func WithSeverity[T any](rule Rule[T], severity Severity) Rule[T] {
+	return &severityRule[T]{
+		rule:     rule,
+		severity: severity,
+	}
+}
+
+func (r *severityRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *severityRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if r.rule == nil {
+		return false, fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule)
+	}
+
+	return EvaluateContext(ctx, r.rule, input)
+}
+
+func (r *severityRule[T]) Name() string {
+	if r.rule == nil {
+		return "unnamed"
+	}
+	return r.rule.Name()
+}
+
+// Severity returns the severity level of the rule.
+func (r *severityRule[T]) Severity() Severity {
+	return r.severity
+}
+
+// Unwrap returns the wrapped rule.
+func (r *severityRule[T]) Unwrap() Rule[T] {
+	return r.rule
+}
+
+// unwrapRule returns the wrapped rule for introspection.
+func (r *severityRule[T]) unwrapRule() any {
+	return r.rule
+}
+
+// explicitSeverity returns the severity set on a rule or on any rule it
+// wraps, and whether one was set at all.
+func explicitSeverity(rule any) (Severity, bool) {
+	for rule != nil {
+		if s, ok := rule.(interface{ Severity() Severity }); ok {
+			return s.Severity(), true
+		}
+
+		wrapper, ok := rule.(wrappingRule)
+		if !ok {
+			break
+		}
+		rule = wrapper.unwrapRule()
+	}
+
+	return SeverityError, false
+}
+
+// severityOf returns the effective severity of a rule.
+func severityOf(rule any) Severity {
+	severity, _ := explicitSeverity(rule)
+	return severity
+}
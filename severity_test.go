@@ -0,0 +1,246 @@
+package rules
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithSeverity(t *testing.T) {
+	t.Parallel()
+
+	minimum := New("minimum value", func(input testInput) (bool, error) {
+		return input.value >= 10, nil
+	})
+	unusual := WithSeverity(
+		New("usual value", func(input testInput) (bool, error) {
+			return input.value < 1000, nil
+		}),
+		SeverityWarning,
+	)
+	note := WithSeverity(
+		New("valid", func(input testInput) (bool, error) {
+			return input.valid, nil
+		}),
+		SeverityInfo,
+	)
+
+	rule := And("order validation", minimum, unusual, note)
+
+	tests := []struct {
+		name  string
+		rule  Rule[testInput]
+		input testInput
+		want  bool
+	}{
+		{
+			name:  "failed warning does not block AND",
+			rule:  rule,
+			input: testInput{value: 5000},
+			want:  true,
+		},
+		{
+			name:  "failed error still blocks AND",
+			rule:  rule,
+			input: testInput{value: 5},
+			want:  false,
+		},
+		{
+			name:  "failed warning does not block parallel AND",
+			rule:  ParallelAnd("parallel validation", 2, minimum, unusual),
+			input: testInput{value: 5000},
+			want:  true,
+		},
+		{
+			name:  "warning is still false inside OR",
+			rule:  Or("either", unusual, note),
+			input: testInput{value: 5000},
+			want:  false,
+		},
+		{
+			name:  "top-level warning rule reports its outcome",
+			rule:  unusual,
+			input: testInput{value: 5000},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.rule.Evaluate(tt.input)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+
+			result := NewEvaluator(tt.rule).EvaluateDetailed(tt.input)
+			if result.Satisfied != tt.want {
+				t.Errorf("EvaluateDetailed().Satisfied = %v, want %v", result.Satisfied, tt.want)
+			}
+		})
+	}
+
+	if unusual.Name() != "usual value" {
+		t.Errorf("Name() = %q, want %q", unusual.Name(), "usual value")
+	}
+}
+
+func TestResultWarningsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	minimum := New("minimum value", func(input testInput) (bool, error) {
+		return input.value >= 10, nil
+	})
+	valid := New("valid", func(input testInput) (bool, error) {
+		return input.valid, nil
+	})
+	unusual := WithSeverity(
+		New("usual value", func(input testInput) (bool, error) {
+			return input.value < 1000, nil
+		}),
+		SeverityWarning,
+	)
+
+	rule := And("order validation", Or("minimum or valid", minimum, valid), unusual)
+	evaluator := NewEvaluator(rule)
+
+	t.Run("satisfied tree with warning", func(t *testing.T) {
+		t.Parallel()
+
+		result := evaluator.EvaluateDetailed(testInput{value: 5000})
+		if !result.Satisfied {
+			t.Fatal("Expected rule to be satisfied")
+		}
+
+		warnings := result.Warnings()
+		if len(warnings) != 1 || warnings[0].RuleName != "usual value" {
+			t.Errorf("Expected warning for %q, got %v", "usual value", warnings)
+		}
+		if errs := result.Errors(); len(errs) != 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if !strings.Contains(result.String(), "usual value (took") ||
+			!strings.Contains(result.String(), "[warning]") {
+			t.Errorf("Expected warning marker in output, got:\n%s", result.String())
+		}
+	})
+
+	t.Run("failed tree reports error leaves", func(t *testing.T) {
+		t.Parallel()
+
+		result := evaluator.EvaluateDetailed(testInput{value: 5})
+		if result.Satisfied {
+			t.Fatal("Expected rule to not be satisfied")
+		}
+
+		errs := result.Errors()
+		if len(errs) != 2 || errs[0].RuleName != "minimum value" || errs[1].RuleName != "valid" {
+			t.Errorf("Expected errors for both OR children, got %v", errs)
+		}
+		if warnings := result.Warnings(); len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("negation reports the NOT rule itself", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(Not("not valid", valid)).EvaluateDetailed(testInput{valid: true})
+
+		errs := result.Errors()
+		if len(errs) != 1 || errs[0].RuleName != "not valid" {
+			t.Errorf("Expected error for %q, got %v", "not valid", errs)
+		}
+	})
+}
+
+func TestSeverityString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[Severity]string{
+		SeverityError:   "error",
+		SeverityWarning: "warning",
+		SeverityInfo:    "info",
+		Severity(99):    "unknown",
+	}
+
+	for severity, want := range tests {
+		if got := severity.String(); got != want {
+			t.Errorf("Severity(%d).String() = %q, want %q", severity, got, want)
+		}
+	}
+}
+
+func TestSeverityDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	amount := NewWithDomain("amount check", TestOrderDomain, func(o TestOrder) (bool, error) {
+		return o.Amount >= 100, nil
+	})
+	country := WithSeverity(
+		NewWithDomain("usual country", TestOrderDomain, func(o TestOrder) (bool, error) {
+			return o.Country == "US", nil
+		}),
+		SeverityWarning,
+	)
+	_ = And("order validation", amount, country)
+
+	t.Run("markdown", func(t *testing.T) {
+		md, err := GenerateMarkdown(DocumentOptions{})
+		if err != nil {
+			t.Fatalf("GenerateMarkdown() error = %v", err)
+		}
+		if !strings.Contains(md, "**Severity**: warning") {
+			t.Errorf("Markdown should show the severity:\n%s", md)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		html, err := GenerateHTML(DocumentOptions{})
+		if err != nil {
+			t.Fatalf("GenerateHTML() error = %v", err)
+		}
+		if !strings.Contains(html, `<span class="severity-badge severity-warning">warning</span>`) {
+			t.Error("HTML should show a severity badge")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := GenerateJSON(DocumentOptions{})
+		if err != nil {
+			t.Fatalf("GenerateJSON() error = %v", err)
+		}
+
+		var doc JSONDocumentation
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+
+		found := false
+		for _, rule := range doc.Rules {
+			for _, child := range rule.Children {
+				if child.Name == "usual country" && child.Severity == "warning" {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Error("JSON should include the child's severity")
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		mermaid, err := GenerateMermaid(DocumentOptions{})
+		if err != nil {
+			t.Fatalf("GenerateMermaid() error = %v", err)
+		}
+		if !strings.Contains(mermaid, "-->|warning|") {
+			t.Errorf("Mermaid should label the edge to the warning rule:\n%s", mermaid)
+		}
+	})
+}
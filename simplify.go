@@ -0,0 +1,369 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNormalFormTooLarge is returned when converting a rule to conjunctive or
+// disjunctive normal form would produce more than MaxNormalFormClauses
+// clauses.
+var ErrNormalFormTooLarge = errors.New("normal form too large")
+
+// MaxNormalFormClauses limits the number of clauses ToCNF and ToDNF produce.
+// Normal forms can grow exponentially with the size of the rule.
+const MaxNormalFormClauses = 1024
+
+// Simplify returns an equivalent rule with a simpler structure. It
+//
+//   - flattens And and Or rules nested in a rule of the same kind
+//   - removes Always and Never children that do not affect the outcome and
+//     replaces rules whose outcome they decide by Always or Never
+//   - removes duplicate children and layers with a single child
+//   - pushes Not inward (De Morgan's laws) and removes double negations
+//   - rewrites NoneOf as an And of negations
+//
+// The leaves of the simplified rule are the leaves of the original rule, so
+// it can be evaluated and documented in its place. Rewritten composites keep
+// the names of the rules they replace; pushed-down negations are named
+// "not <name>". The top-level rule keeps its name.
+//
+// Rules whose children have a severity below SeverityError or a validity
+// window are only flattened, as negating them would change their outcome.
+// Other rules, such as mapped, collection, parallel and custom rules, are
+// kept as they are. Simplified rules are not registered.
+//
+// Children whose outcome cannot affect the result may no longer be
+// evaluated, so their errors are not reported.
+func Simplify[T any](rule Rule[T]) Rule[T] {
+	simplified := simplifyRule(rule)
+	if simplified == nil || rule == nil || simplified.Name() == rule.Name() {
+		return simplified
+	}
+
+	// Keep the name of the top-level rule when a layer was removed
+	switch r := simplified.(type) {
+	case *andRule[T]:
+		return newAndRule(rule.Name(), r.rules)
+	case *orRule[T]:
+		return newOrRule(rule.Name(), r.rules)
+	case *constantRule[T]:
+		return &constantRule[T]{name: rule.Name(), value: r.value}
+	}
+	if !isPlainRule(simplified) {
+		// An And would ignore the failure of a non-blocking rule
+		return rule
+	}
+	return newAndRule(rule.Name(), []Rule[T]{simplified})
+}
+
+// ToCNF returns an equivalent rule in conjunctive normal form: an And of Or
+// rules whose children are leaves or negated leaves. The rule is simplified
+// first (see Simplify). Rules that Simplify keeps as they are become leaves.
+// It returns ErrNormalFormTooLarge if the result would have more than
+// MaxNormalFormClauses clauses.
+func ToCNF[T any](rule Rule[T]) (Rule[T], error) {
+	return toNormalForm(rule, RuleTypeAnd)
+}
+
+// ToDNF returns an equivalent rule in disjunctive normal form: an Or of And
+// rules whose children are leaves or negated leaves. The rule is simplified
+// first (see Simplify). Rules that Simplify keeps as they are become leaves.
+// It returns ErrNormalFormTooLarge if the result would have more than
+// MaxNormalFormClauses clauses.
+func ToDNF[T any](rule Rule[T]) (Rule[T], error) {
+	return toNormalForm(rule, RuleTypeOr)
+}
+
+// simplifyRule simplifies a rule and its descendants.
+func simplifyRule[T any](rule Rule[T]) Rule[T] {
+	switch r := rule.(type) {
+	case *andRule[T]:
+		if len(r.rules) == 0 {
+			return r
+		}
+		return simplifiedAnd(r.name, simplifyRules(r.rules))
+	case *orRule[T]:
+		if len(r.rules) == 0 {
+			return r
+		}
+		return simplifiedOr(r.name, simplifyRules(r.rules))
+	case *notRule[T]:
+		if r.rule == nil {
+			return r
+		}
+		return negate(r.name, simplifyRule(r.rule))
+	case *quantifierRule[T]:
+		children := simplifyRules(r.rules)
+		if r.kind == RuleTypeNoneOf && len(children) > 0 && !containsNilRule(children) {
+			// None of the children is satisfied exactly if their Or is not
+			return negate(r.name, simplifiedOr(r.name, children))
+		}
+		return &quantifierRule[T]{name: r.name, kind: r.kind, n: r.n, rules: children}
+	default:
+		return rule
+	}
+}
+
+// simplifyRules simplifies each of the rules.
+func simplifyRules[T any](rules []Rule[T]) []Rule[T] {
+	simplified := make([]Rule[T], len(rules))
+	for i, rule := range rules {
+		simplified[i] = simplifyRule(rule)
+	}
+	return simplified
+}
+
+// simplifiedAnd builds an And of simplified children.
+func simplifiedAnd[T any](name string, children []Rule[T]) Rule[T] {
+	if containsNilRule(children) {
+		return newAndRule(name, children)
+	}
+
+	var flattened []Rule[T]
+	for _, child := range children {
+		switch c := child.(type) {
+		case *andRule[T]:
+			if len(c.rules) > 0 && !containsNilRule(c.rules) {
+				flattened = append(flattened, c.rules...)
+				continue
+			}
+		case *constantRule[T]:
+			if !c.value {
+				return &constantRule[T]{name: name, value: false}
+			}
+			continue
+		}
+		flattened = append(flattened, child)
+	}
+
+	return collapse(name, dedupeRules(flattened), true)
+}
+
+// simplifiedOr builds an Or of simplified children.
+func simplifiedOr[T any](name string, children []Rule[T]) Rule[T] {
+	if containsNilRule(children) {
+		return newOrRule(name, children)
+	}
+
+	var flattened []Rule[T]
+	for _, child := range children {
+		switch c := child.(type) {
+		case *orRule[T]:
+			if len(c.rules) > 0 && !containsNilRule(c.rules) {
+				flattened = append(flattened, c.rules...)
+				continue
+			}
+		case *constantRule[T]:
+			if c.value {
+				return &constantRule[T]{name: name, value: true}
+			}
+			continue
+		}
+		flattened = append(flattened, child)
+	}
+
+	return collapse(name, dedupeRules(flattened), false)
+}
+
+// collapse builds an And (conjunctive) or Or of the children, replacing it
+// by its identity element if there are none and by its child if there is a
+// single one that can stand on its own.
+func collapse[T any](name string, children []Rule[T], conjunctive bool) Rule[T] {
+	switch {
+	case len(children) == 0:
+		return &constantRule[T]{name: name, value: conjunctive}
+	case len(children) == 1 && isPlainRule(children[0]):
+		return children[0]
+	case conjunctive:
+		return newAndRule(name, children)
+	default:
+		return newOrRule(name, children)
+	}
+}
+
+// negate returns the negation of a simplified rule, pushing it inward where
+// that keeps the outcome.
+func negate[T any](name string, rule Rule[T]) Rule[T] {
+	switch r := rule.(type) {
+	case *constantRule[T]:
+		return &constantRule[T]{name: name, value: !r.value}
+	case *notRule[T]:
+		// Negation ignores the severity and validity window of its child,
+		// while the parent of the unwrapped child would not
+		if isPlainRule(r.rule) {
+			return r.rule
+		}
+	case *andRule[T]:
+		if len(r.rules) > 0 && allPlainRules(r.rules) {
+			return simplifiedOr(name, negateRules(r.rules))
+		}
+	case *orRule[T]:
+		if len(r.rules) > 0 && allPlainRules(r.rules) {
+			return simplifiedAnd(name, negateRules(r.rules))
+		}
+	}
+
+	return newNotRule(name, rule)
+}
+
+// negateRules negates each of the rules.
+func negateRules[T any](rules []Rule[T]) []Rule[T] {
+	negated := make([]Rule[T], len(rules))
+	for i, rule := range rules {
+		negated[i] = negate("not "+rule.Name(), rule)
+	}
+	return negated
+}
+
+// toNormalForm converts a rule to conjunctive (outer And) or disjunctive
+// (outer Or) normal form.
+func toNormalForm[T any](rule Rule[T], outer RuleType) (Rule[T], error) {
+	simplified := Simplify(rule)
+	if simplified == nil {
+		return nil, ErrNilRule
+	}
+	if _, ok := simplified.(*constantRule[T]); ok {
+		return simplified, nil
+	}
+
+	clauses, err := normalFormClauses(simplified, outer)
+	if err != nil {
+		return nil, fmt.Errorf("converting rule %q to normal form: %w", rule.Name(), err)
+	}
+
+	conjunctive := outer == RuleTypeAnd
+	joiner := " or "
+	if !conjunctive {
+		joiner = " and "
+	}
+
+	built := make([]Rule[T], len(clauses))
+	for i, literals := range clauses {
+		literals = dedupeRules(literals)
+		names := make([]string, len(literals))
+		for j, literal := range literals {
+			names[j] = literal.Name()
+		}
+		// Clauses combine their literals with the inner operator
+		built[i] = collapse(strings.Join(names, joiner), literals, !conjunctive)
+	}
+
+	if conjunctive {
+		return newAndRule(simplified.Name(), built), nil
+	}
+	return newOrRule(simplified.Name(), built), nil
+}
+
+// normalFormClauses returns the clauses of a simplified rule in the normal
+// form whose outer operator is outer. Each clause lists the literals the
+// inner operator combines.
+func normalFormClauses[T any](rule Rule[T], outer RuleType) ([][]Rule[T], error) {
+	kind, children := plainComposite(rule)
+
+	switch {
+	case kind == outer:
+		// The outer operator concatenates the clauses of its children
+		var clauses [][]Rule[T]
+		for _, child := range children {
+			childClauses, err := normalFormClauses(child, outer)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, childClauses...)
+			if len(clauses) > MaxNormalFormClauses {
+				return nil, ErrNormalFormTooLarge
+			}
+		}
+		return clauses, nil
+	case kind != RuleTypeUnknown:
+		// The inner operator distributes over the clauses of its children
+		clauses := [][]Rule[T]{nil}
+		for _, child := range children {
+			childClauses, err := normalFormClauses(child, outer)
+			if err != nil {
+				return nil, err
+			}
+			if len(clauses)*len(childClauses) > MaxNormalFormClauses {
+				return nil, ErrNormalFormTooLarge
+			}
+
+			combined := make([][]Rule[T], 0, len(clauses)*len(childClauses))
+			for _, clause := range clauses {
+				for _, childClause := range childClauses {
+					literals := append(append([]Rule[T]{}, clause...), childClause...)
+					combined = append(combined, literals)
+				}
+			}
+			clauses = combined
+		}
+		return clauses, nil
+	default:
+		return [][]Rule[T]{{rule}}, nil
+	}
+}
+
+// plainComposite returns the kind and children of an And or Or rule whose
+// children can be regrouped without changing its outcome, and
+// RuleTypeUnknown for all other rules.
+func plainComposite[T any](rule Rule[T]) (RuleType, []Rule[T]) {
+	switch r := rule.(type) {
+	case *andRule[T]:
+		if len(r.rules) > 0 && allPlainRules(r.rules) {
+			return RuleTypeAnd, r.rules
+		}
+	case *orRule[T]:
+		if len(r.rules) > 0 && allPlainRules(r.rules) {
+			return RuleTypeOr, r.rules
+		}
+	}
+	return RuleTypeUnknown, nil
+}
+
+// isPlainRule reports whether a rule behaves the same inside and outside
+// an And or Or: it is not nil, blocks when it fails and has no validity
+// window.
+func isPlainRule(rule any) bool {
+	if isNilRule(rule) || !severityOf(rule).isBlocking() {
+		return false
+	}
+	_, _, windowed := effectiveWindow(rule)
+	return !windowed
+}
+
+// allPlainRules reports whether all rules are plain (see isPlainRule).
+func allPlainRules[T any](rules []Rule[T]) bool {
+	for _, rule := range rules {
+		if !isPlainRule(rule) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsNilRule reports whether any of the rules is nil.
+func containsNilRule[T any](rules []Rule[T]) bool {
+	for _, rule := range rules {
+		if isNilRule(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupeRules removes repeated occurrences of the same rule instance.
+func dedupeRules[T any](rules []Rule[T]) []Rule[T] {
+	seen := make(map[ruleIdentity]bool, len(rules))
+	deduped := make([]Rule[T], 0, len(rules))
+	for _, rule := range rules {
+		if id, ok := identify(rule); ok {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		deduped = append(deduped, rule)
+	}
+	return deduped
+}
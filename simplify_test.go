@@ -0,0 +1,225 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// bitRules returns n rules, the i-th of which is satisfied if bit i of the
+// input value is set.
+func bitRules(n int) []Rule[testInput] {
+	rules := make([]Rule[testInput], n)
+	for i := range rules {
+		bit := i
+		rules[i] = New(fmt.Sprintf("bit %d", bit), func(input testInput) (bool, error) {
+			return input.value&(1<<bit) != 0, nil
+		})
+	}
+	return rules
+}
+
+// assertEquivalent checks that two rules over n bit rules have the same
+// outcome for every input.
+func assertEquivalent(t *testing.T, n int, want, got Rule[testInput]) {
+	t.Helper()
+
+	for value := 0; value < 1<<n; value++ {
+		input := testInput{value: value}
+		wantSatisfied, wantErr := want.Evaluate(input)
+		gotSatisfied, gotErr := got.Evaluate(input)
+		if wantErr != nil || gotErr != nil {
+			t.Fatalf("Evaluate(%b) errors = %v, %v", value, wantErr, gotErr)
+		}
+		if wantSatisfied != gotSatisfied {
+			t.Fatalf("Evaluate(%b) = %v, want %v", value, gotSatisfied, wantSatisfied)
+		}
+	}
+}
+
+// equivalenceCases returns rules over the bit rules b that exercise every
+// rewrite.
+func equivalenceCases(b []Rule[testInput]) map[string]Rule[testInput] {
+	return map[string]Rule[testInput]{
+		"nested and": And("root", b[0], And("inner", b[1], AllOf("all", b[2], b[3]))),
+		"nested or":  Or("root", b[0], AnyOf("any", b[1], Or("inner", b[2]))),
+		"constants": Or("root",
+			And("with always", b[0], Always[testInput]("always")),
+			And("with never", b[1], Never[testInput]("never")),
+		),
+		"de morgan":       Not("root", And("both", b[0], Or("either", b[1], b[2]))),
+		"double negation": And("root", Not("not not", Not("not", b[0])), b[1]),
+		"none of":         NoneOf("root", b[0], And("both", b[1], b[2])),
+		"mixed": Or("root",
+			And("a", b[0], Not("not b", Or("b", b[1], b[2]))),
+			And("c", b[3], b[1], Not("not d", Not("d", b[2]))),
+		),
+		"duplicates":                            And("root", b[0], b[0], Or("either", b[1], b[1])),
+		"quantifier":                            AtLeast("root", 2, b[0], And("both", b[1], Always[testInput]("always")), b[2]),
+		"non-blocking child":                    Not("root", And("both", b[0], WithSeverity(b[1], SeverityWarning))),
+		"double negation of non-blocking child": Not("root", Not("inner", WithSeverity(b[0], SeverityWarning))),
+		"nested double negation of non-blocking child": And("root", b[1],
+			Not("not not", Not("not", WithSeverity(b[0], SeverityWarning))),
+		),
+	}
+}
+
+func TestSimplify_Equivalence(t *testing.T) {
+	t.Parallel()
+
+	b := bitRules(4)
+	for name, rule := range equivalenceCases(b) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			simplified := Simplify(rule)
+			assertEquivalent(t, len(b), rule, simplified)
+			if simplified.Name() != rule.Name() {
+				t.Errorf("Simplify() name = %q, want %q", simplified.Name(), rule.Name())
+			}
+
+			cnf, err := ToCNF(rule)
+			if err != nil {
+				t.Fatalf("ToCNF() error = %v", err)
+			}
+			assertEquivalent(t, len(b), rule, cnf)
+
+			dnf, err := ToDNF(rule)
+			if err != nil {
+				t.Fatalf("ToDNF() error = %v", err)
+			}
+			assertEquivalent(t, len(b), rule, dnf)
+		})
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	t.Parallel()
+
+	b := bitRules(3)
+
+	t.Run("flattens nested composites", func(t *testing.T) {
+		t.Parallel()
+
+		simplified := Simplify(And("root", b[0], And("inner", b[1], And("innermost", b[2]))))
+		children := getChildren(simplified)
+		if len(children) != 3 || children[2] != any(b[2]) {
+			t.Errorf("Expected the three leaves, got %v", children)
+		}
+	})
+
+	t.Run("removes constants", func(t *testing.T) {
+		t.Parallel()
+
+		simplified := Simplify(Or("root", b[0], Never[testInput]("never"), And("inner", b[1], Always[testInput]("always"))))
+		children := getChildren(simplified)
+		if len(children) != 2 || children[0] != any(b[0]) || children[1] != any(b[1]) {
+			t.Errorf("Expected the two leaves, got %v", children)
+		}
+
+		constant := Simplify(And("root", b[0], Or("inner", Never[testInput]("never"))))
+		if c, ok := constant.(interface{ Constant() bool }); !ok || c.Constant() {
+			t.Errorf("Expected Never, got %T", constant)
+		}
+		if constant.Name() != "root" {
+			t.Errorf("Expected the constant to keep the name, got %q", constant.Name())
+		}
+	})
+
+	t.Run("pushes negations to the leaves", func(t *testing.T) {
+		t.Parallel()
+
+		simplified := Simplify(Not("root", Or("either", b[0], Not("not b1", b[1]))))
+		if getRuleType(simplified) != RuleTypeAnd {
+			t.Fatalf("Expected an And rule, got %s", getRuleType(simplified))
+		}
+		children := getChildren(simplified)
+		if len(children) != 2 || getRuleName(children[0]) != "not bit 0" || children[1] != any(b[1]) {
+			t.Errorf("Unexpected children: %v", children)
+		}
+	})
+
+	t.Run("keeps rules with non-blocking children", func(t *testing.T) {
+		t.Parallel()
+
+		warning := WithSeverity(b[1], SeverityWarning)
+		simplified := Simplify(Not("root", And("both", b[0], warning)))
+		if getRuleType(simplified) != RuleTypeNot {
+			t.Errorf("Expected the negation to stay in place, got %s", getRuleType(simplified))
+		}
+	})
+
+	t.Run("keeps rules with effective-dated children", func(t *testing.T) {
+		t.Parallel()
+
+		expired := Effective(b[1], time.Time{}, effectiveJan1)
+		rule := Not("root", Or("either", b[0], expired))
+		if getRuleType(Simplify(rule)) != RuleTypeNot {
+			t.Errorf("Expected the negation to stay in place")
+		}
+	})
+
+	t.Run("keeps empty composites", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Simplify(Or[testInput]("empty")).Evaluate(testInput{})
+		if !errors.Is(err, ErrEmptyRules) {
+			t.Errorf("Expected ErrEmptyRules, got %v", err)
+		}
+	})
+
+	t.Run("nil rule", func(t *testing.T) {
+		t.Parallel()
+
+		if Simplify[testInput](nil) != nil {
+			t.Error("Expected nil")
+		}
+		if _, err := ToCNF[testInput](nil); !errors.Is(err, ErrNilRule) {
+			t.Errorf("Expected ErrNilRule, got %v", err)
+		}
+	})
+}
+
+func TestNormalForms(t *testing.T) {
+	t.Parallel()
+
+	b := bitRules(4)
+	// (b0 and b1) or (b2 and b3)
+	rule := Or("root", And("first", b[0], b[1]), And("second", b[2], b[3]))
+
+	cnf, err := ToCNF(rule)
+	if err != nil {
+		t.Fatalf("ToCNF() error = %v", err)
+	}
+	clauses := getChildren(cnf)
+	if getRuleType(cnf) != RuleTypeAnd || len(clauses) != 4 {
+		t.Fatalf("Expected an And of 4 clauses, got %s with %d", getRuleType(cnf), len(clauses))
+	}
+	if name := getRuleName(clauses[0]); name != "bit 0 or bit 2" {
+		t.Errorf("Unexpected clause name %q", name)
+	}
+
+	dnf, err := ToDNF(rule)
+	if err != nil {
+		t.Fatalf("ToDNF() error = %v", err)
+	}
+	if getRuleType(dnf) != RuleTypeOr || len(getChildren(dnf)) != 2 {
+		t.Errorf("Expected an Or of 2 clauses, got %s with %d", getRuleType(dnf), len(getChildren(dnf)))
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		// An Or of 11 Ands of 2 leaves has 2^11 CNF clauses
+		leaves := bitRules(22)
+		terms := make([]Rule[testInput], 11)
+		for i := range terms {
+			terms[i] = And(fmt.Sprintf("term %d", i), leaves[2*i], leaves[2*i+1])
+		}
+
+		if _, err := ToCNF(Or("large", terms...)); !errors.Is(err, ErrNormalFormTooLarge) {
+			t.Errorf("Expected ErrNormalFormTooLarge, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,453 @@
+package rules
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTruthTableTooLarge is returned when a truth table would have more rows
+// than allowed by TruthTableOptions.MaxRows.
+var ErrTruthTableTooLarge = errors.New("truth table too large")
+
+// DefaultMaxTruthTableRows limits the number of rows of truth tables unless
+// TruthTableOptions.MaxRows is set.
+const DefaultMaxTruthTableRows = 256
+
+// TruthTableOptions configures NewTruthTable.
+type TruthTableOptions struct {
+	// MaxRows limits the number of rows (0 = DefaultMaxTruthTableRows).
+	MaxRows int
+
+	// Prune collapses the combinations that share an outcome regardless of
+	// the remaining leaves into a single row, even if the full table has no
+	// more than MaxRows rows.
+	Prune bool
+}
+
+// TruthTable lists the outcome of a rule for combinations of the outcomes of
+// its leaves (see NewTruthTable).
+type TruthTable struct {
+	// Rule is the name of the rule.
+	Rule string
+
+	// Leaves holds the names of the leaf rules, one column each.
+	Leaves []string
+
+	// Rows holds the combinations of leaf outcomes and the resulting
+	// outcome of the rule.
+	Rows []TruthTableRow
+
+	// Pruned reports whether the rows were pruned, so that leaves whose
+	// outcome does not matter in a row are TruthUnknown.
+	Pruned bool
+}
+
+// TruthTableRow is a combination of leaf outcomes in a truth table.
+type TruthTableRow struct {
+	// Leaves holds the outcome of each leaf, in the order of
+	// TruthTable.Leaves. In pruned tables, TruthUnknown marks a leaf whose
+	// outcome does not matter.
+	Leaves []Truth
+
+	// Outcome is the outcome of the rule.
+	Outcome bool
+}
+
+// NewTruthTable enumerates the combinations of outcomes of the leaves of a
+// rule and the resulting outcome, e.g. for the sign-off of a composite rule.
+// Leaves are treated as independent boolean variables; their predicates are
+// never called. A leaf that appears more than once in the tree is a single
+// column.
+//
+// And, Or, Not, quantifier, mapped and custom composite rules are expanded,
+// Always and Never are constants, and every other rule (e.g. references,
+// collection rules and decision tables) is a leaf. Severities are honored;
+// validity windows are ignored, as if every rule were in effect.
+//
+// The full table has 2^n rows for n leaves. If that exceeds
+// TruthTableOptions.MaxRows, or pruning is requested, the table is pruned:
+// leaves are assigned true before false, starting with the first leaf that
+// can still change the outcome on its own, and a row ends as soon as the
+// outcome no longer depends on the remaining leaves. It returns
+// ErrTruthTableTooLarge if even the pruned table has more than MaxRows rows.
+//
+// Example:
+//
+//	table, err := rules.NewTruthTable(eligibility, rules.TruthTableOptions{})
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Print(table.Markdown())
+func NewTruthTable[T any](rule Rule[T], opts TruthTableOptions) (*TruthTable, error) {
+	return buildTruthTable(rule, opts)
+}
+
+// buildTruthTable builds the truth table of an untyped rule.
+func buildTruthTable(rule any, opts TruthTableOptions) (*TruthTable, error) {
+	name := getRuleName(rule)
+	if isNilRule(rule) {
+		return nil, fmt.Errorf("building truth table: %w", ErrNilRule)
+	}
+
+	b := &truthTableBuilder{
+		leaves:  make(map[ruleIdentity]int),
+		maxRows: opts.MaxRows,
+	}
+	if b.maxRows <= 0 {
+		b.maxRows = DefaultMaxTruthTableRows
+	}
+
+	root, err := b.node(rule)
+	if err != nil {
+		return nil, fmt.Errorf("building truth table of %q: %w", name, err)
+	}
+
+	b.table = &TruthTable{
+		Rule:   name,
+		Leaves: b.names,
+		Pruned: opts.Prune || len(b.names) >= 31 || 1<<len(b.names) > b.maxRows,
+	}
+	if err := b.enumerate(root, make([]Truth, len(b.names))); err != nil {
+		return nil, fmt.Errorf("building truth table of %q: %w", name, err)
+	}
+	return b.table, nil
+}
+
+// Markdown renders the truth table as a Markdown table.
+func (t *TruthTable) Markdown() string {
+	var sb strings.Builder
+	writeMarkdownTable(&sb, t.header(), t.rows())
+	return sb.String()
+}
+
+// HTML renders the truth table as an HTML table.
+func (t *TruthTable) HTML() string {
+	var sb strings.Builder
+	writeHTMLTable(&sb, t.caption(), t.header(), t.rows())
+	return sb.String()
+}
+
+// CSV renders the truth table as CSV with a header row.
+func (t *TruthTable) CSV() string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	// Writing to a strings.Builder cannot fail
+	_ = w.WriteAll(append([][]string{t.header()}, t.rows()...))
+	return sb.String()
+}
+
+// caption returns the caption of the rendered table.
+func (t *TruthTable) caption() string {
+	if t.Pruned {
+		return "Truth table (- = any outcome)"
+	}
+	return "Truth table"
+}
+
+// header returns the column headers: the leaves and the rule.
+func (t *TruthTable) header() []string {
+	header := make([]string, 0, len(t.Leaves)+1)
+	header = append(header, t.Leaves...)
+	return append(header, t.Rule)
+}
+
+// rows returns the cells of the rendered table.
+func (t *TruthTable) rows() [][]string {
+	rows := make([][]string, len(t.Rows))
+	for i, row := range t.Rows {
+		cells := make([]string, 0, len(row.Leaves)+1)
+		for _, leaf := range row.Leaves {
+			if leaf == TruthUnknown {
+				cells = append(cells, "-")
+			} else {
+				cells = append(cells, leaf.String())
+			}
+		}
+		rows[i] = append(cells, truthOf(row.Outcome).String())
+	}
+	return rows
+}
+
+// truthTableNode is a rule in the tree a truth table is enumerated from.
+type truthTableNode struct {
+	kind      RuleType
+	leaf      int // column of leaves, -1 for other rules
+	constant  bool
+	value     bool // outcome of constants
+	threshold int
+	severity  Severity
+	combine   func(results []Result, total int) (bool, bool, error)
+	name      string
+	children  []*truthTableNode
+}
+
+// truthTableBuilder collects the leaves of a rule and enumerates their
+// outcomes.
+type truthTableBuilder struct {
+	leaves  map[ruleIdentity]int
+	names   []string
+	maxRows int
+	table   *TruthTable
+}
+
+// node builds the node of a rule and its descendants.
+func (b *truthTableBuilder) node(rule any) (*truthTableNode, error) {
+	structure := unwrapRule(rule)
+	n := &truthTableNode{
+		kind:     getRuleType(structure),
+		leaf:     -1,
+		severity: severityOf(rule),
+		name:     getRuleName(rule),
+	}
+
+	if constant, ok := structure.(interface{ Constant() bool }); ok {
+		n.constant, n.value = true, constant.Constant()
+		return n, nil
+	}
+	if quantifier, ok := structure.(interface{ Threshold() int }); ok {
+		n.threshold = quantifier.Threshold()
+	}
+
+	switch {
+	case n.kind == RuleTypeAnd, n.kind == RuleTypeOr, n.kind == RuleTypeNot, n.kind == RuleTypeMapped, n.kind.isQuantifier():
+	case n.kind == RuleTypeComposite:
+		composite, ok := structure.(interface {
+			Combine(results []Result, total int) (bool, bool, error)
+		})
+		if !ok {
+			return b.leaf(n, structure), nil
+		}
+		n.combine = composite.Combine
+	default:
+		return b.leaf(n, structure), nil
+	}
+
+	children := getChildren(structure)
+	if (n.kind == RuleTypeNot || n.kind == RuleTypeMapped) && len(children) != 1 {
+		return b.leaf(n, structure), nil
+	}
+	if len(children) == 0 && (n.kind == RuleTypeAnd || n.kind == RuleTypeOr || n.kind == RuleTypeNoneOf) {
+		return nil, fmt.Errorf("%s rule %q: %w", n.kind, n.name, ErrEmptyRules)
+	}
+	for _, child := range children {
+		if isNilRule(child) {
+			// As in evaluation, only NoneOf fails on nil children
+			if n.kind.isQuantifier() && n.kind != RuleTypeNoneOf {
+				continue
+			}
+			return nil, fmt.Errorf("%s rule %q: %w", n.kind, n.name, ErrNilRule)
+		}
+		childNode, err := b.node(child)
+		if err != nil {
+			return nil, err
+		}
+		n.children = append(n.children, childNode)
+	}
+	return n, nil
+}
+
+// leaf makes a node a leaf, reusing the column of a rule that already
+// appeared in the tree.
+func (b *truthTableBuilder) leaf(n *truthTableNode, rule any) *truthTableNode {
+	id, ok := identify(rule)
+	if column, seen := b.leaves[id]; ok && seen {
+		n.leaf = column
+		return n
+	}
+
+	n.leaf = len(b.names)
+	b.names = append(b.names, n.name)
+	if ok {
+		b.leaves[id] = n.leaf
+	}
+	return n
+}
+
+// enumerate adds the rows for every outcome of the unassigned leaves,
+// given the outcomes assigned so far.
+func (b *truthTableBuilder) enumerate(root *truthTableNode, assignment []Truth) error {
+	outcome := root.evaluate(assignment)
+	next := b.nextLeaf(root, assignment)
+	if next < 0 && !outcome.Known() {
+		// Only custom composites that fail to combine their children leave
+		// the outcome open
+		return fmt.Errorf("combining child outcomes: %w", ErrEvaluationFailed)
+	}
+	if next < 0 || b.table.Pruned && outcome.Known() {
+		if len(b.table.Rows) == b.maxRows {
+			return fmt.Errorf("more than %d rows: %w", b.maxRows, ErrTruthTableTooLarge)
+		}
+		b.table.Rows = append(b.table.Rows, TruthTableRow{
+			Leaves:  append([]Truth(nil), assignment...),
+			Outcome: outcome == TruthTrue,
+		})
+		return nil
+	}
+
+	for _, value := range []Truth{TruthTrue, TruthFalse} {
+		assignment[next] = value
+		if err := b.enumerate(root, assignment); err != nil {
+			return err
+		}
+	}
+	assignment[next] = TruthUnknown
+	return nil
+}
+
+// nextLeaf returns the column of the next leaf to assign, or -1 if all
+// are assigned. Pruned tables skip leaves that no longer affect the
+// outcome on their own, e.g. the siblings of a false child of an AND, so
+// they do not split rows.
+func (b *truthTableBuilder) nextLeaf(root *truthTableNode, assignment []Truth) int {
+	first := -1
+	for i, value := range assignment {
+		if value != TruthUnknown {
+			continue
+		}
+		if !b.table.Pruned {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+
+		assignment[i] = TruthTrue
+		ifTrue := root.evaluate(assignment)
+		assignment[i] = TruthFalse
+		ifFalse := root.evaluate(assignment)
+		assignment[i] = TruthUnknown
+		if ifTrue != ifFalse {
+			return i
+		}
+	}
+	return first
+}
+
+// evaluate returns the outcome of a node in three-valued logic, where
+// unassigned leaves are unknown (see EvaluateTruth).
+func (n *truthTableNode) evaluate(assignment []Truth) Truth {
+	switch {
+	case n.leaf >= 0:
+		return assignment[n.leaf]
+	case n.constant:
+		return truthOf(n.value)
+	}
+
+	switch {
+	case n.kind == RuleTypeAnd:
+		unknown := false
+		for _, child := range n.children {
+			if !child.severity.isBlocking() {
+				continue
+			}
+			switch child.evaluate(assignment) {
+			case TruthFalse:
+				return TruthFalse
+			case TruthUnknown:
+				unknown = true
+			}
+		}
+		if unknown {
+			return TruthUnknown
+		}
+		return TruthTrue
+	case n.kind == RuleTypeOr:
+		unknown := false
+		for _, child := range n.children {
+			switch child.evaluate(assignment) {
+			case TruthTrue:
+				return TruthTrue
+			case TruthUnknown:
+				unknown = true
+			}
+		}
+		if unknown {
+			return TruthUnknown
+		}
+		return TruthFalse
+	case n.kind == RuleTypeNot:
+		switch n.children[0].evaluate(assignment) {
+		case TruthTrue:
+			return TruthFalse
+		case TruthFalse:
+			return TruthTrue
+		default:
+			return TruthUnknown
+		}
+	case n.kind == RuleTypeMapped:
+		return n.children[0].evaluate(assignment)
+	case n.kind.isQuantifier():
+		satisfied, unknown := 0, 0
+		for _, child := range n.children {
+			switch child.evaluate(assignment) {
+			case TruthTrue:
+				satisfied++
+			case TruthUnknown:
+				unknown++
+			}
+		}
+		outcome := quantifierOutcome(n.kind, n.threshold, satisfied)
+		for count := satisfied + 1; count <= satisfied+unknown; count++ {
+			if quantifierOutcome(n.kind, n.threshold, count) != outcome {
+				return TruthUnknown
+			}
+		}
+		return truthOf(outcome)
+	default:
+		return n.evaluateComposite(assignment)
+	}
+}
+
+// evaluateComposite combines the outcomes of the children of a custom
+// composite, which is unknown while any child is or if combining fails.
+func (n *truthTableNode) evaluateComposite(assignment []Truth) Truth {
+	results := make([]Result, len(n.children))
+	for i, child := range n.children {
+		truth := child.evaluate(assignment)
+		if truth == TruthUnknown {
+			return TruthUnknown
+		}
+		results[i] = Result{RuleName: child.name, Satisfied: truth == TruthTrue, Severity: child.severity}
+	}
+
+	satisfied, _, err := n.combine(results, len(results))
+	if err != nil {
+		return TruthUnknown
+	}
+	return truthOf(satisfied)
+}
+
+// GenerateTruthTables builds the truth tables of the registered composite
+// rules that match the filters of the document options, e.g. to export them
+// as CSV. Rules whose table cannot be built, e.g. because it would have
+// more than MaxTruthTableRows rows, are left out.
+func GenerateTruthTables(opts DocumentOptions) []*TruthTable {
+	var tables []*TruthTable
+	for _, regRule := range filterRegisteredRules(AllRules(), opts) {
+		if table, err := documentTruthTable(regRule.Rule, opts); err == nil && table != nil {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// documentTruthTable builds the truth table of a documented rule. It
+// returns nil for rules that do not combine other rules.
+func documentTruthTable(rule any, opts DocumentOptions) (*TruthTable, error) {
+	kind := getRuleType(unwrapRule(rule))
+	if !isCompositeKind(kind) && kind != RuleTypeNot {
+		return nil, nil
+	}
+
+	table, err := buildTruthTable(rule, TruthTableOptions{MaxRows: opts.MaxTruthTableRows})
+	if errors.Is(err, ErrTruthTableTooLarge) {
+		maxRows := opts.MaxTruthTableRows
+		if maxRows <= 0 {
+			maxRows = DefaultMaxTruthTableRows
+		}
+		return nil, fmt.Errorf("more than %d rows", maxRows)
+	}
+	return table, err
+}
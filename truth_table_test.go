@@ -0,0 +1,196 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// leafRules returns n leaf rules named a, b, c, ...
+func leafRules(n int) []Rule[testInput] {
+	leaves := make([]Rule[testInput], n)
+	for i := range leaves {
+		leaves[i] = New(string(rune('a'+i)), func(testInput) (bool, error) { return true, nil })
+	}
+	return leaves
+}
+
+// truthTableCells renders the rows of a truth table as strings like
+// "T-F=T".
+func truthTableCells(table *TruthTable) []string {
+	symbols := map[Truth]string{TruthTrue: "T", TruthFalse: "F", TruthUnknown: "-"}
+	rows := make([]string, len(table.Rows))
+	for i, row := range table.Rows {
+		var sb strings.Builder
+		for _, leaf := range row.Leaves {
+			sb.WriteString(symbols[leaf])
+		}
+		sb.WriteString("=" + symbols[truthOf(row.Outcome)])
+		rows[i] = sb.String()
+	}
+	return rows
+}
+
+func TestNewTruthTable(t *testing.T) {
+	t.Parallel()
+
+	leaves := leafRules(3)
+	a, b, c := leaves[0], leaves[1], leaves[2]
+
+	tests := []struct {
+		name       string
+		rule       Rule[testInput]
+		opts       TruthTableOptions
+		wantLeaves string
+		want       []string
+		wantPruned bool
+	}{
+		{
+			name:       "and",
+			rule:       And("and", a, b),
+			wantLeaves: "ab",
+			want:       []string{"TT=T", "TF=F", "FT=F", "FF=F"},
+		},
+		{
+			name:       "or of not",
+			rule:       Or("or", a, Not("not b", b)),
+			wantLeaves: "ab",
+			want:       []string{"TT=T", "TF=T", "FT=F", "FF=T"},
+		},
+		{
+			name:       "shared leaf and constant",
+			rule:       And("and", a, Or("or", a, Never[testInput]("never"))),
+			wantLeaves: "a",
+			want:       []string{"T=T", "F=F"},
+		},
+		{
+			name:       "non-blocking child",
+			rule:       And("and", a, WithSeverity(b, SeverityWarning)),
+			wantLeaves: "ab",
+			want:       []string{"TT=T", "TF=T", "FT=F", "FF=F"},
+		},
+		{
+			name:       "exactly",
+			rule:       Exactly("exactly", 1, a, b),
+			wantLeaves: "ab",
+			want:       []string{"TT=F", "TF=T", "FT=T", "FF=F"},
+		},
+		{
+			name:       "pruned",
+			rule:       Or("or", And("and", a, b), c),
+			opts:       TruthTableOptions{Prune: true},
+			wantLeaves: "abc",
+			want:       []string{"--T=T", "TTF=T", "TFF=F", "F-F=F"},
+			wantPruned: true,
+		},
+		{
+			name:       "pruned at least",
+			rule:       AtLeast("at least", 2, a, b, c),
+			opts:       TruthTableOptions{Prune: true},
+			wantLeaves: "abc",
+			want:       []string{"TT-=T", "TFT=T", "TFF=F", "FTT=T", "FTF=F", "FF-=F"},
+			wantPruned: true,
+		},
+		{
+			name:       "pruned beyond max rows",
+			rule:       And("and", a, b, c),
+			opts:       TruthTableOptions{MaxRows: 4},
+			wantLeaves: "abc",
+			want:       []string{"TTT=T", "TTF=F", "TF-=F", "F--=F"},
+			wantPruned: true,
+		},
+		{
+			name:       "leaf",
+			rule:       a,
+			wantLeaves: "a",
+			want:       []string{"T=T", "F=F"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			table, err := NewTruthTable(tt.rule, tt.opts)
+			if err != nil {
+				t.Fatalf("NewTruthTable() error = %v", err)
+			}
+			if table.Rule != tt.rule.Name() || strings.Join(table.Leaves, "") != tt.wantLeaves || table.Pruned != tt.wantPruned {
+				t.Errorf("Unexpected table: %q, leaves %v, pruned %v", table.Rule, table.Leaves, table.Pruned)
+			}
+			if got := truthTableCells(table); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTruthTable_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTruthTable(Exactly("exactly", 5, leafRules(10)...), TruthTableOptions{MaxRows: 16}); !errors.Is(err, ErrTruthTableTooLarge) {
+		t.Errorf("Expected ErrTruthTableTooLarge, got %v", err)
+	}
+	if _, err := NewTruthTable(NoneOf("none", leafRules(1)[0], nil), TruthTableOptions{}); !errors.Is(err, ErrNilRule) {
+		t.Errorf("Expected ErrNilRule, got %v", err)
+	}
+}
+
+func TestTruthTable_Renderings(t *testing.T) {
+	t.Parallel()
+
+	leaves := leafRules(2)
+	table, err := NewTruthTable(Or("a or b", leaves...), TruthTableOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("NewTruthTable() error = %v", err)
+	}
+
+	if md := table.Markdown(); !strings.Contains(md, "| a | b | a or b |") || !strings.Contains(md, "| true | - | true |") {
+		t.Errorf("Unexpected Markdown:\n%s", md)
+	}
+	if html := table.HTML(); !strings.Contains(html, "<th>a or b</th>") || !strings.Contains(html, "<td>false</td><td>false</td><td>false</td>") {
+		t.Errorf("Unexpected HTML:\n%s", html)
+	}
+	if csv := table.CSV(); csv != "a,b,a or b\ntrue,-,true\nfalse,true,true\nfalse,false,false\n" {
+		t.Errorf("Unexpected CSV:\n%s", csv)
+	}
+}
+
+func TestTruthTableDocumentation(t *testing.T) {
+	DefaultRegistry.Clear()
+	defer DefaultRegistry.Clear()
+
+	leaves := leafRules(10)
+	_ = Register(And("eligible", leaves[0], Not("not blocked", leaves[1])), WithDomain(TestOrderDomain))
+	_ = Register(Exactly("balanced", 5, leaves...), WithDomain(TestOrderDomain))
+	_ = Register(leaves[2], WithDomain(TestOrderDomain))
+
+	opts := DocumentOptions{IncludeTruthTables: true, MaxTruthTableRows: 64}
+	md, err := GenerateMarkdown(opts)
+	if err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"**Truth table:**",
+		"| a | b | eligible |",
+		"| true | false | true |",
+		"*Omitted: more than 64 rows*",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, md)
+		}
+	}
+
+	htmlDoc, err := GenerateHTML(opts)
+	if err != nil {
+		t.Fatalf("GenerateHTML() error = %v", err)
+	}
+	if !strings.Contains(htmlDoc, "<th>eligible</th>") || !strings.Contains(htmlDoc, "omitted: more than 64 rows") {
+		t.Error("HTML should contain the truth tables")
+	}
+
+	tables := GenerateTruthTables(opts)
+	if len(tables) != 1 || tables[0].Rule != "eligible" || len(tables[0].Rows) != 4 {
+		t.Errorf("Unexpected truth tables: %+v", tables)
+	}
+}
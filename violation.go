@@ -0,0 +1,198 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+)
+
+// Violation describes a single reason why a rule was not satisfied.
+// Violations are collected by the Evaluator into the Result tree and can be
+// retrieved as a flat list with Result.Violations.
+type Violation struct {
+	// Rule is the name of the rule that reported the violation.
+	// The Evaluator fills it in when it is left empty.
+	Rule string
+
+	// Code is a stable, machine-readable identifier (e.g. "MIN_AMOUNT").
+	Code string
+
+	// Message is a human-readable explanation of the violation.
+	Message string
+
+	// Field is the path of the offending field (e.g. "items[2].quantity").
+	Field string
+
+	// Actual is the value that was found.
+	Actual any
+
+	// Expected is the value or constraint that was expected.
+	Expected any
+}
+
+// String returns a string representation of the violation.
+func (v Violation) String() string {
+	s := v.Message
+	if v.Code != "" {
+		s = fmt.Sprintf("[%s] %s", v.Code, s)
+	}
+	if v.Field != "" {
+		s = fmt.Sprintf("%s: %s", v.Field, s)
+	}
+	if v.Actual != nil || v.Expected != nil {
+		s = fmt.Sprintf("%s (actual: %v, expected: %v)", s, v.Actual, v.Expected)
+	}
+	return s
+}
+
+// ValidationFunc is a function that checks an input and reports every
+// violation it finds. An input without violations satisfies the rule.
+type ValidationFunc[T any] func(input T) ([]Violation, error)
+
+// validationRule is a leaf rule that reports structured violations.
+type validationRule[T any] struct {
+	name     string
+	validate ValidationFunc[T]
+}
+
+// NewValidation creates a rule from a validation function. The rule is
+// satisfied when the function reports no violations, and the reported
+// violations are included in detailed evaluation results.
+//
+// Example:
+//
+//	minAmount := rules.NewValidation("minimum amount",
+//	    func(o Order) ([]rules.Violation, error) {
+//	        if o.Amount >= 100 {
+//	            return nil, nil
+//	        }
+//	        return []rules.Violation{{
+//	            Code:     "MIN_AMOUNT",
+//	            Message:  "order amount is below the minimum",
+//	            Field:    "amount",
+//	            Actual:   o.Amount,
+//	            Expected: ">= 100",
+//	        }}, nil
+//	    },
+//	)
+func NewValidation[T any](name string, validate ValidationFunc[T]) Rule[T] {
+	return &validationRule[T]{
+		name:     name,
+		validate: validate,
+	}
+}
+
+func (r *validationRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *validationRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	violations, err := r.violations(ctx, input)
+	if err != nil {
+		return false, err
+	}
+
+	return len(violations) == 0, nil
+}
+
+func (r *validationRule[T]) Name() string {
+	return r.name
+}
+
+// violations runs the validation function and wraps any error it returns.
+func (r *validationRule[T]) violations(ctx context.Context, input T) ([]Violation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"evaluating rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	violations, err := r.validate(input)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"evaluating rule %q: %w",
+			r.name,
+			err,
+		)
+	}
+
+	return violations, nil
+}
+
+// violationRule decorates a rule with a violation that is reported whenever
+// the rule is not satisfied.
+type violationRule[T any] struct {
+	rule      Rule[T]
+	violation Violation
+}
+
+// WithViolation wraps a rule so that the given violation is reported in
+// detailed evaluation results whenever the rule is not satisfied.
+// The wrapped rule keeps its name, structure and evaluation semantics.
+//
+// Example:
+//
+//	minAmount := rules.WithViolation(
+//	    rules.New("minimum amount", func(o Order) (bool, error) {
+//	        return o.Amount >= 100, nil
+//	    }),
+//	    rules.Violation{Code: "MIN_AMOUNT", Field: "amount", Message: "must be at least 100"},
+//	)
+func WithViolation[T any](rule Rule[T], violation Violation) Rule[T] {
+	return &violationRule[T]{
+		rule:      rule,
+		violation: violation,
+	}
+}
+
+func (r *violationRule[T]) Evaluate(input T) (bool, error) {
+	return r.EvaluateContext(context.Background(), input)
+}
+
+func (r *violationRule[T]) EvaluateContext(ctx context.Context, input T) (bool, error) {
+	if r.rule == nil {
+		return false, fmt.Errorf("evaluating rule %q: %w", r.Name(), ErrNilRule)
+	}
+
+	return EvaluateContext(ctx, r.rule, input)
+}
+
+func (r *violationRule[T]) Name() string {
+	if r.rule == nil {
+		return "unnamed"
+	}
+	return r.rule.Name()
+}
+
+// Unwrap returns the wrapped rule.
+func (r *violationRule[T]) Unwrap() Rule[T] {
+	return r.rule
+}
+
+// unwrapRule returns the wrapped rule for introspection.
+func (r *violationRule[T]) unwrapRule() any {
+	return r.rule
+}
+
+// wrappingRule is implemented by rules that decorate another rule without
+// changing its structure. Introspection (documentation, domain inheritance)
+// looks through such rules to the rule they wrap.
+type wrappingRule interface {
+	unwrapRule() any
+}
+
+// unwrapRule returns the innermost rule wrapped by decorating rules.
+func unwrapRule(rule any) any {
+	for {
+		wrapper, ok := rule.(wrappingRule)
+		if !ok {
+			return rule
+		}
+		inner := wrapper.unwrapRule()
+		if inner == nil {
+			return rule
+		}
+		rule = inner
+	}
+}
@@ -0,0 +1,238 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewValidation(t *testing.T) {
+	t.Parallel()
+
+	rule := NewValidation("value in range", func(input testInput) ([]Violation, error) {
+		var violations []Violation
+		if input.value < 10 {
+			violations = append(violations, Violation{
+				Code:     "TOO_SMALL",
+				Message:  "value is too small",
+				Field:    "value",
+				Actual:   input.value,
+				Expected: ">= 10",
+			})
+		}
+		if !input.valid {
+			violations = append(violations, Violation{
+				Code:    "INVALID",
+				Message: "input is not valid",
+				Field:   "valid",
+			})
+		}
+		return violations, nil
+	})
+
+	t.Run("satisfied without violations", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := rule.Evaluate(testInput{value: 10, valid: true})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if !got {
+			t.Error("Evaluate() = false, want true")
+		}
+	})
+
+	t.Run("violations are collected by the evaluator", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailed(testInput{value: 5})
+		if result.Satisfied {
+			t.Fatal("Expected rule to not be satisfied")
+		}
+
+		violations := result.Violations()
+		if len(violations) != 2 {
+			t.Fatalf("Expected 2 violations, got %d", len(violations))
+		}
+		if violations[0].Code != "TOO_SMALL" || violations[1].Code != "INVALID" {
+			t.Errorf("Unexpected violation codes: %v", violations)
+		}
+		if violations[0].Rule != "value in range" {
+			t.Errorf("Expected rule name to be filled in, got %q", violations[0].Rule)
+		}
+		if violations[0].Actual != 5 {
+			t.Errorf("Expected actual value 5, got %v", violations[0].Actual)
+		}
+	})
+
+	t.Run("errors are wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		errBoom := errors.New("boom")
+		failing := NewValidation("failing", func(input testInput) ([]Violation, error) {
+			return nil, errBoom
+		})
+
+		_, err := failing.Evaluate(testInput{})
+		if !errors.Is(err, errBoom) {
+			t.Errorf("Evaluate() error = %v, want %v", err, errBoom)
+		}
+	})
+}
+
+func TestWithViolation(t *testing.T) {
+	t.Parallel()
+
+	minimum := WithViolation(
+		New("minimum value", func(input testInput) (bool, error) {
+			return input.value >= 10, nil
+		}),
+		Violation{Code: "MIN_VALUE", Field: "value", Message: "must be at least 10"},
+	)
+	valid := WithViolation(
+		New("valid", func(input testInput) (bool, error) {
+			return input.valid, nil
+		}),
+		Violation{Code: "INVALID", Field: "valid", Message: "must be valid"},
+	)
+
+	rule := And("checks", minimum, valid)
+
+	t.Run("keeps name and semantics", func(t *testing.T) {
+		t.Parallel()
+
+		if minimum.Name() != "minimum value" {
+			t.Errorf("Name() = %q, want %q", minimum.Name(), "minimum value")
+		}
+
+		got, err := rule.Evaluate(testInput{value: 10, valid: true})
+		if err != nil || !got {
+			t.Errorf("Evaluate() = %v, %v; want true, nil", got, err)
+		}
+	})
+
+	t.Run("reports violations of failed children only", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailed(testInput{value: 5, valid: true})
+
+		violations := result.Violations()
+		if len(violations) != 1 {
+			t.Fatalf("Expected 1 violation, got %v", violations)
+		}
+		if violations[0].Code != "MIN_VALUE" || violations[0].Rule != "minimum value" {
+			t.Errorf("Unexpected violation: %+v", violations[0])
+		}
+
+		if len(result.Children) != 2 || result.Children[0].RuleName != "minimum value" {
+			t.Errorf("Expected wrapper to be transparent in the result tree, got %v", result.Children)
+		}
+	})
+
+	t.Run("satisfied OR hides violations of failed alternatives", func(t *testing.T) {
+		t.Parallel()
+
+		either := Or("either", minimum, valid)
+		result := NewEvaluator(either).EvaluateDetailed(testInput{value: 5, valid: true})
+
+		if !result.Satisfied {
+			t.Fatal("Expected rule to be satisfied")
+		}
+		if violations := result.Violations(); len(violations) != 0 {
+			t.Errorf("Expected no violations, got %v", violations)
+		}
+	})
+
+	t.Run("string output includes violations", func(t *testing.T) {
+		t.Parallel()
+
+		result := NewEvaluator(rule).EvaluateDetailed(testInput{value: 5, valid: false})
+		output := result.String()
+
+		if !strings.Contains(output, "value: [MIN_VALUE] must be at least 10") {
+			t.Errorf("Expected violation in output, got:\n%s", output)
+		}
+	})
+}
+
+func TestViolations_NotShared(t *testing.T) {
+	t.Parallel()
+
+	// The validation function returns a slice with spare capacity
+	reported := make([]Violation, 1, 2)
+	reported[0] = Violation{Code: "REPORTED"}
+	validation := NewValidation("validation", func(testInput) ([]Violation, error) {
+		return reported[:1], nil
+	})
+	rule := And("root",
+		WithViolation(validation, Violation{Code: "FIRST"}),
+		WithViolation(validation, Violation{Code: "SECOND"}),
+	)
+
+	result := NewEvaluator(rule, WithMemoization()).EvaluateDetailed(testInput{})
+	if len(result.Children) != 2 || !result.Children[1].Cached {
+		t.Fatalf("EvaluateDetailed() = %v", result)
+	}
+	for i, want := range []string{"FIRST", "SECOND"} {
+		violations := result.Children[i].RuleViolations
+		if len(violations) != 2 || violations[0].Rule != "validation" || violations[1].Code != want {
+			t.Errorf("Unexpected violations of child %d: %+v", i, violations)
+		}
+	}
+	if reported := reported[:2]; reported[0].Rule != "" || reported[1].Code != "" {
+		t.Errorf("The slice of the validation function was modified: %+v", reported)
+	}
+}
+
+func TestWrappers_NilRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rule Rule[testInput]
+	}{
+		{name: "violation", rule: WithViolation[testInput](nil, Violation{Code: "CODE"})},
+		{name: "severity", rule: WithSeverity[testInput](nil, SeverityWarning)},
+		{name: "effective", rule: Effective[testInput](nil, time.Time{}, time.Time{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Every evaluation path reports the same wrapped error
+			const want = `evaluating rule "unnamed": nil rule`
+			_, err := tt.rule.Evaluate(testInput{})
+			if !errors.Is(err, ErrNilRule) || err.Error() != want {
+				t.Errorf("Evaluate() error = %v, want %q", err, want)
+			}
+			_, err = Compile(tt.rule).Evaluate(testInput{})
+			if !errors.Is(err, ErrNilRule) || err.Error() != want {
+				t.Errorf("compiled Evaluate() error = %v, want %q", err, want)
+			}
+			_, err = EvaluateTruth(context.Background(), tt.rule, testInput{})
+			if !errors.Is(err, ErrNilRule) || err.Error() != want {
+				t.Errorf("EvaluateTruth() error = %v, want %q", err, want)
+			}
+		})
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	t.Parallel()
+
+	v := Violation{
+		Code:     "MIN_AMOUNT",
+		Message:  "amount too low",
+		Field:    "amount",
+		Actual:   50,
+		Expected: ">= 100",
+	}
+
+	want := "amount: [MIN_AMOUNT] amount too low (actual: 50, expected: >= 100)"
+	if got := v.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}